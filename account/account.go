@@ -0,0 +1,213 @@
+// Package account stores player accounts and authenticates logins.
+package account
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/mud/models"
+)
+
+var (
+	ErrNotFound         = errors.New("account does not exist")
+	ErrExists           = errors.New("account already exists")
+	ErrBadCredentials   = errors.New("incorrect name or password")
+	ErrPasswordTooShort = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrAlreadyLoggedIn  = errors.New("already logged in")
+	ErrBanned           = errors.New("account is banned")
+)
+
+const MinPasswordLength = 6
+
+type Account struct {
+	Name         string      `json:"name"`
+	PasswordHash string      `json:"passwordHash"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	LastLogin    time.Time   `json:"lastLogin"`
+	PublicKeys   []string    `json:"publicKeys,omitempty"` // authorized_keys lines, for SSH logins
+	Role         models.Role `json:"role,omitempty"`       // empty for players
+	Banned       bool        `json:"banned,omitempty"`
+}
+
+// Store persists accounts. Names are matched case-insensitively; Get returns
+// ErrNotFound for unknown names.
+type Store interface {
+	Get(name string) (*Account, error)
+	Put(a *Account) error
+}
+
+// Manager creates and authenticates accounts and makes sure each account has
+// at most one live session.
+type Manager struct {
+	store Store
+
+	mu     sync.Mutex
+	online map[string]struct{} // lower-case name
+}
+
+func NewManager(store Store) *Manager {
+	return &Manager{
+		store:  store,
+		online: make(map[string]struct{}),
+	}
+}
+
+// Exists reports whether an account with the given name has been created.
+func (m *Manager) Exists(name string) (bool, error) {
+	_, err := m.store.Get(name)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("look up account '%s': %w", name, err)
+	}
+	return true, nil
+}
+
+// Create registers a new account with the given password.
+func (m *Manager) Create(name, password string) (*Account, error) {
+	if len(password) < MinPasswordLength {
+		return nil, ErrPasswordTooShort
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	exists, err := m.Exists(name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrExists
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("create account '%s': %w", name, err)
+	}
+
+	now := time.Now()
+	a := &Account{
+		Name:         name,
+		PasswordHash: hash,
+		CreatedAt:    now,
+		LastLogin:    now,
+	}
+	if err := m.store.Put(a); err != nil {
+		return nil, fmt.Errorf("create account '%s': %w", name, err)
+	}
+	return a, nil
+}
+
+// Authenticate checks a password against the stored account, and records
+// the login.
+func (m *Manager) Authenticate(name, password string) (*Account, error) {
+	a, err := m.CheckPassword(name, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.RecordLogin(a.Name); err != nil {
+		return nil, fmt.Errorf("authenticate '%s': %w", name, err)
+	}
+	return a, nil
+}
+
+// CheckPassword checks a password against the stored account without
+// recording a login, for logins that can still fail afterwards.
+func (m *Manager) CheckPassword(name, password string) (*Account, error) {
+	a, err := m.store.Get(name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrBadCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("authenticate '%s': %w", name, err)
+	}
+
+	ok, err := VerifyPassword(a.PasswordHash, password)
+	if err != nil {
+		return nil, fmt.Errorf("authenticate '%s': %w", name, err)
+	}
+	if !ok {
+		return nil, ErrBadCredentials
+	}
+	if a.Banned {
+		return nil, ErrBanned
+	}
+	return a, nil
+}
+
+// RecordLogin notes that the account has just logged in.
+func (m *Manager) RecordLogin(name string) error {
+	return m.update(name, func(a *Account) { a.LastLogin = time.Now() })
+}
+
+// Acquire claims the live session for an account. The returned func releases
+// it and must be called when the session ends.
+func (m *Manager) Acquire(name string) (func(), error) {
+	key := strings.ToLower(name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.online[key]; ok {
+		return nil, ErrAlreadyLoggedIn
+	}
+	m.online[key] = struct{}{}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.online, key)
+			m.mu.Unlock()
+		})
+	}, nil
+}
+
+// Role returns what the account is allowed to do.
+func (m *Manager) Role(name string) (models.Role, error) {
+	a, err := m.store.Get(name)
+	if err != nil {
+		return "", fmt.Errorf("role of '%s': %w", name, err)
+	}
+	if a.Role == "" {
+		return models.RolePlayer, nil
+	}
+	return a.Role, nil
+}
+
+// Banned reports whether the account is banned from logging in.
+func (m *Manager) Banned(name string) (bool, error) {
+	a, err := m.store.Get(name)
+	if err != nil {
+		return false, fmt.Errorf("ban status of '%s': %w", name, err)
+	}
+	return a.Banned, nil
+}
+
+// SetRole changes what the account is allowed to do.
+func (m *Manager) SetRole(name string, role models.Role) error {
+	return m.update(name, func(a *Account) { a.Role = role })
+}
+
+// SetBanned bans the account from logging in, or lifts its ban.
+func (m *Manager) SetBanned(name string, banned bool) error {
+	return m.update(name, func(a *Account) { a.Banned = banned })
+}
+
+func (m *Manager) update(name string, change func(a *Account)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, err := m.store.Get(name)
+	if err != nil {
+		return fmt.Errorf("update account '%s': %w", name, err)
+	}
+	change(a)
+	if err := m.store.Put(a); err != nil {
+		return fmt.Errorf("update account '%s': %w", name, err)
+	}
+	return nil
+}
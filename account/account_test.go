@@ -0,0 +1,185 @@
+package account
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"example.com/mud/models"
+)
+
+func TestPassword_HashAndVerify(t *testing.T) {
+	t.Parallel()
+
+	hash, err := HashPassword("hunter22")
+	require.NoError(t, err)
+
+	other, err := HashPassword("hunter22")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other, "hashes of the same password should be salted differently")
+
+	type tc struct {
+		name     string
+		encoded  string
+		password string
+		want     bool
+		wantErr  bool
+	}
+
+	cases := []tc{
+		{name: "correct password", encoded: hash, password: "hunter22", want: true},
+		{name: "wrong password", encoded: hash, password: "hunter23", want: false},
+		{name: "unknown scheme", encoded: "md5$1$abc$def", password: "hunter22", wantErr: true},
+		{name: "malformed", encoded: "not a hash", password: "hunter22", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := VerifyPassword(c.encoded, c.password)
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	m := NewManager(store)
+
+	_, err = m.Create("Alice", "short")
+	require.ErrorIs(t, err, ErrPasswordTooShort)
+
+	_, err = m.Create("Alice", "wonderland")
+	require.NoError(t, err)
+
+	_, err = m.Create("alice", "wonderland")
+	require.ErrorIs(t, err, ErrExists)
+
+	_, err = m.Authenticate("ALICE", "wonderland")
+	require.NoError(t, err)
+
+	_, err = m.Authenticate("Alice", "looking-glass")
+	require.ErrorIs(t, err, ErrBadCredentials)
+
+	_, err = m.Authenticate("Bob", "wonderland")
+	require.ErrorIs(t, err, ErrBadCredentials)
+
+	// names that would reach outside the directory never become file names
+	for _, name := range []string{"../alice", `..\alice`, "sub/alice"} {
+		_, err = m.Create(name, "wonderland")
+		require.ErrorContains(t, err, "invalid account name", name)
+		_, err = m.Authenticate(name, "wonderland")
+		require.ErrorContains(t, err, "invalid account name", name)
+	}
+
+	release, err := m.Acquire("Alice")
+	require.NoError(t, err)
+
+	_, err = m.Acquire("alice")
+	require.ErrorIs(t, err, ErrAlreadyLoggedIn)
+
+	release()
+	release, err = m.Acquire("Alice")
+	require.NoError(t, err)
+	release()
+}
+
+func TestManager_PublicKeys(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	m := NewManager(store)
+	_, err = m.Create("Alice", "wonderland")
+	require.NoError(t, err)
+
+	newKey := func() ssh.PublicKey {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		key, err := ssh.NewPublicKey(pub)
+		require.NoError(t, err)
+		return key
+	}
+	key, other := newKey(), newKey()
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))) + " alice@home"
+
+	require.ErrorIs(t, m.AddPublicKey("Alice", "ssh-rsa nonsense"), ErrBadPublicKey)
+	require.NoError(t, m.AddPublicKey("Alice", line))
+	require.NoError(t, m.AddPublicKey("Alice", line), "adding a key twice is a no-op")
+
+	keys, err := m.PublicKeys("alice")
+	require.NoError(t, err)
+	require.Equal(t, []string{line}, keys)
+
+	before, err := store.Get("Alice")
+	require.NoError(t, err)
+	a, err := m.CheckKey("alice", key)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", a.Name)
+	after, err := store.Get("Alice")
+	require.NoError(t, err)
+	require.Equal(t, before.LastLogin, after.LastLogin, "checking a key doesn't count as logging in")
+
+	_, err = m.CheckKey("Alice", other)
+	require.ErrorIs(t, err, ErrBadCredentials)
+	_, err = m.CheckKey("Bob", key)
+	require.ErrorIs(t, err, ErrBadCredentials)
+
+	require.NoError(t, m.RemovePublicKey("Alice", 0))
+	_, err = m.CheckKey("Alice", key)
+	require.ErrorIs(t, err, ErrBadCredentials)
+}
+
+func TestManager_RolesAndBans(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	m := NewManager(store)
+	_, err = m.Create("Alice", "wonderland")
+	require.NoError(t, err)
+
+	role, err := m.Role("alice")
+	require.NoError(t, err)
+	require.Equal(t, models.RolePlayer, role)
+
+	require.NoError(t, m.SetRole("ALICE", models.RoleBuilder))
+	role, err = m.Role("Alice")
+	require.NoError(t, err)
+	require.Equal(t, models.RoleBuilder, role)
+
+	_, err = m.Role("Bob")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, m.SetBanned("Bob", true), ErrNotFound)
+
+	require.NoError(t, m.SetBanned("Alice", true))
+	_, err = m.Authenticate("Alice", "wonderland")
+	require.ErrorIs(t, err, ErrBanned)
+	_, err = m.Authenticate("Alice", "looking-glass")
+	require.ErrorIs(t, err, ErrBadCredentials, "a ban doesn't give away the password")
+
+	require.NoError(t, m.SetBanned("Alice", false))
+	_, err = m.Authenticate("Alice", "wonderland")
+	require.NoError(t, err)
+
+	// a ban between checking a login and recording it sticks
+	_, err = m.CheckPassword("Alice", "wonderland")
+	require.NoError(t, err)
+	require.NoError(t, m.SetBanned("Alice", true))
+	require.NoError(t, m.RecordLogin("Alice"))
+	_, err = m.CheckPassword("Alice", "wonderland")
+	require.ErrorIs(t, err, ErrBanned)
+}
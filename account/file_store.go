@@ -0,0 +1,83 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"example.com/mud/utils"
+)
+
+// FileStore keeps each account as a JSON file in a directory.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+var _ Store = &FileStore{}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create account directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path returns the file an account is kept in. Names that could reach
+// outside the directory are refused.
+func (s *FileStore) path(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid account name '%s'", name)
+	}
+	return filepath.Join(s.dir, strings.ToLower(name)+".json"), nil
+}
+
+func (s *FileStore) Get(name string) (*Account, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read account: %w", err)
+	}
+
+	var a Account
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("decode account '%s': %w", name, err)
+	}
+	return &a, nil
+}
+
+// Put writes the account to a temporary file and renames it into place, so a
+// crash mid-write never leaves a truncated account behind.
+func (s *FileStore) Put(a *Account) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode account '%s': %w", a.Name, err)
+	}
+	path, err := s.path(a.Name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := utils.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("save account '%s': %w", a.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	hashScheme     = "pbkdf2-sha256"
+	hashIterations = 600_000
+	saltLength     = 16
+	keyLength      = 32
+)
+
+// HashPassword derives a salted hash of the password, encoded as
+// "pbkdf2-sha256$<iterations>$<salt>$<key>".
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, hashIterations, keyLength)
+	if err != nil {
+		return "", fmt.Errorf("derive key: %w", err)
+	}
+
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("%s$%d$%s$%s", hashScheme, hashIterations, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// VerifyPassword reports whether password matches an encoded hash.
+func VerifyPassword(encoded, password string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != hashScheme {
+		return false, fmt.Errorf("unrecognized password hash format")
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false, fmt.Errorf("invalid password hash iterations '%s'", parts[1])
+	}
+
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false, fmt.Errorf("decode salt: %w", err)
+	}
+	want, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return false, fmt.Errorf("decode key: %w", err)
+	}
+
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	if err != nil {
+		return false, fmt.Errorf("derive key: %w", err)
+	}
+
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
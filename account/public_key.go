@@ -0,0 +1,100 @@
+package account
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var ErrBadPublicKey = errors.New("not a public key in authorized_keys format")
+
+// AddPublicKey registers an SSH public key, given as an authorized_keys line,
+// that can log in to the account instead of its password.
+func (m *Manager) AddPublicKey(name, authorizedKey string) error {
+	key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		return ErrBadPublicKey
+	}
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	if comment != "" {
+		line += " " + comment
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, err := m.store.Get(name)
+	if err != nil {
+		return fmt.Errorf("add public key to '%s': %w", name, err)
+	}
+	if hasKey(a, key) {
+		return nil
+	}
+	a.PublicKeys = append(a.PublicKeys, line)
+	if err := m.store.Put(a); err != nil {
+		return fmt.Errorf("add public key to '%s': %w", name, err)
+	}
+	return nil
+}
+
+// RemovePublicKey removes the key at index i of the account's PublicKeys.
+func (m *Manager) RemovePublicKey(name string, i int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	a, err := m.store.Get(name)
+	if err != nil {
+		return fmt.Errorf("remove public key from '%s': %w", name, err)
+	}
+	if i < 0 || i >= len(a.PublicKeys) {
+		return fmt.Errorf("remove public key from '%s': no key %d", name, i+1)
+	}
+	a.PublicKeys = append(a.PublicKeys[:i], a.PublicKeys[i+1:]...)
+	if err := m.store.Put(a); err != nil {
+		return fmt.Errorf("remove public key from '%s': %w", name, err)
+	}
+	return nil
+}
+
+// PublicKeys lists the keys registered to the account.
+func (m *Manager) PublicKeys(name string) ([]string, error) {
+	a, err := m.store.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("list public keys of '%s': %w", name, err)
+	}
+	return a.PublicKeys, nil
+}
+
+// CheckKey checks that key is registered to the account. It doesn't record
+// a login: SSH clients ask which of their keys would do before proving they
+// hold one, so that waits until the handshake is over.
+func (m *Manager) CheckKey(name string, key ssh.PublicKey) (*Account, error) {
+	a, err := m.store.Get(name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrBadCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("authenticate '%s': %w", name, err)
+	}
+	if !hasKey(a, key) {
+		return nil, ErrBadCredentials
+	}
+	if a.Banned {
+		return nil, ErrBanned
+	}
+	return a, nil
+}
+
+func hasKey(a *Account, key ssh.PublicKey) bool {
+	want := key.Marshal()
+	for _, line := range a.PublicKeys {
+		registered, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err == nil && bytes.Equal(registered.Marshal(), want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"example.com/mud/config"
+	"example.com/mud/dsl"
+	orbisplugin "example.com/mud/plugin"
+	pb "example.com/mud/plugin/proto"
+)
+
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: orbis <command> [arguments]
+
+Commands:
+  serve                       start the server with the world from config.yaml (the default)
+  check <dir>                 report every mistake in a directory of .mud files
+  compile <dir> -o <file>     write the world in a directory of .mud files as a game manifest
+  plugin-info <binary>        launch a game plugin and describe its manifest
+
+Run 'orbis <command> -h' for a command's flags.
+`)
+}
+
+func subcommandUsage(flags *flag.FlagSet, synopsis, description string) func() {
+	return func() {
+		fmt.Fprintf(flags.Output(), "Usage: orbis %s\n\n%s\n", synopsis, description)
+		hasFlags := false
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(flags.Output())
+			flags.PrintDefaults()
+		}
+	}
+}
+
+// parseArgs parses flags that may come before or after the positional
+// arguments, like "compile data -o world.pb", and returns the positional ones.
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		if flags.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// check parses and compiles a DSL directory and reports every mistake in it,
+// without starting anything.
+func check(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	flags.Usage = subcommandUsage(flags, "check <dir>", "Parse and compile the .mud files in dir, and report every mistake with its file:line:column.")
+	dirs := parseArgs(flags, args)
+	if len(dirs) != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	entityMap, commands, errs := dsl.Check(dirs[0])
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s found\n", count(len(errs), "mistake"))
+		return 1
+	}
+
+	fmt.Printf("%s is fine: %s and %s\n", dirs[0], count(len(entityMap), "entity"), count(len(commands), "command"))
+	return 0
+}
+
+// compile writes the world declared in a DSL directory as a serialized game
+// manifest.
+func compile(args []string) int {
+	flags := flag.NewFlagSet("compile", flag.ExitOnError)
+	flags.Usage = subcommandUsage(flags, "compile <dir> [-o manifest.pb] [-start room]", "Compile the .mud files in dir and write the world as a serialized game manifest.")
+	out := flags.String("o", "manifest.pb", "file to write the manifest to")
+	start := flags.String("start", "", "the room players start in (default worldSource.startingRoom from config.yaml)")
+	dirs := parseArgs(flags, args)
+	if len(dirs) != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	if *start == "" {
+		if cfg, err := config.Load("config.yaml"); err == nil {
+			*start = cfg.WorldSource.StartingRoom
+		}
+	}
+	if *start == "" {
+		fmt.Fprintln(os.Stderr, "no starting room: pass -start, or set worldSource.startingRoom in config.yaml")
+		return 2
+	}
+
+	entityMap, commands, errs := dsl.Check(dirs[0])
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		fmt.Fprintf(os.Stderr, "%s found\n", count(len(errs), "mistake"))
+		return 1
+	}
+
+	manifest, err := orbisplugin.WorldToManifest(*start, entityMap, commands)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	data, err := proto.Marshal(manifest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "encode manifest: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "write manifest: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Wrote %s (%d bytes)\n", *out, len(data))
+	describeManifest(os.Stdout, manifest)
+	return 0
+}
+
+// pluginInfo launches a game plugin just long enough to get its manifest,
+// and describes it.
+func pluginInfo(args []string) int {
+	flags := flag.NewFlagSet("plugin-info", flag.ExitOnError)
+	flags.Usage = subcommandUsage(flags, "plugin-info <binary>", "Launch a game plugin and describe the world its manifest declares.")
+	binaries := parseArgs(flags, args)
+	if len(binaries) != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	client, kill, err := orbisplugin.Launch(binaries[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer kill()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	manifest, err := client.GetManifest(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "get game manifest: %v\n", err)
+		return 1
+	}
+
+	describeManifest(os.Stdout, manifest)
+	return 0
+}
+
+// describeManifest summarizes the world a manifest declares.
+func describeManifest(w io.Writer, m *pb.GameManifest) {
+	fmt.Fprintf(w, "Starting room: %s\n", m.GetStartingRoom())
+
+	fmt.Fprintf(w, "%s:\n", count(len(m.GetRooms()), "room"))
+	for _, rd := range m.GetRooms() {
+		exits := make([]string, 0, len(rd.Exits))
+		for direction := range rd.Exits {
+			exits = append(exits, direction)
+		}
+		slices.Sort(exits)
+		fmt.Fprintf(w, "  %s %q, exits: %s, %s\n", rd.Id, rd.Name, orNone(exits), count(len(rd.ChildIds), "thing"))
+	}
+
+	fmt.Fprintf(w, "%s:\n", count(len(m.GetEntities()), "entity"))
+	for _, ed := range m.GetEntities() {
+		details := []string{count(len(ed.Fields), "field"), count(len(ed.Reactions), "reaction")}
+		if ed.HasInventory {
+			details = append(details, "inventory")
+		}
+		if ed.HasContainer {
+			details = append(details, "container")
+		}
+		if ed.ContainerId != "" {
+			details = append(details, fmt.Sprintf("in %s's %s", ed.ContainerId, ed.ContainerComponent))
+		}
+		fmt.Fprintf(w, "  %s %q, %s\n", ed.Id, ed.Name, strings.Join(details, ", "))
+	}
+
+	fmt.Fprintf(w, "%s:\n", count(len(m.GetCommands()), "command"))
+	for _, cd := range m.GetCommands() {
+		syntaxes := make([]string, 0, len(cd.Patterns))
+		for _, p := range cd.Patterns {
+			syntaxes = append(syntaxes, p.Syntax)
+		}
+		fmt.Fprintf(w, "  %s: %s\n", cd.Name, orNone(syntaxes))
+	}
+}
+
+func count(n int, noun string) string {
+	switch {
+	case n == 1:
+		return "1 " + noun
+	case strings.HasSuffix(noun, "y"):
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func orNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
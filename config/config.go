@@ -5,12 +5,119 @@ import (
 	"os"
 
 	"gopkg.in/yaml.v3"
+
+	"example.com/mud/world"
+)
+
+const (
+	WorldSourcePlugin = "plugin"
+	WorldSourceDSL    = "dsl"
 )
 
 type Config struct {
-	PlayerRateLimit int    `yaml:"playerRateLimit"`
-	WebSocketPort   int    `yaml:"websocketPort"`
-	GameBinary      string `yaml:"gameBinary"`
+	PlayerRateLimit int         `yaml:"playerRateLimit"`
+	Listeners       []Listener  `yaml:"listeners"`
+	SSH             SSH         `yaml:"ssh"`
+	Sessions        Sessions    `yaml:"sessions"`
+	Outbox          Outbox      `yaml:"outbox"`
+	Chat            Chat        `yaml:"chat"`
+	WorldSource     WorldSource `yaml:"worldSource"`
+	Accounts        Accounts    `yaml:"accounts"`
+	Persistence     Persistence `yaml:"persistence"`
+	Admins          []string    `yaml:"admins"` // account names that are admins whatever their account's role
+}
+
+// WorldSource selects where the engine builds its world from: a compiled game
+// plugin binary, or Orbis Definition Language files loaded directly.
+type WorldSource struct {
+	Type          string `yaml:"type"`
+	GameBinary    string `yaml:"gameBinary"`
+	DataDir       string `yaml:"dataDir"`
+	StartingRoom  string `yaml:"startingRoom"`
+	BuildFile     string `yaml:"buildFile"`     // file in dataDir that rooms and things built in game are added to
+	WatchInterval int    `yaml:"watchInterval"` // seconds between checks of dataDir for changes to reload, 0 disables
+	TickInterval  int    `yaml:"tickInterval"`  // milliseconds between tick updates sent to a plugin, 0 disables
+	Events        Events `yaml:"events"`
+}
+
+// Events configures how the engine waits on a game plugin to handle events,
+// and when it stops asking one that keeps failing. Zero values get the
+// engine's defaults.
+type Events struct {
+	Timeout            int    `yaml:"timeout"`            // milliseconds the plugin has to handle an event
+	BreakerFailures    int    `yaml:"breakerFailures"`    // failed events in a row before the engine stops asking
+	BreakerCooldown    int    `yaml:"breakerCooldown"`    // seconds before it asks again
+	UnavailableMessage string `yaml:"unavailableMessage"` // what players see meanwhile
+}
+
+// Accounts configures where player accounts are stored.
+type Accounts struct {
+	Store string `yaml:"store"` // "file" is the only store so far
+	Dir   string `yaml:"dir"`
+}
+
+const AccountStoreFile = "file"
+
+// Sessions configures what happens when a player's connection drops.
+type Sessions struct {
+	LinkDeadGrace int `yaml:"linkDeadGrace"` // seconds a dropped player stays in the world, 0 removes them at once
+	ReplayLimit   int `yaml:"replayLimit"`   // messages kept for a link-dead player to catch up on, 0 for the default
+}
+
+// Chat configures talking between players. Zero values get the engine's
+// defaults.
+type Chat struct {
+	Channels    []string `yaml:"channels"`    // global channels every player starts out in
+	HistorySize int      `yaml:"historySize"` // lines kept per channel
+	RateLimit   int      `yaml:"rateLimit"`   // milliseconds a player must wait between lines
+	ShoutRange  int      `yaml:"shoutRange"`  // exits a shout carries through
+}
+
+// Outbox configures what happens to messages for a player whose connection
+// can't keep up. Zero values get the engine's defaults.
+type Outbox struct {
+	Policy       string `yaml:"policy"`       // coalesce, disconnect or spill
+	Size         int    `yaml:"size"`         // messages waiting before the policy applies
+	SlowAfter    int    `yaml:"slowAfter"`    // disconnect: milliseconds the queue may stay full
+	SpillSize    int    `yaml:"spillSize"`    // spill: messages kept in the overflow ring buffer
+	DelayedAfter int    `yaml:"delayedAfter"` // milliseconds after which a message counts as delayed
+}
+
+const (
+	ProtocolTelnet    = "telnet"
+	ProtocolTelnetTLS = "telnets"
+	ProtocolWS        = "ws"
+	ProtocolWSS       = "wss"
+	ProtocolSSH       = "ssh"
+)
+
+// Listener is one address the server accepts players on. The TLS protocols,
+// telnets and wss, need a certificate and key, which are reloaded from disk
+// on SIGHUP.
+type Listener struct {
+	Protocol string `yaml:"protocol"`
+	Address  string `yaml:"address"` // host:port, or :port for every interface
+	CertFile string `yaml:"cert"`
+	KeyFile  string `yaml:"key"`
+}
+
+// TLS reports whether the listener's protocol runs over TLS.
+func (l Listener) TLS() bool {
+	return l.Protocol == ProtocolTelnetTLS || l.Protocol == ProtocolWSS
+}
+
+// SSH configures ssh listeners. Players log in with their account password or
+// a public key they've registered in game.
+type SSH struct {
+	HostKey string `yaml:"hostKey"` // private key file, generated on first start if missing
+}
+
+// Persistence configures where game state is saved and how often.
+type Persistence struct {
+	Dir              string `yaml:"dir"`
+	AutosaveInterval int    `yaml:"autosaveInterval"` // seconds between saves of online players, 0 disables
+	SnapshotInterval int    `yaml:"snapshotInterval"` // seconds between world snapshots, 0 disables the timer
+	SnapshotKeep     int    `yaml:"snapshotKeep"`     // number of snapshots kept on disk, 0 keeps all
 }
 
 func Load(path string) (*Config, error) {
@@ -24,5 +131,103 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("unmarshal yaml: %w", err)
 	}
 
+	if err := cfg.WorldSource.validate(); err != nil {
+		return nil, fmt.Errorf("world source: %w", err)
+	}
+
+	if err := cfg.Accounts.validate(); err != nil {
+		return nil, fmt.Errorf("accounts: %w", err)
+	}
+
+	if len(cfg.Listeners) == 0 {
+		return nil, fmt.Errorf("no listeners configured")
+	}
+	for i := range cfg.Listeners {
+		if err := cfg.Listeners[i].validate(); err != nil {
+			return nil, fmt.Errorf("listener %d: %w", i+1, err)
+		}
+	}
+
+	if err := cfg.Outbox.validate(); err != nil {
+		return nil, fmt.Errorf("outbox: %w", err)
+	}
+
+	if cfg.SSH.HostKey == "" {
+		cfg.SSH.HostKey = "./ssh_host_ed25519_key"
+	}
+
+	if cfg.Persistence.Dir == "" {
+		cfg.Persistence.Dir = "./save"
+	}
+
 	return &cfg, nil
 }
+
+func (ws *WorldSource) validate() error {
+	if ws.Type == "" {
+		ws.Type = WorldSourcePlugin
+	}
+
+	switch ws.Type {
+	case WorldSourcePlugin:
+		if ws.GameBinary == "" {
+			return fmt.Errorf("plugin source requires gameBinary")
+		}
+	case WorldSourceDSL:
+		if ws.DataDir == "" {
+			return fmt.Errorf("dsl source requires dataDir")
+		}
+		if ws.StartingRoom == "" {
+			return fmt.Errorf("dsl source requires startingRoom")
+		}
+		if ws.BuildFile == "" {
+			ws.BuildFile = "built.mud"
+		}
+	default:
+		return fmt.Errorf("unknown type '%s'", ws.Type)
+	}
+
+	return nil
+}
+
+func (l *Listener) validate() error {
+	switch l.Protocol {
+	case ProtocolTelnet, ProtocolTelnetTLS, ProtocolWS, ProtocolWSS, ProtocolSSH:
+	case "":
+		return fmt.Errorf("protocol is required")
+	default:
+		return fmt.Errorf("unknown protocol '%s'", l.Protocol)
+	}
+
+	if l.Address == "" {
+		return fmt.Errorf("%s listener requires an address", l.Protocol)
+	}
+	if l.TLS() && (l.CertFile == "" || l.KeyFile == "") {
+		return fmt.Errorf("%s listener on %s requires cert and key", l.Protocol, l.Address)
+	}
+	return nil
+}
+
+func (o *Outbox) validate() error {
+	switch o.Policy {
+	case "":
+		o.Policy = world.OutboxCoalesce
+	case world.OutboxCoalesce, world.OutboxDisconnect, world.OutboxSpill:
+	default:
+		return fmt.Errorf("unknown policy '%s'", o.Policy)
+	}
+	return nil
+}
+
+func (a *Accounts) validate() error {
+	if a.Store == "" {
+		a.Store = AccountStoreFile
+	}
+	if a.Store != AccountStoreFile {
+		return fmt.Errorf("unknown store '%s'", a.Store)
+	}
+	if a.Dir == "" {
+		a.Dir = "./accounts"
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener_Validate(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name     string
+		listener Listener
+		wantErr  string
+	}
+
+	cases := []tc{
+		{name: "telnet", listener: Listener{Protocol: ProtocolTelnet, Address: ":4000"}},
+		{name: "ssh", listener: Listener{Protocol: ProtocolSSH, Address: "127.0.0.1:2222"}},
+		{name: "wss with cert and key", listener: Listener{Protocol: ProtocolWSS, Address: ":8443", CertFile: "cert.pem", KeyFile: "key.pem"}},
+		{name: "no protocol", listener: Listener{Address: ":4000"}, wantErr: "protocol is required"},
+		{name: "unknown protocol", listener: Listener{Protocol: "gopher", Address: ":70"}, wantErr: "unknown protocol 'gopher'"},
+		{name: "no address", listener: Listener{Protocol: ProtocolWS}, wantErr: "ws listener requires an address"},
+		{name: "telnets without a key", listener: Listener{Protocol: ProtocolTelnetTLS, Address: ":4443", CertFile: "cert.pem"}, wantErr: "telnets listener on :4443 requires cert and key"},
+		{name: "wss without a cert", listener: Listener{Protocol: ProtocolWSS, Address: ":8443", KeyFile: "key.pem"}, wantErr: "wss listener on :8443 requires cert and key"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := c.listener.validate()
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
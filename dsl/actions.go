@@ -7,9 +7,12 @@ import (
 	"example.com/mud/models"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/actions"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type ActionDef struct {
+	Pos lexer.Position
+
 	Print                   *PrintAction             `parser:"  'print' @@"`
 	Publish                 *PublishAction           `parser:"| 'publish' @@"`
 	Copy                    *CopyAction              `parser:"| 'copy' @@"`
@@ -56,12 +59,16 @@ type RevealChildrenAction struct {
 }
 
 type ScheduleOnceAction struct {
+	Pos lexer.Position
+
 	ExprIn *Expression `parser:"'in' @@"`
 	Units  string      `parser:"@( 'second' | 'seconds' | 'minute' | 'minutes' )"`
 	Then   *ThenBlock  `parser:"@@"`
 }
 
 type ScheduleRepeatingAction struct {
+	Pos lexer.Position
+
 	ExprIn *Expression `parser:"'repeat' 'every' @@"`
 	Units  string      `parser:"@( 'second' | 'seconds' | 'minute' | 'minutes' )"`
 	While  *IfDef      `parser:"'while' @@"`
@@ -240,10 +247,14 @@ func (def *ScheduleOnceAction) Build() (entities.Action, error) {
 		return nil, fmt.Errorf("could not build schedule once then actions: %w", err)
 	}
 
-	return &actions.ScheduleOnce{
+	action := &actions.ScheduleOnce{
 		Nanoseconds: time.Duration(value.I) * unitMultiplier,
 		Actions:     then,
-	}, nil
+		Key:         scheduleKey(def.Pos),
+	}
+	actions.RegisterResumable(action.Key, action)
+
+	return action, nil
 }
 
 func (def *ScheduleRepeatingAction) Build() (entities.Action, error) {
@@ -272,10 +283,20 @@ func (def *ScheduleRepeatingAction) Build() (entities.Action, error) {
 		return nil, fmt.Errorf("could not build rule for schedule repeating action: %w", err)
 	}
 
-	return &actions.ScheduleRepeating{
+	action := &actions.ScheduleRepeating{
 		Nanoseconds: time.Duration(value.I) * unitMultiplier,
 		Rule:        rule,
-	}, nil
+		Key:         scheduleKey(def.Pos),
+	}
+	actions.RegisterResumable(action.Key, action)
+
+	return action, nil
+}
+
+// scheduleKey names a schedule action by where it is written, so jobs it
+// leaves pending can be matched back to it after a restart.
+func scheduleKey(pos lexer.Position) string {
+	return fmt.Sprintf("dsl:%s:%d:%d", pos.Filename, pos.Line, pos.Column)
 }
 
 func (def *ConditionalAction) Build() (entities.Action, error) {
@@ -24,23 +24,36 @@ type DSL struct {
 	Declarations []*TopLevel `parser:"@@*"`
 }
 
+// Pos and EndPos, here and below, are filled in by the parser. The writer
+// uses them to change a declaration without disturbing the rest of its file.
 type TopLevel struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Entity  *EntityDef  `parser:"'entity' @@"`
 	Trait   *TraitDef   `parser:"| 'trait' @@"`
 	Command *CommandDef `parser:"| 'command' @@"`
 }
 
 type EntityDef struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Name   string         `parser:"@Ident"`
 	Blocks []*EntityBlock `parser:"'{' { @@ } '}'"`
 }
 
 type TraitDef struct {
+	Pos lexer.Position
+
 	Name   string         `parser:"@Ident"`
 	Blocks []*EntityBlock `parser:"'{' { @@ } '}'"`
 }
 
 type EntityBlock struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Component *ComponentDef        `parser:"  'component' @@"`
 	Trait     *TraitInheritanceDef `parser:"| 'trait' @@"`
 	Reaction  *ReactionDef         `parser:"| 'react' @@"`
@@ -53,6 +66,9 @@ type TraitInheritanceDef struct {
 }
 
 type FieldDef struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Key   string      `parser:"@Ident 'is'"`
 	Value *Expression `parser:"@@"`
 	Pairs []KV        `parser:"| '{' @@ { ',' @@ } '}'"`
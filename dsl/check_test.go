@@ -0,0 +1,85 @@
+package dsl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const checkRooms = `entity Hall {
+    name is "Hall"
+    description is "A long hall."
+    aliases is ["hall"]
+    trait Haunted
+
+    component Room {
+        children is ["Lamp", "Ghost"]
+    }
+}
+
+entity Lamp {
+    name is 3
+    description is "A lamp."
+    aliases is ["lamp"]
+
+    react rub {
+        then {
+            print nobody "A genie appears."
+        }
+    }
+}
+`
+
+const checkCommands = `command Rub {
+    aliases is ["rub"]
+    pattern {
+        syntax is "rub {target}"
+    }
+}
+
+command Rub {
+    aliases is ["polish"]
+}
+`
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rooms.mud"), []byte(checkRooms), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "commands.mud"), []byte(checkCommands), 0o644))
+
+	var got []string
+	_, _, errs := Check(dir)
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	require.Len(t, got, 5, strings.Join(got, "\n"))
+
+	want := []string{
+		filepath.Join(dir, "commands.mud") + ":8:9: duplicate command Rub, first declared at commands.mud:1:9",
+		filepath.Join(dir, "rooms.mud") + ":5:5: could not build prototype 'Hall': unknown trait 'Haunted'",
+		filepath.Join(dir, "rooms.mud") + ":8:9: prototype 'Hall' has unknown child 'Ghost'",
+		filepath.Join(dir, "rooms.mud") + ":13:5: could not build prototype 'Lamp': name must be a string",
+		filepath.Join(dir, "rooms.mud") + ":19:13: could not build prototype 'Lamp': could not build reaction for rub",
+	}
+	for i, prefix := range want {
+		require.True(t, strings.HasPrefix(got[i], prefix), "want %q, got %q", prefix, got[i])
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.mud"), []byte("entity Broken {\n    name is\n}\n"), 0o644))
+	_, _, errs = Check(dir)
+	require.Len(t, errs, 1, "syntax errors are reported on their own")
+	require.ErrorContains(t, errs[0], filepath.Join(dir, "broken.mud")+":3:1: ")
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "broken.mud")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rooms.mud"), []byte(writerSource), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "commands.mud"), []byte(checkCommands[:strings.LastIndex(checkCommands, "command")]), 0o644))
+	entityMap, commands, errs := Check(dir)
+	require.Empty(t, errs)
+	require.Contains(t, entityMap, "Hall")
+	require.Len(t, commands, 1)
+}
@@ -5,9 +5,12 @@ import (
 	"strings"
 
 	"example.com/mud/models"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type CommandDef struct {
+	Pos lexer.Position
+
 	Name   string          `parser:"@Ident"`
 	Blocks []*CommandBlock `parser:"'{' { @@ } '}'"`
 }
@@ -35,11 +38,11 @@ func (def *CommandDef) Build() (*models.CommandDefinition, error) {
 			case "aliases":
 				value, err := immediateEvalExpressionAs(f.Value, models.KindStringList)
 				if err != nil {
-					return nil, fmt.Errorf("could not get value '%s' for command aliases: %w", f.Key, err)
+					return nil, at(f.Pos, fmt.Errorf("could not get value '%s' for command aliases: %w", f.Key, err))
 				}
 				cmd.Aliases = append(cmd.Aliases, value.SL...)
 			default:
-				return nil, fmt.Errorf("unknown field '%s' in command definition", f.Key)
+				return nil, at(f.Pos, fmt.Errorf("unknown field '%s' in command definition", f.Key))
 			}
 		} else if b.CommandDefinitionDef != nil {
 			commandPattern, err := b.CommandDefinitionDef.Build()
@@ -64,11 +67,14 @@ func (def *CommandDefinitionDef) Build() (*models.CommandPattern, error) {
 	for _, f := range def.Fields {
 		value, err := immediateEvalExpressionAs(f.Value, models.KindString)
 		if err != nil {
-			return nil, fmt.Errorf("could not get value '%s' for command: %w", f.Key, err)
+			return nil, at(f.Pos, fmt.Errorf("could not get value '%s' for command: %w", f.Key, err))
 		}
 
 		switch f.Key {
 		case "syntax":
+			if strings.TrimSpace(value.S) == "" {
+				return nil, at(f.Pos, fmt.Errorf("command syntax is empty"))
+			}
 			p.Tokens = tokenizeCommandSyntax(value.S)
 		case "noMatch":
 			p.NoMatchMessage = value.S
@@ -76,7 +82,7 @@ func (def *CommandDefinitionDef) Build() (*models.CommandPattern, error) {
 			p.HelpMessage = value.S
 		default:
 			err := fmt.Errorf("CommandDefinitionDef Field not recognized: %s", f.Key)
-			return nil, err
+			return nil, at(f.Pos, err)
 		}
 	}
 	return p, nil
@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"errors"
 	"fmt"
 
 	"example.com/mud/models"
@@ -34,75 +35,90 @@ type entityPrototype struct {
 
 type entityPrototypes struct {
 	prototypesById map[string]*entityPrototype
+	entitiesById   map[string]EntityDef
 	traitsById     map[string]TraitDef
 	childrenPlan   ChildrenPlan
 	visiting       map[string]struct{}
 }
 
+// Compile builds the entities and commands declared in ast. Its errors are
+// joined, one for each mistake found, in the order they appear in the files.
 func Compile(ast *DSL) (map[string]*entities.Entity, []*models.CommandDefinition, error) {
 	if ast == nil {
 		return nil, nil, fmt.Errorf("nil DSL")
 	}
 
+	var errs []error
 	collectedDefs, err := collectDefs(ast.Declarations)
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not collect top level declarations: %w", err)
+		errs = append(errs, err)
 	}
 
 	prototypes, err := collectedDefs.collectPrototypes()
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not collect prototype entities: %w", err)
-	}
-
-	entitiesById, err := prototypes.instantiatePrototypes()
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not instantiate prototype entities: %w", err)
+		errs = append(errs, err)
 	}
 
 	commands := make([]*models.CommandDefinition, 0, len(collectedDefs.commandsById))
 	for _, c := range collectedDefs.commandsById {
 		cd, err := c.Build()
 		if err != nil {
-			return nil, nil, fmt.Errorf("could not instantiate command '%s': %w", c.Name, err)
+			errs = append(errs, at(c.Pos, fmt.Errorf("could not instantiate command '%s': %w", c.Name, err)))
+			continue
 		}
 
 		commands = append(commands, cd)
 	}
 
+	if len(errs) > 0 {
+		return nil, nil, joinErrors(errs)
+	}
+
+	entitiesById, err := prototypes.instantiatePrototypes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not instantiate prototype entities: %w", err)
+	}
+
 	return entitiesById, commands, nil
 }
 
-// collect entity, command and trait definitions
+// collect entity, command and trait definitions. Duplicates are reported,
+// and the first declaration is kept.
 func collectDefs(decls []*TopLevel) (*collectedDefs, error) {
 	entitiesById := make(map[string]EntityDef, len(decls))
 	commandsById := make(map[string]CommandDef, len(decls))
 	traitsById := make(map[string]TraitDef, len(decls))
 
+	var errs []error
 	for _, declaration := range decls {
 		if declaration == nil {
-			return nil, fmt.Errorf("declaration at top level is nil")
+			errs = append(errs, fmt.Errorf("declaration at top level is nil"))
+			continue
 		}
 
 		if ed := declaration.Entity; ed != nil {
-			if _, exists := entitiesById[ed.Name]; exists {
-				return nil, fmt.Errorf("duplicate entity %s", ed.Name)
+			if first, exists := entitiesById[ed.Name]; exists {
+				errs = append(errs, at(ed.Pos, fmt.Errorf("duplicate entity %s, first declared at %s", ed.Name, first.Pos)))
+				continue
 			}
 
 			entitiesById[ed.Name] = *ed
 		} else if td := declaration.Trait; td != nil {
-			if _, exists := entitiesById[td.Name]; exists {
-				return nil, fmt.Errorf("duplicate trait %s", td.Name)
+			if first, exists := traitsById[td.Name]; exists {
+				errs = append(errs, at(td.Pos, fmt.Errorf("duplicate trait %s, first declared at %s", td.Name, first.Pos)))
+				continue
 			}
 
 			traitsById[declaration.Trait.Name] = *declaration.Trait
 		} else if ec := declaration.Command; ec != nil {
-			if _, exists := commandsById[ec.Name]; exists {
-				return nil, fmt.Errorf("duplicate command %s", ec.Name)
+			if first, exists := commandsById[ec.Name]; exists {
+				errs = append(errs, at(ec.Pos, fmt.Errorf("duplicate command %s, first declared at %s", ec.Name, first.Pos)))
+				continue
 			}
 
 			commandsById[declaration.Command.Name] = *declaration.Command
 		} else {
-			return nil, fmt.Errorf("declaration at top level is empty")
+			errs = append(errs, at(declaration.Pos, fmt.Errorf("declaration at top level is empty")))
 		}
 	}
 
@@ -110,24 +126,27 @@ func collectDefs(decls []*TopLevel) (*collectedDefs, error) {
 		entitiesById: entitiesById,
 		traitsById:   traitsById,
 		commandsById: commandsById,
-	}, nil
+	}, errors.Join(errs...)
 }
 
 // expand traits in each entity definition
 func (c *collectedDefs) collectPrototypes() (*entityPrototypes, error) {
 	ep := &entityPrototypes{
 		prototypesById: map[string]*entityPrototype{},
+		entitiesById:   c.entitiesById,
 		traitsById:     c.traitsById,
 		childrenPlan:   map[string]map[entities.ComponentType][]string{},
 		visiting:       map[string]struct{}{},
 	}
 
 	// build prototypes of each entity and put them in name->builtEntity map
+	var errs []error
 	for name, ed := range c.entitiesById {
 		// build prototype and populate pending children
 		prototypeEntity, err := ep.buildPrototype(name, ed.Blocks)
 		if err != nil {
-			return nil, fmt.Errorf("build %s: %w", name, err)
+			errs = append(errs, wrapEach(err, func(err error) error { return at(ed.Pos, err) }))
+			continue
 		}
 		ep.prototypesById[name] = &entityPrototype{
 			id:  name,
@@ -136,15 +155,20 @@ func (c *collectedDefs) collectPrototypes() (*entityPrototypes, error) {
 		}
 	}
 
-	return ep, nil
+	return ep, errors.Join(errs...)
 }
 
 // create prototype entity with components. collect child prototype names into the sidecar for later.
 func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*entities.Entity, error) {
 
-	loweredEntity, err := ep.lowerEntity(id, blocks)
-	if err != nil {
-		return nil, fmt.Errorf("could not build prototype: %w", err)
+	loweredEntity, lowerErr := ep.lowerEntity(id, blocks)
+	if lowerErr != nil {
+		lowerErr = wrapEach(lowerErr, func(err error) error {
+			return fmt.Errorf("could not build prototype '%s': %w", id, err)
+		})
+	}
+	if err := errors.Join(lowerErr, ep.planChildren(id, blocks)); err != nil {
+		return nil, err
 	}
 
 	e := entities.NewEntity(
@@ -155,6 +179,7 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 		loweredEntity.fields,
 		nil,
 	)
+	e.TemplateID = id
 
 	for _, c := range loweredEntity.components {
 		e.Add(c)
@@ -177,6 +202,13 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 		}
 	}
 
+	return e, nil
+}
+
+// collect the names of an entity's children into the sidecar, for when the
+// entity is instantiated
+func (ep *entityPrototypes) planChildren(id string, blocks []*EntityBlock) error {
+	var errs []error
 	for _, block := range blocks {
 		if block.Component == nil {
 			continue
@@ -191,13 +223,20 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 				// populate pending children map
 				componentType, err := entities.ParseComponentType(block.Component.Name)
 				if err != nil {
-					return nil, fmt.Errorf("could not build prototype '%s': %w", id, err)
+					errs = append(errs, at(block.Pos, fmt.Errorf("could not build prototype '%s': %w", id, err)))
+					continue
 				}
 
 				// get list of strings from expression
 				childrenStrings, err := immediateEvalExpressionAs(f.Value, models.KindStringList)
 				if err != nil {
-					return nil, fmt.Errorf("could not get children list for prototype '%s': %w", id, err)
+					errs = append(errs, at(f.Pos, fmt.Errorf("could not get children list for prototype '%s': %w", id, err)))
+					continue
+				}
+				for _, child := range childrenStrings.SL {
+					if _, ok := ep.entitiesById[child]; !ok {
+						errs = append(errs, at(f.Pos, fmt.Errorf("prototype '%s' has unknown child '%s'", id, child)))
+					}
 				}
 
 				ep.childrenPlan[id][componentType] =
@@ -206,7 +245,7 @@ func (ep *entityPrototypes) buildPrototype(id string, blocks []*EntityBlock) (*e
 		}
 	}
 
-	return e, nil
+	return errors.Join(errs...)
 }
 
 // recursively expand traits in entities
@@ -226,12 +265,15 @@ func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*Lowe
 	components := make([]entities.Component, 0, len(blocks))
 	rulesByCommand := make(map[string][]*entities.Rule, len(blocks))
 
+	// carry on past a block with a mistake, so they're all reported
+	var errs []error
 	for _, block := range blocks {
 		if block.Reaction != nil {
 			// process reaction
 			rules, err := block.Reaction.Build()
 			if err != nil {
-				return nil, err
+				errs = append(errs, at(block.Pos, err))
+				continue
 			}
 			// rules at the entity level come first
 			for _, command := range block.Reaction.Commands {
@@ -241,21 +283,30 @@ func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*Lowe
 			// process component into prototype without children
 			comp, err := block.Component.Build()
 			if err != nil {
-				return nil, fmt.Errorf("could not process component %s: %w", block.Component.Name, err)
+				errs = append(errs, at(block.Pos, fmt.Errorf("could not process component %s: %w", block.Component.Name, err)))
+				continue
 			}
 			components = append(components, comp)
 		} else if block.Trait != nil {
-			// TODO this dereferences a nil pointer if the trait doesn't exist
-			loweredTrait, err := ep.lowerEntity(block.Trait.Name, ep.traitsById[block.Trait.Name].Blocks)
+			trait, ok := ep.traitsById[block.Trait.Name]
+			if !ok {
+				errs = append(errs, at(block.Pos, fmt.Errorf("unknown trait '%s'", block.Trait.Name)))
+				continue
+			}
+			loweredTrait, err := ep.lowerEntity(block.Trait.Name, trait.Blocks)
 			if err != nil {
-				return nil, fmt.Errorf("could not process trait '%s': %w", block.Trait.Name, err)
+				errs = append(errs, wrapEach(err, func(err error) error {
+					return at(block.Pos, fmt.Errorf("could not process trait '%s': %w", block.Trait.Name, err))
+				}))
+				continue
 			}
 
 			// first write over fields that were passed into trait
 			for _, f := range block.Trait.Fields {
 				value, err := immediateEvalExpression(f.Value)
 				if err != nil {
-					return nil, fmt.Errorf("could not get process trait '%s' field '%s': %w", block.Trait.Name, f.Key, err)
+					errs = append(errs, at(f.Pos, fmt.Errorf("could not get process trait '%s' field '%s': %w", block.Trait.Name, f.Key, err)))
+					continue
 				}
 
 				// only include fields passed into trait that aren't already defined
@@ -282,37 +333,45 @@ func (ep *entityPrototypes) lowerEntity(id string, blocks []*EntityBlock) (*Lowe
 			f := block.Field
 			value, err := immediateEvalExpression(block.Field.Value)
 			if err != nil {
-				return nil, fmt.Errorf("could not get process field '%s' for entity '%s': %w", block.Field.Key, id, err)
+				errs = append(errs, at(block.Pos, fmt.Errorf("could not get process field '%s' for entity '%s': %w", block.Field.Key, id, err)))
+				continue
 			}
 
 			switch f.Key {
 			case "name":
 				if value.K != models.KindString {
-					return nil, fmt.Errorf("name must be a string")
+					errs = append(errs, at(block.Pos, fmt.Errorf("name must be a string")))
+					continue
 				}
 				name = value.S
 			case "description":
 				if value.K != models.KindString {
-					return nil, fmt.Errorf("description must be a string")
+					errs = append(errs, at(block.Pos, fmt.Errorf("description must be a string")))
+					continue
 				}
 				description = value.S
 			case "aliases":
 				if value.K != models.KindStringList {
-					return nil, fmt.Errorf("aliases must be a string list")
+					errs = append(errs, at(block.Pos, fmt.Errorf("aliases must be a string list")))
+					continue
 				}
 				aliases = value.SL
 			case "tags":
 				if value.K != models.KindStringList {
-					return nil, fmt.Errorf("tags must be a string list")
+					errs = append(errs, at(block.Pos, fmt.Errorf("tags must be a string list")))
+					continue
 				}
 				tags = value.SL
 			default:
 				fields[f.Key] = value
 			}
 		} else {
-			return nil, fmt.Errorf("could not expand empty entity block")
+			errs = append(errs, at(block.Pos, fmt.Errorf("could not expand empty entity block")))
 		}
 	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
 
 	// only do verification if at a top level entity
 	if len(ep.visiting) == 1 {
@@ -6,9 +6,12 @@ import (
 	"example.com/mud/models"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/components"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type ComponentDef struct {
+	EndPos lexer.Position
+
 	Name   string      `parser:"@Ident"`
 	Fields []*FieldDef `parser:"'{' { @@ } '}'"`
 }
@@ -46,7 +49,7 @@ func buildRoom(def *ComponentDef) (entities.Component, error) {
 			if m == nil {
 				m = map[string]string{}
 			}
-			rm.Exits = m
+			rm.SetExits(m)
 			continue
 		}
 
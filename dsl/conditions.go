@@ -6,9 +6,12 @@ import (
 
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/conditions"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
 type ConditionDef struct {
+	Pos lexer.Position
+
 	Or *OrChain `parser:"@@"`
 }
 
@@ -27,6 +30,7 @@ type CondAtom struct {
 	Expr       *ExprCondition            `parser:"| @@"`
 	HasTag     *HasTagCondition          `parser:"| @@"`
 	IsPresent  *IsPresentCondition       `parser:"| @@"`
+	FieldEq    *FieldEqualsCondition     `parser:"| @@"`
 	RolesEqual *EventRolesEqualCondition `parser:"| @@"`
 	HasChild   *HasChildCondition        `parser:"| @@"`
 	MsgHas     *MessageContains          `parser:"| @@"`
@@ -49,6 +53,12 @@ type IsPresentCondition struct {
 	Role string `parser:"@Ident 'exists'"`
 }
 
+type FieldEqualsCondition struct {
+	Role  string      `parser:"@Ident"`
+	Field string      `parser:"'.' @Ident"`
+	Value *Expression `parser:"'is' @@"`
+}
+
 type EventRolesEqualCondition struct {
 	Role1 string `parser:"@Ident"`
 	Role2 string `parser:"'is' @Ident"`
@@ -104,6 +114,8 @@ func (def *CondAtom) Build() (entities.Condition, error) {
 		return def.HasTag.Build()
 	case def.IsPresent != nil:
 		return def.IsPresent.Build()
+	case def.FieldEq != nil:
+		return def.FieldEq.Build()
 	case def.RolesEqual != nil:
 		return def.RolesEqual.Build()
 	case def.HasChild != nil:
@@ -150,6 +162,22 @@ func (def *IsPresentCondition) Build() (entities.Condition, error) {
 	return &conditions.IsPresent{EventRole: eventRole}, nil
 }
 
+func (def *FieldEqualsCondition) Build() (entities.Condition, error) {
+	eventRole, err := entities.ParseEventRole(def.Role)
+	if err != nil {
+		return nil, fmt.Errorf("field equals condition: %w", err)
+	}
+	value, err := immediateEvalExpression(def.Value)
+	if err != nil {
+		return nil, fmt.Errorf("field equals condition '%s.%s': %w", def.Role, def.Field, err)
+	}
+	return &conditions.FieldEquals{
+		EventRole: eventRole,
+		Field:     def.Field,
+		Value:     value,
+	}, nil
+}
+
 func (def *EventRolesEqualCondition) Build() (entities.Condition, error) {
 	role1, err := entities.ParseEventRole(def.Role1)
 	if err != nil {
@@ -0,0 +1,112 @@
+package dsl
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Error is a mistake in a DSL file, at the position it was found.
+type Error struct {
+	Pos lexer.Position
+	Err error
+}
+
+func (e *Error) Error() string {
+	msg := e.Err.Error()
+	var inner *Error
+	if errors.As(e.Err, &inner) {
+		// its position was moved to the front by at
+		msg = strings.Replace(msg, inner.Pos.String()+": ", "", 1)
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// at places err at pos, unless it's already been placed nearer the mistake,
+// in which case that position is moved to the front of the message.
+func at(pos lexer.Position, err error) error {
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	var placed *Error
+	if errors.As(err, &placed) {
+		pos = placed.Pos
+	}
+	return &Error{Pos: pos, Err: err}
+}
+
+// wrapEach wraps each of the mistakes joined in err separately.
+func wrapEach(err error, wrap func(err error) error) error {
+	var out []error
+	for _, e := range Errors(err) {
+		out = append(out, wrap(e))
+	}
+	return errors.Join(out...)
+}
+
+// Errors splits an error from loading or compiling DSL files into the
+// mistakes it's made of.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var out []error
+		for _, e := range joined.Unwrap() {
+			out = append(out, Errors(e)...)
+		}
+		return out
+	}
+	return []error{err}
+}
+
+// joinErrors joins errs in the order of the files and lines they're at, with
+// any that aren't at a position last.
+func joinErrors(errs []error) error {
+	var flat []error
+	for _, err := range errs {
+		flat = append(flat, Errors(err)...)
+	}
+
+	slices.SortStableFunc(flat, func(a, b error) int {
+		pa, oka := position(a)
+		pb, okb := position(b)
+		switch {
+		case !oka || !okb:
+			return cmp.Compare(boolRank(oka), boolRank(okb))
+		case pa.Filename != pb.Filename:
+			return cmp.Compare(pa.Filename, pb.Filename)
+		case pa.Line != pb.Line:
+			return cmp.Compare(pa.Line, pb.Line)
+		default:
+			return cmp.Compare(pa.Column, pb.Column)
+		}
+	})
+	return errors.Join(slices.CompactFunc(flat, func(a, b error) bool {
+		// a mistake in a trait is found once for each entity with the trait
+		return a.Error() == b.Error()
+	})...)
+}
+
+func position(err error) (lexer.Position, bool) {
+	var placed *Error
+	if errors.As(err, &placed) {
+		return placed.Pos, true
+	}
+	return lexer.Position{}, false
+}
+
+func boolRank(placed bool) int {
+	if placed {
+		return 0
+	}
+	return 1
+}
@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -12,17 +13,61 @@ import (
 	participle "github.com/alecthomas/participle/v2"
 )
 
-func LoadEntitiesFromDirectory(directoryName string) (map[string]*entities.Entity, []*models.CommandDefinition, error) {
-	parser, err := participle.Build[DSL](
+func newParser() (*participle.Parser[DSL], error) {
+	return participle.Build[DSL](
 		participle.Lexer(DslLexer),
 		participle.Elide("Whitespace", "Comment"),
 		participle.Unquote("String"),
 	)
+}
+
+func LoadEntitiesFromDirectory(directoryName string) (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+	ast, err := parseDirectory(directoryName)
 	if err != nil {
-		return nil, nil, fmt.Errorf("parser build failed %w", err)
+		return nil, nil, err
+	}
+
+	entities, commands, err := Compile(ast)
+	return entities, commands, err
+}
+
+// Check parses and compiles the DSL files in a directory, without building a
+// world from them, and returns what they declare or every mistake found, with
+// positions relative to the working directory rather than the data directory.
+func Check(directoryName string) (map[string]*entities.Entity, []*models.CommandDefinition, []error) {
+	ast, err := parseDirectory(directoryName)
+	if err != nil {
+		return nil, nil, inDirectory(directoryName, Errors(err))
+	}
+
+	entities, commands, err := Compile(ast)
+	if err != nil {
+		return nil, nil, inDirectory(directoryName, Errors(err))
+	}
+	return entities, commands, nil
+}
+
+func inDirectory(directoryName string, errs []error) []error {
+	for _, err := range errs {
+		var placed *Error
+		if errors.As(err, &placed) && placed.Pos.Filename != "" {
+			placed.Pos.Filename = filepath.Join(directoryName, filepath.FromSlash(placed.Pos.Filename))
+		}
+	}
+	return errs
+}
+
+// parseDirectory parses every .mud file under a directory into one syntax
+// tree. A file that doesn't parse doesn't stop the rest from being parsed, so
+// that all of their syntax errors are reported together.
+func parseDirectory(directoryName string) (*DSL, error) {
+	parser, err := newParser()
+	if err != nil {
+		return nil, fmt.Errorf("parser build failed %w", err)
 	}
 
 	var ast = &DSL{}
+	var errs []error
 
 	err = filepath.WalkDir(directoryName, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -40,8 +85,20 @@ func LoadEntitiesFromDirectory(directoryName string) (map[string]*entities.Entit
 			return fmt.Errorf("failed to read %s: %v", path, err)
 		}
 
-		fileSyntaxTree, err := parser.ParseString("", string(data))
+		// name positions relative to the data directory, so they stay stable
+		// if the directory moves
+		relPath, err := filepath.Rel(directoryName, path)
+		if err != nil {
+			relPath = path
+		}
+
+		fileSyntaxTree, err := parser.ParseString(filepath.ToSlash(relPath), string(data))
 		if err != nil {
+			var syntaxErr participle.Error
+			if errors.As(err, &syntaxErr) {
+				errs = append(errs, &Error{Pos: syntaxErr.Position(), Err: errors.New(syntaxErr.Message())})
+				return nil
+			}
 			return fmt.Errorf("failed to parse %s: %v", path, err)
 		}
 
@@ -49,9 +106,11 @@ func LoadEntitiesFromDirectory(directoryName string) (map[string]*entities.Entit
 		return nil
 	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("error walking DSL directory: %w", err)
+		return nil, fmt.Errorf("error walking DSL directory: %w", err)
+	}
+	if len(errs) > 0 {
+		return nil, joinErrors(errs)
 	}
 
-	entities, commands, err := Compile(ast)
-	return entities, commands, err
+	return ast, nil
 }
@@ -64,7 +64,7 @@ func (def *WhenBlock) Build() ([]entities.Condition, error) {
 	for i, cDef := range def.Conds {
 		condition, err := cDef.Build()
 		if err != nil {
-			return nil, fmt.Errorf("build when: %w", err)
+			return nil, at(cDef.Pos, fmt.Errorf("build when: %w", err))
 		}
 		ret[i] = condition
 	}
@@ -79,7 +79,7 @@ func (def *ThenBlock) Build() ([]entities.Action, error) {
 		action, err := aDef.Build()
 
 		if err != nil {
-			return nil, fmt.Errorf("build action: %w", err)
+			return nil, at(aDef.Pos, fmt.Errorf("build action: %w", err))
 		}
 
 		ret[i] = action
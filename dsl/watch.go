@@ -0,0 +1,56 @@
+package dsl
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Watch calls changed whenever a DSL file in dir is added, removed or
+// modified, looking every interval. It never returns.
+func Watch(dir string, interval time.Duration, changed func()) {
+	last, err := fingerprint(dir)
+	if err != nil {
+		fmt.Println("watch DSL files:", err)
+	}
+
+	for range time.Tick(interval) {
+		current, err := fingerprint(dir)
+		if err != nil {
+			fmt.Println("watch DSL files:", err)
+			continue
+		}
+		if !maps.Equal(current, last) {
+			last = current
+			changed()
+		}
+	}
+}
+
+// fileStamp is enough of a file's stat to tell that it changed.
+type fileStamp struct {
+	modified time.Time
+	size     int64
+}
+
+func fingerprint(dir string) (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".mud") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stamps[path] = fileStamp{modified: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return stamps, err
+}
@@ -0,0 +1,463 @@
+package dsl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"example.com/mud/models"
+	"example.com/mud/utils"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	participle "github.com/alecthomas/participle/v2"
+)
+
+const indentUnit = "    "
+
+var identPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Writer saves changes made to the world in game back to a directory of DSL
+// files. An entity is changed where it's declared, leaving the rest of its
+// file, comments and all, as it was. New entities go in a file of their own.
+type Writer struct {
+	mu        sync.Mutex
+	dir       string
+	buildFile string // relative to dir
+	parser    *participle.Parser[DSL]
+}
+
+func NewWriter(dir, buildFile string) (*Writer, error) {
+	parser, err := newParser()
+	if err != nil {
+		return nil, fmt.Errorf("parser build failed %w", err)
+	}
+	return &Writer{dir: dir, buildFile: buildFile, parser: parser}, nil
+}
+
+// declaration is an entity's declaration and the source of the file it's in.
+type declaration struct {
+	path string
+	src  []byte
+	top  *TopLevel
+}
+
+// AddEntity declares a new entity in the build file, from an entity made in
+// game. It can't write reactions, so e mustn't have any.
+func (wr *Writer) AddEntity(e *entities.Entity) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	if _, ok, err := wr.find(e.TemplateID); err != nil {
+		return fmt.Errorf("add entity '%s': %w", e.TemplateID, err)
+	} else if ok {
+		return fmt.Errorf("add entity '%s': it's already declared", e.TemplateID)
+	}
+
+	text, err := formatEntity(e)
+	if err != nil {
+		return fmt.Errorf("add entity '%s': %w", e.TemplateID, err)
+	}
+
+	path := filepath.Join(wr.dir, wr.buildFile)
+	src, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		src = []byte("// Rooms and things built in game. Changes to them are written back here.\n")
+	case err != nil:
+		return fmt.Errorf("add entity '%s': %w", e.TemplateID, err)
+	}
+
+	src = append(bytes.TrimRight(src, "\n"), '\n', '\n')
+	src = append(src, text...)
+	if err := wr.write(path, src); err != nil {
+		return fmt.Errorf("add entity '%s': %w", e.TemplateID, err)
+	}
+	return nil
+}
+
+// SetField sets one of an entity's fields, including its name, description,
+// aliases and tags. An empty list takes the field out instead, since the DSL
+// has no way to write one.
+func (wr *Writer) SetField(templateID, key string, value models.Value) error {
+	literal, err := formatValue(value)
+	if err != nil {
+		return fmt.Errorf("set %s.%s: %w", templateID, key, err)
+	}
+	return wr.edit(templateID, func(d *declaration) ([]byte, error) {
+		return setIn(d.src, entityBody(d), key, literal), nil
+	})
+}
+
+// SetComponentField sets one of the fields of an entity's component, adding
+// the component to the declaration if it isn't there.
+func (wr *Writer) SetComponentField(templateID string, component entities.ComponentType, key string, value models.Value) error {
+	literal, err := formatValue(value)
+	if err != nil {
+		return fmt.Errorf("set %s.%s.%s: %w", templateID, component, key, err)
+	}
+	return wr.setComponentField(templateID, component, key, literal)
+}
+
+// SetExits replaces a room's exits.
+func (wr *Writer) SetExits(templateID string, exits map[string]string) error {
+	return wr.setComponentField(templateID, entities.ComponentRoom, "exits", formatExits(exits))
+}
+
+// AddChild adds childID to the children an entity's component starts out
+// with.
+func (wr *Writer) AddChild(templateID string, component entities.ComponentType, childID string) error {
+	return wr.edit(templateID, func(d *declaration) ([]byte, error) {
+		block := componentBlock(d, component)
+		if block == nil {
+			return addComponent(d.src, entityBody(d), component, "children is "+strconv.Quote(childID)), nil
+		}
+
+		var children []string
+		multiline := false
+		for _, f := range block.Component.Fields {
+			if f.Key != "children" {
+				continue
+			}
+			value, err := immediateEvalExpressionAs(f.Value, models.KindStringList)
+			if err != nil {
+				return nil, fmt.Errorf("children: %w", err)
+			}
+			children = value.SL
+			multiline = bytes.ContainsRune(d.src[f.Pos.Offset:f.EndPos.Offset], '\n')
+		}
+
+		children = append(children, childID)
+		literal := formatList(children, strconv.Quote)
+		if multiline {
+			// keep to the one-child-a-line style it was written in
+			literal = "[\n" + indentUnit + strings.Join(mapList(children, strconv.Quote), ",\n"+indentUnit) + "\n]"
+		}
+		return setIn(d.src, componentBody(d, block), "children", literal), nil
+	})
+}
+
+func (wr *Writer) setComponentField(templateID string, component entities.ComponentType, key, literal string) error {
+	return wr.edit(templateID, func(d *declaration) ([]byte, error) {
+		block := componentBlock(d, component)
+		if block == nil {
+			if literal == "" {
+				return d.src, nil
+			}
+			return addComponent(d.src, entityBody(d), component, key+" is "+literal), nil
+		}
+		return setIn(d.src, componentBody(d, block), key, literal), nil
+	})
+}
+
+// edit rewrites the declaration of templateID with change, and saves its
+// file if the result still parses.
+func (wr *Writer) edit(templateID string, change func(d *declaration) ([]byte, error)) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	d, ok, err := wr.find(templateID)
+	if err != nil {
+		return fmt.Errorf("edit '%s': %w", templateID, err)
+	}
+	if !ok {
+		return fmt.Errorf("edit '%s': it isn't declared in %s", templateID, wr.dir)
+	}
+
+	src, err := change(d)
+	if err != nil {
+		return fmt.Errorf("edit '%s': %w", templateID, err)
+	}
+	if err := wr.write(d.path, src); err != nil {
+		return fmt.Errorf("edit '%s': %w", templateID, err)
+	}
+	return nil
+}
+
+// find looks through the DSL files for the declaration of templateID.
+func (wr *Writer) find(templateID string) (*declaration, bool, error) {
+	var found *declaration
+	err := filepath.WalkDir(wr.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".mud") {
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		ast, err := wr.parser.ParseBytes(path, src)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		for _, top := range ast.Declarations {
+			if top.Entity != nil && top.Entity.Name == templateID {
+				found = &declaration{path: path, src: src, top: top}
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return found, found != nil, nil
+}
+
+// write saves src to path, refusing to if it no longer parses.
+func (wr *Writer) write(path string, src []byte) error {
+	if _, err := wr.parser.ParseBytes(path, src); err != nil {
+		return fmt.Errorf("the change wouldn't parse: %w", err)
+	}
+	return utils.WriteFileAtomic(path, src)
+}
+
+// body is the inside of a braced block in a declaration.
+type body struct {
+	fields []*FieldDef
+	blocks bool // whether there's anything in the block at all
+	last   int  // offset just past the last thing in the block, or the opening brace
+	indent string
+}
+
+func entityBody(d *declaration) body {
+	b := body{}
+	for _, block := range d.top.Entity.Blocks {
+		if block.Field != nil {
+			b.fields = append(b.fields, block.Field)
+		}
+	}
+
+	if blocks := d.top.Entity.Blocks; len(blocks) > 0 {
+		b.blocks = true
+		b.last = blocks[len(blocks)-1].EndPos.Offset
+		b.indent = lineIndent(d.src, blocks[0].Pos.Offset)
+	} else {
+		b.last = openingBrace(d.src, d.top.Pos.Offset)
+		b.indent = lineIndent(d.src, d.top.Pos.Offset) + indentUnit
+	}
+	return b
+}
+
+func componentBlock(d *declaration, component entities.ComponentType) *EntityBlock {
+	for _, block := range d.top.Entity.Blocks {
+		if block.Component != nil && block.Component.Name == component.String() {
+			return block
+		}
+	}
+	return nil
+}
+
+func componentBody(d *declaration, block *EntityBlock) body {
+	fields := block.Component.Fields
+	b := body{fields: fields}
+	if len(fields) > 0 {
+		b.blocks = true
+		b.last = fields[len(fields)-1].EndPos.Offset
+		b.indent = lineIndent(d.src, fields[0].Pos.Offset)
+	} else {
+		b.last = openingBrace(d.src, block.Pos.Offset)
+		b.indent = lineIndent(d.src, block.Pos.Offset) + indentUnit
+	}
+	return b
+}
+
+// setIn replaces the value of key in b, or adds the field if it isn't there.
+// An empty literal takes the field out.
+func setIn(src []byte, b body, key, literal string) []byte {
+	for _, f := range b.fields {
+		if f.Key != key {
+			continue
+		}
+		if literal == "" {
+			start := f.Pos.Offset
+			if i := bytes.LastIndexByte(src[:start], '\n'); i >= 0 && len(bytes.TrimSpace(src[i:start])) == 0 {
+				start = i
+			}
+			return splice(src, start, f.EndPos.Offset, "")
+		}
+		return splice(src, f.Pos.Offset, f.EndPos.Offset, key+" is "+indentLines(literal, lineIndent(src, f.Pos.Offset)))
+	}
+
+	if literal == "" {
+		return src
+	}
+	return addBlock(src, b, key+" is "+literal, false)
+}
+
+// addBlock puts text on a new line at the end of b, after a blank line if
+// gap is set.
+func addBlock(src []byte, b body, text string, gap bool) []byte {
+	text = "\n" + b.indent + indentLines(text, b.indent)
+	if gap && b.blocks {
+		text = "\n" + text
+	}
+	if !b.blocks {
+		text += "\n" + strings.TrimSuffix(b.indent, indentUnit)
+	}
+	return splice(src, b.last, b.last, text)
+}
+
+// addComponent puts a new component block holding field at the end of b,
+// after a blank line.
+func addComponent(src []byte, b body, component entities.ComponentType, field string) []byte {
+	text := fmt.Sprintf("component %s {\n%s%s\n}", component, indentUnit, indentLines(field, indentUnit))
+	return addBlock(src, b, text, true)
+}
+
+func splice(src []byte, start, end int, text string) []byte {
+	out := make([]byte, 0, len(src)+len(text))
+	out = append(out, src[:start]...)
+	out = append(out, text...)
+	return append(out, src[end:]...)
+}
+
+// lineIndent returns the whitespace the line holding offset starts with.
+func lineIndent(src []byte, offset int) string {
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	line := src[start:offset]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// openingBrace returns the offset just past the first brace after offset.
+func openingBrace(src []byte, offset int) int {
+	return offset + bytes.IndexByte(src[offset:], '{') + 1
+}
+
+// indentLines indents every line of text but the first.
+func indentLines(text, indent string) string {
+	return strings.ReplaceAll(text, "\n", "\n"+indent)
+}
+
+func formatEntity(e *entities.Entity) (string, error) {
+	if !identPattern.MatchString(e.TemplateID) {
+		return "", fmt.Errorf("'%s' can't be used as an ID", e.TemplateID)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "entity %s {\n", e.TemplateID)
+	fields := map[string]models.Value{
+		"name":        models.VStr(e.Name),
+		"description": models.VStr(e.Description),
+		"aliases":     {K: models.KindStringList, SL: e.Aliases},
+		"tags":        {K: models.KindStringList, SL: e.Tags},
+	}
+	keys := []string{"name", "description", "aliases", "tags"}
+	keys = append(keys, slices.Sorted(maps.Keys(e.Fields))...)
+	maps.Copy(fields, e.Fields)
+
+	for _, key := range keys {
+		if !identPattern.MatchString(key) {
+			return "", fmt.Errorf("'%s' can't be used as a field name", key)
+		}
+		literal, err := formatValue(fields[key])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", key, err)
+		}
+		if literal != "" {
+			fmt.Fprintf(&b, "%s%s is %s\n", indentUnit, key, literal)
+		}
+	}
+
+	for _, c := range e.Components() {
+		text, err := formatComponent(c)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\n%s%s\n", indentUnit, indentLines(text, indentUnit))
+	}
+
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+func formatComponent(c entities.Component) (string, error) {
+	var lines []string
+	switch v := c.(type) {
+	case *components.Room:
+		lines = append(lines, "icon is "+strconv.Quote(v.MapIcon))
+		if v.MapColor != "" {
+			lines = append(lines, "color is "+strconv.Quote(v.MapColor))
+		}
+		if exits := v.Exits(); len(exits) > 0 {
+			lines = append(lines, "exits is "+formatExits(exits))
+		}
+	case *components.Inventory, *components.Container:
+		lines = append(lines, "revealed is "+strconv.FormatBool(c.(entities.ComponentWithChildren).GetChildren().GetRevealed()))
+	default:
+		return "", fmt.Errorf("can't write a %s component", c.Id())
+	}
+
+	var children []string
+	for _, child := range c.(entities.ComponentWithChildren).GetChildren().GetChildren() {
+		children = append(children, child.TemplateID)
+	}
+	if len(children) > 0 {
+		literal, _ := formatValue(models.Value{K: models.KindStringList, SL: children})
+		lines = append(lines, "children is "+literal)
+	}
+
+	for i, line := range lines {
+		lines[i] = indentUnit + indentLines(line, indentUnit)
+	}
+	return fmt.Sprintf("component %s {\n%s\n}", c.Id(), strings.Join(lines, "\n")), nil
+}
+
+// formatValue writes v as a DSL literal. Empty lists come out empty, since
+// the DSL can't write them.
+func formatValue(v models.Value) (string, error) {
+	switch v.K {
+	case models.KindNil:
+		return "nil", nil
+	case models.KindInt:
+		return strconv.Itoa(v.I), nil
+	case models.KindString:
+		return strconv.Quote(v.S), nil
+	case models.KindBool:
+		return strconv.FormatBool(v.B), nil
+	case models.KindIntList:
+		return formatList(v.IL, strconv.Itoa), nil
+	case models.KindStringList:
+		return formatList(v.SL, strconv.Quote), nil
+	case models.KindBoolList:
+		return formatList(v.BL, strconv.FormatBool), nil
+	}
+	return "", fmt.Errorf("can't write a value of kind %d", v.K)
+}
+
+func formatList[T any](items []T, format func(T) string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(mapList(items, format), ", ") + "]"
+}
+
+func mapList[T any](items []T, format func(T) string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = format(item)
+	}
+	return out
+}
+
+func formatExits(exits map[string]string) string {
+	if len(exits) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(exits))
+	for _, dir := range slices.Sorted(maps.Keys(exits)) {
+		lines = append(lines, fmt.Sprintf("%s%s: %s", indentUnit, strconv.Quote(dir), strconv.Quote(exits[dir])))
+	}
+	return "{\n" + strings.Join(lines, ",\n") + "\n}"
+}
@@ -0,0 +1,117 @@
+package dsl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+const writerSource = `// the starting room
+entity Hall {
+    name is "Hall"
+    description is "A long hall."
+    aliases is ["hall"]
+    tags is ["room", "indoors"]
+
+    component Room {
+        icon is "H"
+        exits is {
+            "north": "Tower"
+        }
+        children is [
+            "Lamp"
+        ]
+    }
+
+    react knock {
+        then {
+            print source "Nobody answers."
+        }
+    }
+}
+
+entity Tower {
+    name is "Tower"
+    description is "A tall tower."
+    aliases is ["tower"]
+
+    component Room {
+        exits is { "south": "Hall" }
+    }
+}
+
+entity Lamp {
+    name is "Lamp"
+    description is "A lamp."
+    aliases is ["lamp"]
+}
+`
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "world.mud"), []byte(writerSource), 0o644))
+	wr, err := NewWriter(dir, "built.mud")
+	require.NoError(t, err)
+
+	cellar := entities.NewEntity("Cellar", "A damp cellar.", []string{"cellar"}, []string{"room"}, map[string]models.Value{"dark": models.VBool(true)}, nil)
+	cellar.TemplateID = "Cellar"
+	room := components.NewRoom()
+	room.SetExits(map[string]string{"up": "Hall"})
+	cellar.Add(room)
+
+	require.NoError(t, wr.SetField("Hall", "name", models.VStr(`The "Great" Hall`)))
+	require.NoError(t, wr.SetField("Hall", "tags", models.Value{K: models.KindStringList}))
+	require.NoError(t, wr.SetField("Lamp", "lit", models.VBool(true)))
+	require.NoError(t, wr.SetComponentField("Hall", entities.ComponentRoom, "color", models.VStr("red")))
+	require.NoError(t, wr.SetExits("Hall", map[string]string{"north": "Tower", "down": "Cellar"}))
+	require.NoError(t, wr.AddChild("Tower", entities.ComponentRoom, "Lamp"))
+	require.NoError(t, wr.AddChild("Tower", entities.ComponentRoom, "Lamp"))
+	require.NoError(t, wr.AddChild("Hall", entities.ComponentRoom, "Lamp"))
+	require.NoError(t, wr.SetComponentField("Lamp", entities.ComponentContainer, "revealed", models.VBool(true)))
+	require.NoError(t, wr.AddEntity(cellar))
+	require.Error(t, wr.AddEntity(cellar), "IDs are unique")
+	require.Error(t, wr.SetField("Nowhere", "name", models.VStr("x")))
+	require.Error(t, wr.SetField("Hall", "component", models.VInt(1)), "changes must still parse")
+
+	src, err := os.ReadFile(filepath.Join(dir, "world.mud"))
+	require.NoError(t, err)
+	require.Contains(t, string(src), "// the starting room\nentity Hall {\n    name is \"The \\\"Great\\\" Hall\"\n")
+	require.Contains(t, string(src), "        exits is {\n            \"down\": \"Cellar\",\n            \"north\": \"Tower\"\n        }\n        children is [\n            \"Lamp\",\n            \"Lamp\"\n        ]\n        color is \"red\"\n    }\n\n    react knock")
+	require.NotContains(t, string(src), "indoors")
+
+	entityMap, _, err := LoadEntitiesFromDirectory(dir)
+	require.NoError(t, err)
+
+	hall := entityMap["Hall"]
+	require.Equal(t, `The "Great" Hall`, hall.Name)
+	require.Empty(t, hall.Tags)
+	hallRoom, ok := entities.GetComponent[*components.Room](hall)
+	require.True(t, ok)
+	require.Equal(t, "red", hallRoom.MapColor)
+	require.Equal(t, map[string]string{"north": "Tower", "down": "Cellar"}, hallRoom.Exits())
+	_, ok = entities.GetComponent[*components.Eventful](hall)
+	require.True(t, ok, "reactions are left alone")
+
+	towerRoom, _ := entities.GetComponent[*components.Room](entityMap["Tower"])
+	require.Len(t, towerRoom.GetChildren().GetChildren(), 2)
+
+	lamp := entityMap["Lamp"]
+	require.Equal(t, models.VBool(true), lamp.Fields["lit"])
+	container, ok := entities.GetComponent[*components.Container](lamp)
+	require.True(t, ok)
+	require.True(t, container.GetChildren().GetRevealed())
+
+	built := entityMap["Cellar"]
+	require.Equal(t, "A damp cellar.", built.Description)
+	require.Equal(t, models.VBool(true), built.Fields["dark"])
+	builtRoom, _ := entities.GetComponent[*components.Room](built)
+	require.Equal(t, map[string]string{"up": "Hall"}, builtRoom.Exits())
+}
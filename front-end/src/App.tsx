@@ -9,7 +9,7 @@ import MainLog from './components/MainLog'
 import ItemsPanel from './components/ItemsPanel'
 import InputBar from './components/InputBar'
 
-import type { State, Action, WSMessage, ClientMessage, Direction, RoomContent, InventoryContent, TextContent, EntityContent, MapData } from './types'
+import type { State, Action, WSMessage, ClientMessage, Direction, RoomContent, InventoryContent, MessageContent, SessionContent, TextContent, EntityContent, MapData } from './types'
 
 // ── Theme ─────────────────────────────────────────────────────────────────────
 
@@ -92,8 +92,12 @@ function reducer(state: State, action: Action): State {
           return { ...state, phase: 'playing', room: content as RoomContent }
         case 'map':
           return { ...state, map: content as MapData }
-        case 'inventory':
-          return { ...state, inventoryOpen: true, inventory: (content as InventoryContent).items }
+        case 'inventory': {
+          // an inventory-changed message refreshes the drawer without opening it
+          const c = content as InventoryContent | MessageContent
+          if ('kind' in c) return { ...state, inventory: (c.data as InventoryContent).items }
+          return { ...state, inventoryOpen: true, inventory: c.items }
+        }
         case 'main':
         default: {
           const c = content as TextContent | EntityContent
@@ -106,6 +110,8 @@ function reducer(state: State, action: Action): State {
   }
 }
 
+const MAX_RESUME_ATTEMPTS = 5
+
 const INITIAL: State = { phase: 'modal', nameError: '', lines: [], room: null, map: null, inventoryOpen: false, inventory: [] }
 
 // ── Helpers ───────────────────────────────────────────────────────────────────
@@ -117,14 +123,22 @@ function validateName(name: string): string {
   return ''
 }
 
+function validatePassword(password: string): string {
+  if (password.length < 6) return 'Password must be at least 6 characters.'
+  return ''
+}
+
 // ── App ───────────────────────────────────────────────────────────────────────
 
 export default function App() {
   const [state, dispatch] = useReducer(reducer, INITIAL)
   const [nameInput, setNameInput] = useState('')
+  const [passwordInput, setPasswordInput] = useState('')
+  const [createAccount, setCreateAccount] = useState(false)
   const [cmdInput, setCmdInput] = useState('')
   const [darkMode, setDarkMode] = useState(true)
   const ws = useRef<WebSocket | null>(null)
+  const resumeToken = useRef<string | null>(null)
   const logRef = useRef<HTMLDivElement>(null)
   const cmdRef = useRef<HTMLInputElement>(null)
   const theme = buildTheme(darkMode)
@@ -146,28 +160,53 @@ export default function App() {
     return () => window.removeEventListener('keydown', handleKeyDown)
   }, [])
 
-  function connect(name: string) {
-    const err = validateName(name)
+  function connect(name: string, password: string, create: boolean) {
+    const err = validateName(name) || validatePassword(password)
     if (err) { dispatch({ type: 'name_error', error: err }); return }
 
     dispatch({ type: 'connecting' })
-    const socket = new WebSocket(`${import.meta.env.VITE_WS_URL}?name=${encodeURIComponent(name)}`)
+    resumeToken.current = null
+    open({ type: 'login', name, password, create })
+  }
+
+  // open connects and logs in. If the connection drops without the server
+  // saying why, the player is still in the world for a while, so it logs
+  // back in with the resume token and carries on.
+  function open(login: ClientMessage, retries = 0) {
+    const socket = new WebSocket(import.meta.env.VITE_WS_URL)
     ws.current = socket
 
+    socket.onopen = () => {
+      socket.send(JSON.stringify(login))
+    }
+
     socket.onmessage = (event) => {
       try {
         const msg: WSMessage = JSON.parse(event.data)
+        if (msg.panel === 'session') {
+          resumeToken.current = (msg.content as SessionContent).token
+          retries = 0
+          return
+        }
         dispatch({ type: 'message', msg })
       } catch { /* ignore malformed frames */ }
     }
 
     socket.onclose = (event) => {
       ws.current = null
+      const token = resumeToken.current
+      if (!event.reason && token && retries < MAX_RESUME_ATTEMPTS) {
+        const resume: ClientMessage = { type: 'login', name: '', password: '', create: false, token }
+        setTimeout(() => open(resume, retries + 1), 1000 * (retries + 1))
+        return
+      }
+      resumeToken.current = null
       dispatch(event.reason ? { type: 'name_error', error: event.reason } : { type: 'disconnected' })
     }
 
     socket.onerror = () => {
       ws.current = null
+      if (resumeToken.current) return // onclose retries
       dispatch({ type: 'name_error', error: 'Could not connect to server.' })
     }
   }
@@ -215,6 +254,10 @@ export default function App() {
         phase={state.phase}
         nameInput={nameInput}
         setNameInput={setNameInput}
+        passwordInput={passwordInput}
+        setPasswordInput={setPasswordInput}
+        createAccount={createAccount}
+        setCreateAccount={setCreateAccount}
         nameError={state.nameError}
         onConnect={connect}
       />
@@ -1,18 +1,24 @@
-import { Box, Button, Dialog, DialogActions, DialogContent, DialogTitle, TextField, Typography } from '@mui/material'
+import { Box, Button, Checkbox, Dialog, DialogActions, DialogContent, DialogTitle, FormControlLabel, TextField, Typography } from '@mui/material'
 import type { Phase } from '../types'
 
 interface Props {
   phase: Phase
   nameInput: string
   setNameInput: (v: string) => void
+  passwordInput: string
+  setPasswordInput: (v: string) => void
+  createAccount: boolean
+  setCreateAccount: (v: boolean) => void
   nameError: string
-  onConnect: (name: string) => void
+  onConnect: (name: string, password: string, create: boolean) => void
 }
 
-export default function NameDialog({ phase, nameInput, setNameInput, nameError, onConnect }: Props) {
+export default function NameDialog({
+  phase, nameInput, setNameInput, passwordInput, setPasswordInput, createAccount, setCreateAccount, nameError, onConnect,
+}: Props) {
   return (
     <Dialog open={phase !== 'playing'}>
-      <Box component="form" onSubmit={(e) => { e.preventDefault(); onConnect(nameInput.trim()) }}>
+      <Box component="form" onSubmit={(e) => { e.preventDefault(); onConnect(nameInput.trim(), passwordInput, createAccount) }}>
         <DialogTitle>Enter the World</DialogTitle>
         <DialogContent sx={{ display: 'flex', flexDirection: 'column', gap: 2, pt: '8px !important', width: 300 }}>
           <Typography variant="body2" color="text.secondary">
@@ -24,7 +30,21 @@ export default function NameDialog({ phase, nameInput, setNameInput, nameError,
             disabled={phase === 'connecting'}
             autoFocus
             size="small"
-            slotProps={{ htmlInput: { spellCheck: false, autoComplete: 'off', maxLength: 20 } }}
+            slotProps={{ htmlInput: { spellCheck: false, autoComplete: 'username', maxLength: 20 } }}
+          />
+          <TextField
+            type="password"
+            label="Password"
+            value={passwordInput}
+            onChange={(e) => setPasswordInput(e.target.value)}
+            disabled={phase === 'connecting'}
+            size="small"
+            slotProps={{ htmlInput: { autoComplete: createAccount ? 'new-password' : 'current-password' } }}
+          />
+          <FormControlLabel
+            control={<Checkbox size="small" checked={createAccount} onChange={(e) => setCreateAccount(e.target.checked)} />}
+            label={<Typography variant="body2">I'm new here</Typography>}
+            disabled={phase === 'connecting'}
           />
           {nameError && (
             <Typography variant="caption" color="error">{nameError}</Typography>
@@ -32,7 +52,7 @@ export default function NameDialog({ phase, nameInput, setNameInput, nameError,
         </DialogContent>
         <DialogActions>
           <Button type="submit" disabled={phase === 'connecting'} fullWidth variant="outlined">
-            {phase === 'connecting' ? 'Connecting…' : 'Enter'}
+            {phase === 'connecting' ? 'Connecting…' : createAccount ? 'Create' : 'Enter'}
           </Button>
         </DialogActions>
       </Box>
@@ -8,17 +8,21 @@ export interface RoomContent {
 export interface TextContent { text: string }
 export interface EntityContent { name: string; description: string }
 export interface InventoryContent { items: string[] }
+export interface SessionContent { token: string }
+export type MessageKind = 'narrative' | 'room-changed' | 'inventory-changed' | 'channel' | 'system'
+export interface MessageContent { kind: MessageKind; text?: string; channel?: string; data?: unknown }
 export interface MapCell { color: string; icon: string }
 export interface MapData { grid: MapCell[][]; playerX: number; playerY: number }
 
 export type Direction = 'north' | 'south' | 'east' | 'west' | 'up' | 'down' | 'in' | 'out'
 
 export type ClientMessage =
+  | { type: 'login'; name: string; password: string; create: boolean; token?: string }
   | { type: 'text'; text: string }
   | { type: 'move'; direction: Direction }
 
 export interface WSMessage {
-  panel: 'main' | 'map' | 'inventory' | 'room'
+  panel: 'main' | 'map' | 'inventory' | 'room' | 'session'
   content: unknown
 }
 
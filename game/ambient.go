@@ -0,0 +1,65 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+
+	"example.com/mud/sdk"
+)
+
+// ambientEvery is how many engine ticks pass between ambient messages.
+const ambientEvery = 6
+
+var ambientLines = []string{
+	"A cold wind stirs, carrying the smell of rain.",
+	"Somewhere far off, a goblin giggles.",
+	"A bird calls out, and another answers.",
+	"The light shifts as a cloud passes overhead.",
+}
+
+// ambience tracks which rooms have players in them, so ambient messages
+// only go to rooms where someone can hear them.
+type ambience struct {
+	mu       sync.Mutex
+	occupied map[string]map[string]struct{} // room ID -> player IDs
+}
+
+func newAmbience() *ambience {
+	return &ambience{occupied: map[string]map[string]struct{}{}}
+}
+
+func (a *ambience) enter(roomID, playerID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.occupied[roomID] == nil {
+		a.occupied[roomID] = map[string]struct{}{}
+	}
+	a.occupied[roomID][playerID] = struct{}{}
+}
+
+func (a *ambience) leave(roomID, playerID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.occupied[roomID], playerID)
+	if len(a.occupied[roomID]) == 0 {
+		delete(a.occupied, roomID)
+	}
+}
+
+func (a *ambience) rooms() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]string, 0, len(a.occupied))
+	for id := range a.occupied {
+		out = append(out, id)
+	}
+	return out
+}
+
+// emitAmbient publishes a random ambient line into every occupied room.
+func (a *ambience) emitAmbient(emitter sdk.Emitter) {
+	for _, roomID := range a.rooms() {
+		line := ambientLines[rand.Intn(len(ambientLines))]
+		_ = emitter.Emit(sdk.Scope{RoomID: roomID}, sdk.Publish(line))
+	}
+}
@@ -11,21 +11,27 @@ var eggDef = &sdk.EntityDef{
 	Fields:             map[string]string{"angry": "false"},
 	ContainerID:        "Player",
 	ContainerComponent: "Inventory",
+	Rules: []*sdk.Rule{
+		{
+			Command: "attack",
+			When:    []sdk.Condition{sdk.Not(sdk.FieldEquals("target", "angry", true))},
+			Then: sdk.Actions(
+				sdk.SetField("target", "angry", true),
+				sdk.Print("source", "The egg is now angry that you hit it."),
+			),
+		},
+		{
+			Command: "attack",
+			Then: sdk.Actions(
+				sdk.SetField("target", "angry", false),
+				sdk.Print("source", "The egg is calmed after you strike it again"),
+			),
+		},
+	},
 }
 
 func eggReact(e *sdk.Event) []sdk.Action {
 	switch e.Command {
-	case "attack":
-		if e.Target != nil && !e.Target.FieldBool("angry") {
-			return sdk.Actions(
-				sdk.SetField("target", "angry", true),
-				sdk.Print("source", "The egg is now angry that you hit it."),
-			)
-		}
-		return sdk.Actions(
-			sdk.SetField("target", "angry", false),
-			sdk.Print("source", "The egg is calmed after you strike it again"),
-		)
 	case "take", "drop":
 		return itemReact(e)
 	}
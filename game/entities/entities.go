@@ -34,15 +34,14 @@ func All() []*sdk.EntityDef {
 	return out
 }
 
+// Dispatch routes an event to its entity's react func. Declarative Reactions
+// and Rules are run by the engine, so only events they don't match get here.
 func Dispatch(e *sdk.Event) []sdk.Action {
 	// TODO: this shouldn't be an O(N) operation
 	for _, h := range all {
 		if h.def.ID != e.TargetID {
 			continue
 		}
-		if acts, ok := h.def.Reactions[e.Command]; ok {
-			return acts
-		}
 		if h.react != nil {
 			return h.react(e)
 		}
@@ -2,6 +2,7 @@ package entities
 
 import (
 	"fmt"
+	"math/rand"
 
 	"example.com/mud/sdk"
 )
@@ -18,10 +19,19 @@ var goblinDef = &sdk.EntityDef{
 func goblinReact(e *sdk.Event) []sdk.Action {
 	switch e.Command {
 	case "attack":
-		return sdk.Actions(
+		acts := sdk.Actions(
 			sdk.Print("source", "As you throw a {'punch' | yellow} at the goblin, he jumps around you, {'kissing' | red} your forehead."),
 			sdk.Publish("{source} tries and fails to attack the goblin, yet they're rewarded with a gentle {'kiss' | red } from the creature.", "source"),
 		)
+		if e.Room == nil {
+			return acts
+		}
+		// the goblin eyes a way out, if the engine can tell us where that is
+		if exits, err := e.World.ResolveExits(e.Room.TemplateID); err == nil && len(exits) > 0 {
+			exit := exits[rand.Intn(len(exits))]
+			acts = append(acts, sdk.Print("source", fmt.Sprintf("He glances nervously %s, toward the %s.", exit.Direction, exit.RoomName)))
+		}
+		return acts
 
 	case "kiss":
 		if e.Source != nil && !e.Source.HasChildInComponent("Goblin", "Inventory") {
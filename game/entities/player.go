@@ -22,13 +22,13 @@ func playerReact(e *sdk.Event) []sdk.Action {
 	if e.Instrument != nil && e.Instrument.HasTag("player") {
 		return sdk.Actions(sdk.Print("source", fmt.Sprintf("You attempt to beat %s with %s, but they are too heavy to lift.", e.Target.Name, e.Instrument.Name)))
 	}
-	if e.Source != nil && e.Source.TemplateID == e.TargetID {
+	if e.Source != nil && e.Source.ID == e.Target.ID {
 		return sdk.Actions(
 			sdk.Print("source", "You hit yourself upon the head, hard enough to hurt."),
 			sdk.Publish("{source} hits themselves upon the head, their rage directed inward.", "source"),
 		)
 	}
-	if e.Instrument != nil && e.Instrument.TemplateID == e.TargetID {
+	if e.Instrument != nil && e.Instrument.ID == e.Target.ID {
 		return sdk.Actions(sdk.Print("source", fmt.Sprintf("You wonder if you might be able to beat %s with themselves, but disregard the idea.", e.Target.Name)))
 	}
 	return sdk.Actions(
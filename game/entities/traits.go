@@ -10,7 +10,7 @@ import (
 func itemReact(e *sdk.Event) []sdk.Action {
 	switch e.Command {
 	case "take":
-		if e.Source != nil && !e.Source.HasChildInComponent(e.TargetID, "Inventory") {
+		if e.Source != nil && !e.Source.HasChildIDInComponent(e.Target.ID, "Inventory") {
 			return sdk.Actions(
 				sdk.Print("source", fmt.Sprintf("You pocket %s", e.Target.Name)),
 				sdk.Publish(fmt.Sprintf("{source} pockets %s", e.Target.Name), "source"),
@@ -20,7 +20,7 @@ func itemReact(e *sdk.Event) []sdk.Action {
 		return sdk.Actions(sdk.Print("source", fmt.Sprintf("You're already carrying %s", e.Target.Name)))
 
 	case "drop":
-		if e.Source != nil && e.Source.HasChildInComponent(e.TargetID, "Inventory") {
+		if e.Source != nil && e.Source.HasChildIDInComponent(e.Target.ID, "Inventory") {
 			return sdk.Actions(
 				sdk.Print("source", fmt.Sprintf("You drop %s onto the ground.", e.Target.Name)),
 				sdk.Publish(fmt.Sprintf("{source} drops %s onto the ground.", e.Target.Name), "source"),
@@ -48,7 +48,7 @@ func hittableReact(e *sdk.Event) []sdk.Action {
 	if e.Command != "attack" {
 		return nil
 	}
-	if e.Instrument != nil && e.Instrument.TemplateID == e.TargetID {
+	if e.Instrument != nil && e.Instrument.ID == e.Target.ID {
 		return sdk.Actions(sdk.Print("source", "You can't hit something with itself."))
 	}
 	if e.Instrument != nil {
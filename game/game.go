@@ -10,7 +10,16 @@ import (
 )
 
 // Game implements sdk.Game, providing the world definition and event handling.
-type Game struct{}
+// It also implements sdk.UpdateHandler and sdk.EmitterReceiver so the world
+// can carry on without players typing commands.
+type Game struct {
+	emitter  sdk.Emitter
+	ambience *ambience
+}
+
+func NewGame() *Game {
+	return &Game{ambience: newAmbience()}
+}
 
 func (g *Game) GetManifest() *sdk.Manifest {
 	worldRooms := rooms.GenerateWorld()
@@ -28,3 +37,25 @@ func (g *Game) GetManifest() *sdk.Manifest {
 func (g *Game) HandleEvent(e *sdk.Event) []sdk.Action {
 	return entities.Dispatch(e)
 }
+
+func (g *Game) SetEmitter(e sdk.Emitter) {
+	g.emitter = e
+}
+
+func (g *Game) OnEngineUpdate(u *sdk.EngineUpdate) []sdk.Action {
+	switch u.Kind {
+	case sdk.UpdatePlayerJoined:
+		g.ambience.enter(u.RoomID, u.Entity.ID)
+		return sdk.Actions(sdk.Print("source", "The air here hums faintly, as if the world noticed you arrive."))
+	case sdk.UpdatePlayerLeft:
+		g.ambience.leave(u.RoomID, u.Entity.ID)
+	case sdk.UpdateEntityMoved:
+		g.ambience.leave(u.FromRoomID, u.Entity.ID)
+		g.ambience.enter(u.RoomID, u.Entity.ID)
+	case sdk.UpdateTick:
+		if g.emitter != nil && u.Tick%ambientEvery == 0 {
+			g.ambience.emitAmbient(g.emitter)
+		}
+	}
+	return nil
+}
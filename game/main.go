@@ -20,11 +20,11 @@ func main() {
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer cancel()
 
-		if err := orbisplugin.ServeDebug(ctx, sdk.NewAdapter(&Game{})); err != nil {
+		if err := orbisplugin.ServeDebug(ctx, sdk.NewAdapter(NewGame())); err != nil {
 			log.Fatalf("debug serve: %v", err)
 		}
 		return
 	}
 
-	sdk.Serve(&Game{})
+	sdk.Serve(NewGame())
 }
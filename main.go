@@ -1,191 +1,107 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"errors"
 	"flag"
 	"fmt"
 	"log"
-	"net"
-	"net/http"
-	"strconv"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
+	"example.com/mud/account"
 	"example.com/mud/config"
+	"example.com/mud/dsl"
 	"example.com/mud/parser/commands"
 	orbisplugin "example.com/mud/plugin"
 	"example.com/mud/server"
+	"example.com/mud/session"
 	"example.com/mud/world"
-	"example.com/mud/world/entities"
-	"example.com/mud/world/player"
+	"example.com/mud/world/persist"
 )
 
-func handleConnection(conn net.Conn, gameWorld *world.World, cfg *config.Config) {
-	defer conn.Close()
-	reader := bufio.NewReader(conn)
-	var name string
-
-	for {
-		if _, err := fmt.Fprint(conn, "What is your name, weary adventurer? "); err != nil {
-			return
-		}
-
-		name, _ = reader.ReadString('\n')
-		name = strings.TrimSpace(name)
-
-		vdn := player.NameValidation(name)
-		if vdn != "" {
-			fmt.Fprint(conn, vdn)
-			continue
-		}
-		break
-	}
-
-	inbox := make(chan string, 64)
-	p, err := gameWorld.AddPlayer(name, inbox)
-	if err != nil {
-		err := fmt.Errorf("error adding player: %w", err)
-		fmt.Println(err.Error())
-		fmt.Fprintln(conn, err.Error())
-		return
+// shutdownOnSignal saves the world and every online player when the server is
+// asked to stop, by a signal or an admin, then exits.
+func shutdownOnSignal(gameWorld *world.World, requested <-chan struct{}, cleanup func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-signals:
+	case <-requested:
 	}
 
-	opening, err := p.OpeningMessage()
-	if err != nil {
-		err := fmt.Errorf("error printing opening message: %w", err)
-		fmt.Println(err.Error())
-		fmt.Fprintln(conn, err.Error())
-		return
-	}
-	rendered, err := player.RenderForTelnet(opening)
-	if err != nil {
-		fmt.Fprintln(conn, err.Error())
-		return
+	fmt.Println("Shutting down...")
+	gameWorld.SavePlayers()
+	if name, err := gameWorld.TakeSnapshot(); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("Saved world snapshot", name)
 	}
-	fmt.Fprintln(conn, rendered)
-
-	// start consuming incoming messages
-	go handleConnectionIncoming(conn, inbox)
-
-	// notify when outgoing messages end
-	done := make(chan struct{})
-	go func() {
-		handleConnectionOutgoing(conn, gameWorld, p, cfg)
-		close(done)
-	}()
-
-	// Wait until the outgoing loop ends
-	<-done
-}
-
-func handleConnectionIncoming(conn net.Conn, inbox chan string) {
-	go func() {
-		for msg := range inbox {
-			// Use CRLF for telnet clients
-			fmt.Fprint(conn, msg+"\r\n")
-		}
-	}()
+	cleanup()
+	os.Exit(0)
 }
 
-func handleConnectionOutgoing(conn net.Conn, gameWorld *world.World, p *player.Player, cfg *config.Config) {
-	scanner := bufio.NewScanner(conn)
-	for {
-		if !scanner.Scan() {
-			break
-		}
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		if strings.ToLower(line) == "quit" {
-			break
-		}
-
-		// check if player has pending multi-part messages
-		if pending := p.Pending; pending != nil {
-			n, nerr := strconv.Atoi(line)
-			if nerr == nil {
-				slot := pending.Ambiguity.Slots[pending.StepIndex]
-				if n >= 1 && n <= len(slot.Matches) {
-					pending.Selected[slot.Role] = n - 1
-					pending.StepIndex++
-					if pending.StepIndex < len(pending.Ambiguity.Slots) {
-						// continue prompting current slot
-						promptCurrentSlot(conn, pending)
-					} else {
-						chosen := make(map[string]*entities.Entity, len(pending.Selected))
-						for _, s := range pending.Ambiguity.Slots {
-							idx := pending.Selected[s.Role]
-							chosen[s.Role] = s.Matches[idx].Entity
-						}
-						out, execErr := pending.Ambiguity.Execute(chosen)
-						p.Pending = nil
-						if execErr != nil {
-							fmt.Fprintln(conn, execErr.Error())
-						} else if out != "" {
-							fmt.Fprintln(conn, out)
-						}
-					}
-				}
-				continue
-			}
-			// non-number input falls through and removes pending action
-			p.Pending = nil
-		}
-
-		if coolDownTime := p.CooldownRemaining(); coolDownTime > 0 {
-			fmt.Fprintf(conn, "You need to catch your breath. Try again in %.1fs\r\n", coolDownTime.Seconds())
-			continue
-		}
-
-		resp, err := gameWorld.Parse(p, line)
-		if err != nil {
-			var amb *entities.AmbiguityError
-			if errors.As(err, &amb) {
-				p.Pending = &entities.PendingAction{
-					Ambiguity: amb,
-					StepIndex: 0,
-					Selected:  map[string]int{},
-				}
-				promptCurrentSlot(conn, p.Pending)
-				continue
-			}
-			err := fmt.Errorf("error received: %w", err)
-			fmt.Println(err.Error())
-			fmt.Fprintln(conn, err.Error())
-		} else if resp != nil {
-			rendered, renderErr := player.RenderForTelnet(resp)
-			if renderErr != nil {
-				fmt.Fprintln(conn, renderErr.Error())
-			} else if rendered != "" {
-				fmt.Fprintln(conn, rendered)
-			}
+// reloadCertsOnHangup reloads the TLS listeners' certificates on SIGHUP, so
+// renewed certificates are picked up without a restart.
+func reloadCertsOnHangup(srv *server.Server) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		if err := srv.ReloadCerts(); err != nil {
+			fmt.Println("Reloading certificates:", err)
+		} else {
+			fmt.Println("Reloaded certificates")
 		}
-
-		p.StartCooldown(time.Duration(cfg.PlayerRateLimit) * time.Millisecond)
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Connection error:", err)
+// reloadWorld picks up changes to the world's files, telling the admins
+// online how it went.
+func reloadWorld(gameWorld *world.World) {
+	summary, err := gameWorld.Reload()
+	if err != nil {
+		fmt.Println(err)
+		gameWorld.TellAdmins(fmt.Sprintf("The world's files changed, but couldn't be reloaded: %v", err))
+		return
 	}
-
-	gameWorld.DisconnectPlayer(p)
-	fmt.Printf("Connection closed\n")
+	fmt.Println("Reloaded the world:", summary)
+	gameWorld.TellAdmins(fmt.Sprintf("The world's files changed, and were reloaded: %s.", summary))
 }
 
-func promptCurrentSlot(conn net.Conn, p *entities.PendingAction) {
-	slot := p.Ambiguity.Slots[p.StepIndex]
-	fmt.Fprintln(conn, slot.Prompt)
-	for i, opt := range slot.Matches {
-		fmt.Fprintf(conn, "  %d) %s\r\n", i+1, opt.Text)
+func main() {
+	// with no subcommand, or just flags, serve as before there were any
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		serve(args)
+	case "check":
+		os.Exit(check(args))
+	case "compile":
+		os.Exit(compile(args))
+	case "plugin-info":
+		os.Exit(pluginInfo(args))
+	case "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", command)
+		usage()
+		os.Exit(2)
 	}
 }
 
-func main() {
-	debug := flag.Bool("debug", false, "connect to a game binary already running with -debug instead of launching a subprocess")
-	flag.Parse()
+// serve runs the game server with the world from config.yaml.
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = subcommandUsage(flags, "serve [-debug]", "Start the server with the world from config.yaml.")
+	debug := flags.Bool("debug", false, "with a plugin world source, connect to a game binary already running with -debug instead of launching a subprocess")
+	flags.Parse(args)
 
 	// load configuration file
 	cfg, err := config.Load("config.yaml")
@@ -193,66 +109,109 @@ func main() {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	var gameClient orbisplugin.GameClient
-	var cleanup func()
-	if *debug {
-		gameClient, cleanup, err = orbisplugin.ConnectDebug(5 * time.Second)
-	} else {
-		gameClient, cleanup, err = orbisplugin.Launch(cfg.GameBinary)
-	}
+	def, cleanup, err := loadWorldDefinition(cfg.WorldSource, *debug)
 	if err != nil {
-		log.Fatalf("failed to connect to game: %v", err)
+		log.Fatalf("failed to load world: %v", err)
 	}
 	defer cleanup()
 
-	manifest, err := gameClient.GetManifest(context.Background())
-	if err != nil {
-		log.Fatalf("failed to get game manifest: %v", err)
+	if err := commands.RegisterBuiltInCommands(); err != nil {
+		log.Fatalf("failed to register built-in commands: %v", err)
 	}
 
-	entityMap, cmds, err := orbisplugin.ManifestToWorld(manifest, gameClient)
-	if err != nil {
-		log.Fatalf("failed to build world from manifest: %v", err)
+	if err := commands.RegisterCommands(def.commands); err != nil {
+		log.Fatalf("failed to register world commands: %v", err)
 	}
 
-	// validate starting room exists in entity map
-	if _, ok := entityMap[manifest.GetStartingRoom()]; !ok {
-		log.Fatalf("room '%s' does not exist in world.", manifest.GetStartingRoom())
-	}
+	gameWorld := world.NewWorld(def.entityMap, def.startingRoom)
 
-	if err := commands.RegisterBuiltInCommands(); err != nil {
-		log.Fatalf("failed to register built-in commands: %v", err)
+	accountStore, err := account.NewFileStore(cfg.Accounts.Dir)
+	if err != nil {
+		log.Fatalf("failed to open account store: %v", err)
 	}
+	accounts := account.NewManager(accountStore)
 
-	if err := commands.RegisterCommands(cmds); err != nil {
-		log.Fatalf("failed to register DSL commands: %v", err)
+	playerStore, err := persist.NewFileStore(filepath.Join(cfg.Persistence.Dir, "players"))
+	if err != nil {
+		log.Fatalf("failed to open player store: %v", err)
+	}
+	gameWorld.SetPlayerStore(playerStore)
+	if cfg.Persistence.AutosaveInterval > 0 {
+		gameWorld.StartAutosave(time.Duration(cfg.Persistence.AutosaveInterval) * time.Second)
 	}
 
-	gameWorld := world.NewWorld(entityMap, manifest.GetStartingRoom())
-
-	go func() {
-		addr := fmt.Sprintf(":%d", cfg.WebSocketPort)
-		http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-			server.HandleWS(w, r, gameWorld, cfg)
-		})
-		fmt.Printf("WebSocket server listening on port %d...\n", cfg.WebSocketPort)
-		log.Fatal(http.ListenAndServe(addr, nil))
-	}()
+	snapshotStore, err := persist.NewSnapshotStore(filepath.Join(cfg.Persistence.Dir, "snapshots"), cfg.Persistence.SnapshotKeep)
+	if err != nil {
+		log.Fatalf("failed to open snapshot store: %v", err)
+	}
+	gameWorld.SetSnapshotStore(snapshotStore)
+	gameWorld.SetAdmins(cfg.Admins)
+	if def.source != nil {
+		gameWorld.SetSourceWriter(def.source)
+	}
+	if def.reload != nil {
+		gameWorld.SetLoader(def.reload)
+		if cfg.WorldSource.WatchInterval > 0 {
+			go dsl.Watch(cfg.WorldSource.DataDir, time.Duration(cfg.WorldSource.WatchInterval)*time.Second, func() {
+				reloadWorld(gameWorld)
+			})
+		}
+	}
+	gameWorld.SetChat(world.ChatOptions{
+		Channels:    cfg.Chat.Channels,
+		HistorySize: cfg.Chat.HistorySize,
+		RateLimit:   time.Duration(cfg.Chat.RateLimit) * time.Millisecond,
+		ShoutRange:  cfg.Chat.ShoutRange,
+	})
 
-	listener, err := net.Listen("tcp", ":4000")
+	// pick up where the last run left off, before anyone can log in
+	restored, err := gameWorld.RestoreLatestSnapshot()
 	if err != nil {
-		panic(err)
+		log.Fatalf("failed to restore world snapshot: %v", err)
+	}
+	if restored != "" {
+		fmt.Println("Restored world snapshot", restored)
 	}
-	defer listener.Close()
+	if cfg.Persistence.SnapshotInterval > 0 {
+		gameWorld.StartSnapshots(time.Duration(cfg.Persistence.SnapshotInterval) * time.Second)
+	}
+
+	sessions := session.NewManager(gameWorld, accounts, cfg)
+	gameWorld.SetOperator(sessions)
+	go shutdownOnSignal(gameWorld, sessions.Stopping(), cleanup)
 
-	fmt.Println("MUD server listening on port 4000...")
+	if def.gameClient != nil {
+		if err := def.gameClient.ServeEngine(gameWorld); err != nil {
+			log.Fatalf("failed to serve engine queries: %v", err)
+		}
 
-	for {
-		conn, err := listener.Accept()
+		tick := time.Duration(cfg.WorldSource.TickInterval) * time.Millisecond
+		stream, err := orbisplugin.StartEventStream(context.Background(), def.gameClient, gameWorld, tick)
 		if err != nil {
-			fmt.Println("Error accepting connection:", err)
-			continue
+			log.Fatalf("failed to start plugin event stream: %v", err)
+		}
+		gameWorld.AddObserver(stream)
+		gameWorld.SetPluginReporter(def.guard)
+
+		if def.supervisor != nil {
+			def.supervisor.SetReporter(func(text string) {
+				fmt.Println(text)
+				gameWorld.TellAdmins(text)
+			})
+			def.supervisor.OnSwap(func() {
+				if err := stream.Reopen(); err != nil {
+					fmt.Println(err)
+				}
+			})
 		}
-		go handleConnection(conn, gameWorld, cfg)
 	}
+
+	srv := server.New(cfg, sessions, accounts)
+	if err := srv.Start(); err != nil {
+		log.Fatalf("failed to start listeners: %v", err)
+	}
+	go reloadCertsOnHangup(srv)
+
+	// listeners and the signal handlers run until the process exits
+	select {}
 }
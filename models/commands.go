@@ -4,6 +4,7 @@ type CommandDefinition struct {
 	Name     string
 	Aliases  []string
 	Patterns []CommandPattern
+	Role     Role // needed to use the command; empty for everyone
 }
 
 type CommandPattern struct {
@@ -8,3 +8,13 @@ const (
 	DirectionUp    = "up"
 	DirectionDown  = "down"
 )
+
+// OppositeDirections maps each direction to the one that leads back.
+var OppositeDirections = map[string]string{
+	DirectionNorth: DirectionSouth,
+	DirectionSouth: DirectionNorth,
+	DirectionEast:  DirectionWest,
+	DirectionWest:  DirectionEast,
+	DirectionUp:    DirectionDown,
+	DirectionDown:  DirectionUp,
+}
@@ -0,0 +1,38 @@
+package models
+
+import "fmt"
+
+// Role is what an account is allowed to do. Each role can do everything the
+// roles before it can.
+type Role string
+
+const (
+	RolePlayer  Role = "player"
+	RoleBuilder Role = "builder"
+	RoleAdmin   Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	"":          0, // accounts from before roles
+	RolePlayer:  0,
+	RoleBuilder: 1,
+	RoleAdmin:   2,
+}
+
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if _, ok := roleRanks[r]; !ok || r == "" {
+		return "", fmt.Errorf("unknown role '%s'", s)
+	}
+	return r, nil
+}
+
+// Allows reports whether r can do what needs the role need.
+func (r Role) Allows(need Role) bool {
+	return roleRanks[r] >= roleRanks[need]
+}
+
+// Outranks reports whether r can do more than other.
+func (r Role) Outranks(other Role) bool {
+	return roleRanks[r] > roleRanks[other]
+}
@@ -3,17 +3,38 @@ package models
 import "strings"
 
 // ParseSyntax parses a command syntax string like "attack {target} with {instrument}"
-// into a slice of PatTokens (the same format used by the DSL compiler).
+// into a slice of PatTokens (the same format used by the DSL compiler). A
+// last slot like "{message...}" takes the rest of the input.
 func ParseSyntax(syntax string) []PatToken {
 	parts := strings.Fields(syntax)
 	tokens := make([]PatToken, 0, len(parts))
-	for _, part := range parts {
+	for i, part := range parts {
 		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
 			slot := strings.TrimSuffix(strings.TrimPrefix(part, "{"), "}")
-			tokens = append(tokens, PatToken{SlotName: slot})
+			if i == len(parts)-1 && strings.HasSuffix(slot, "...") {
+				tokens = append(tokens, SlotRest(strings.TrimSuffix(slot, "...")))
+			} else {
+				tokens = append(tokens, PatToken{SlotName: slot})
+			}
 		} else {
 			tokens = append(tokens, PatToken{Literal: part})
 		}
 	}
 	return tokens
 }
+
+// FormatSyntax is the inverse of ParseSyntax.
+func FormatSyntax(tokens []PatToken) string {
+	parts := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		switch {
+		case t.Literal != "":
+			parts = append(parts, t.Literal)
+		case t.SlotIsRest:
+			parts = append(parts, "{"+t.SlotName+"...}")
+		default:
+			parts = append(parts, "{"+t.SlotName+"}")
+		}
+	}
+	return strings.Join(parts, " ")
+}
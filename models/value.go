@@ -114,3 +114,23 @@ func FromAny(x any) (Value, error) {
 		return Value{}, fmt.Errorf("unsupported literal type %T", x)
 	}
 }
+
+// Equals reports whether two scalar values have the same kind and contents.
+// Lists are never equal, matching the expression evaluator.
+func (v Value) Equals(o Value) bool {
+	if v.K != o.K {
+		return false
+	}
+	switch v.K {
+	case KindInt:
+		return v.I == o.I
+	case KindString:
+		return v.S == o.S
+	case KindBool:
+		return v.B == o.B
+	case KindNil:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// valueJSON is the saved form of a Value, keeping the kind explicit, e.g.
+// {"kind": "int", "value": 3}.
+type valueJSON struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+var kindNames = map[Kind]string{
+	KindNil:        "nil",
+	KindInt:        "int",
+	KindIntList:    "ints",
+	KindString:     "string",
+	KindStringList: "strings",
+	KindBool:       "bool",
+	KindBoolList:   "bools",
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	name, ok := kindNames[v.K]
+	if !ok {
+		return nil, fmt.Errorf("cannot encode value of kind %d", v.K)
+	}
+
+	var payload any
+	switch v.K {
+	case KindInt:
+		payload = v.I
+	case KindIntList:
+		payload = v.IL
+	case KindString:
+		payload = v.S
+	case KindStringList:
+		payload = v.SL
+	case KindBool:
+		payload = v.B
+	case KindBoolList:
+		payload = v.BL
+	}
+
+	out := valueJSON{Kind: name}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		out.Value = raw
+	}
+	return json.Marshal(out)
+}
+
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var in valueJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	var out Value
+	var target any
+	switch in.Kind {
+	case "nil":
+		*v = VNil()
+		return nil
+	case "int":
+		out.K, target = KindInt, &out.I
+	case "ints":
+		out.K, target = KindIntList, &out.IL
+	case "string":
+		out.K, target = KindString, &out.S
+	case "strings":
+		out.K, target = KindStringList, &out.SL
+	case "bool":
+		out.K, target = KindBool, &out.B
+	case "bools":
+		out.K, target = KindBoolList, &out.BL
+	default:
+		return fmt.Errorf("unknown value kind '%s'", in.Kind)
+	}
+
+	if err := json.Unmarshal(in.Value, target); err != nil {
+		return fmt.Errorf("decode %s value: %w", in.Kind, err)
+	}
+	*v = out
+	return nil
+}
@@ -0,0 +1,204 @@
+package commands
+
+import "example.com/mud/models"
+
+var gotoCommand = models.CommandDefinition{
+	Name:    "goto",
+	Aliases: []string{"goto"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("goto"),
+				models.SlotRest("room"),
+			},
+			HelpMessage: "Go straight to a room, by ID, or to the room a player is in.",
+		},
+	},
+}
+
+var transferCommand = models.CommandDefinition{
+	Name:    "transfer",
+	Aliases: []string{"transfer", "summon"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("transfer"),
+				models.Slot("player"),
+			},
+			HelpMessage: "Bring a player to the room you're in.",
+		},
+	},
+}
+
+var kickCommand = models.CommandDefinition{
+	Name:    "kick",
+	Aliases: []string{"kick"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("kick"),
+				models.Slot("player"),
+			},
+			HelpMessage: "Disconnect a player and take them out of the world.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("kick"),
+				models.Slot("player"),
+				models.SlotRest("reason"),
+			},
+			HelpMessage: "Kick a player, telling them why.",
+		},
+	},
+}
+
+var banCommand = models.CommandDefinition{
+	Name:    "ban",
+	Aliases: []string{"ban"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("ban"),
+				models.Slot("player"),
+			},
+			HelpMessage: "Kick a player and stop their account from logging in.",
+		},
+	},
+}
+
+var unbanCommand = models.CommandDefinition{
+	Name:    "unban",
+	Aliases: []string{"unban"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("unban"),
+				models.Slot("player"),
+			},
+			HelpMessage: "Let a banned account log in again.",
+		},
+	},
+}
+
+var roleCommand = models.CommandDefinition{
+	Name:    "role",
+	Aliases: []string{"role"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("role"),
+				models.Slot("player"),
+				models.Slot("role"),
+			},
+			HelpMessage: "Make an account a player, builder or admin.",
+		},
+	},
+}
+
+var broadcastCommand = models.CommandDefinition{
+	Name:    "broadcast",
+	Aliases: []string{"broadcast"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("broadcast"),
+				models.SlotRest("message"),
+			},
+			HelpMessage: "Announce something to every player online.",
+		},
+	},
+}
+
+var shutdownCommand = models.CommandDefinition{
+	Name:    "shutdown",
+	Aliases: []string{"shutdown"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("shutdown"),
+			},
+			HelpMessage: "Save everything and stop the server.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("shutdown"),
+				models.Slot("delay"),
+			},
+			HelpMessage: `Stop the server after a number of seconds, or "cancel" a shutdown that's coming.`,
+		},
+	},
+}
+
+var forceCommand = models.CommandDefinition{
+	Name:    "force",
+	Aliases: []string{"force"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("force"),
+				models.Slot("player"),
+				models.SlotRest("command"),
+			},
+			HelpMessage: "Make a player run a command, as though they'd typed it.",
+		},
+	},
+}
+
+var statCommand = models.CommandDefinition{
+	Name:    "stat",
+	Aliases: []string{"stat"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("stat"),
+				models.SlotRest("entity"),
+			},
+			HelpMessage: `Show an entity's fields and components. Name it by alias, ID, "here" or "me".`,
+		},
+	},
+}
+
+var purgeCommand = models.CommandDefinition{
+	Name:    "purge",
+	Aliases: []string{"purge"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("purge"),
+			},
+			HelpMessage: "Destroy everything in the room but the players.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("purge"),
+				models.SlotRest("entity"),
+			},
+			HelpMessage: "Destroy one thing in the room or your inventory.",
+		},
+	},
+}
+
+var reloadCommand = models.CommandDefinition{
+	Name:    "reload",
+	Aliases: []string{"reload"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("reload"),
+			},
+			HelpMessage: "Load the world's files, or its rebuilt game plugin, again and bring the running world up to date with them.",
+		},
+	},
+}
@@ -0,0 +1,178 @@
+package commands
+
+import "example.com/mud/models"
+
+var digCommand = models.CommandDefinition{
+	Name:    "dig",
+	Aliases: []string{"dig"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("dig"),
+				models.Slot("direction"),
+				models.Slot("room"),
+			},
+			HelpMessage: "Make a room with the given ID through a new exit, with an exit back. Digging to a room that's already there joins the two.",
+		},
+	},
+}
+
+var reditCommand = models.CommandDefinition{
+	Name:    "redit",
+	Aliases: []string{"redit"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("redit"),
+				models.Slot("field"),
+				models.SlotRest("value"),
+			},
+			HelpMessage: "Change the room's name, description, icon or color.",
+		},
+	},
+}
+
+var osetCommand = models.CommandDefinition{
+	Name:    "oset",
+	Aliases: []string{"oset"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			// before the pattern below, which would take -template for the entity
+			Tokens: []models.PatToken{
+				models.Lit("oset"),
+				models.Lit("-template"),
+				models.Slot("template"),
+				models.Slot("field"),
+				models.SlotRest("value"),
+			},
+			HelpMessage: "Set a field on something and on the template it was made from, so new copies have it too.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("oset"),
+				models.Slot("entity"),
+				models.Slot("field"),
+				models.SlotRest("value"),
+			},
+			HelpMessage: "Set a field on something. Lists are separated by commas.",
+		},
+	},
+}
+
+var ocreateCommand = models.CommandDefinition{
+	Name:    "ocreate",
+	Aliases: []string{"ocreate"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("ocreate"),
+				models.Slot("template"),
+			},
+			HelpMessage: "Make something from a template, here in the room, for good.",
+		},
+	},
+}
+
+var tagCommand = models.CommandDefinition{
+	Name:    "tag",
+	Aliases: []string{"tag"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("tag"),
+				models.Slot("entity"),
+				models.Slot("tag"),
+			},
+			HelpMessage: "Give something a tag.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("tag"),
+				models.Lit("-template"),
+				models.Slot("template"),
+				models.Slot("tag"),
+			},
+			HelpMessage: "Tag something and the template it was made from.",
+		},
+	},
+}
+
+var untagCommand = models.CommandDefinition{
+	Name:    "untag",
+	Aliases: []string{"untag"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("untag"),
+				models.Slot("entity"),
+				models.Slot("tag"),
+			},
+			HelpMessage: "Take a tag off something.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("untag"),
+				models.Lit("-template"),
+				models.Slot("template"),
+				models.Slot("tag"),
+			},
+			HelpMessage: "Untag something and the template it was made from.",
+		},
+	},
+}
+
+var aliasCommand = models.CommandDefinition{
+	Name:    "alias",
+	Aliases: []string{"alias"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("alias"),
+				models.Slot("entity"),
+				models.Slot("alias"),
+			},
+			HelpMessage: "Give something another name players can call it by.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("alias"),
+				models.Lit("-template"),
+				models.Slot("template"),
+				models.Slot("alias"),
+			},
+			HelpMessage: "Give something and the template it was made from another alias.",
+		},
+	},
+}
+
+var unaliasCommand = models.CommandDefinition{
+	Name:    "unalias",
+	Aliases: []string{"unalias"},
+	Role:    models.RoleBuilder,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("unalias"),
+				models.Slot("entity"),
+				models.Slot("alias"),
+			},
+			HelpMessage: "Stop something answering to one of its aliases.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("unalias"),
+				models.Lit("-template"),
+				models.Slot("template"),
+				models.Slot("alias"),
+			},
+			HelpMessage: "Take an alias off something and the template it was made from.",
+		},
+	},
+}
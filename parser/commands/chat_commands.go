@@ -0,0 +1,150 @@
+package commands
+
+import "example.com/mud/models"
+
+var sayCommand = models.CommandDefinition{
+	Name:    "say",
+	Aliases: []string{"say"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("say"),
+				models.SlotRest("message"),
+			},
+			HelpMessage: "Say something to everyone in the room.",
+		},
+	},
+}
+
+var tellCommand = models.CommandDefinition{
+	Name:    "tell",
+	Aliases: []string{"tell", "whisper"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("tell"),
+				models.Slot("player"),
+				models.SlotRest("message"),
+			},
+			HelpMessage: "Say something to one player, wherever they are.",
+		},
+	},
+}
+
+var shoutCommand = models.CommandDefinition{
+	Name:    "shout",
+	Aliases: []string{"shout", "yell"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("shout"),
+				models.SlotRest("message"),
+			},
+			HelpMessage: "Shout something loud enough to be heard a few rooms away.",
+		},
+	},
+}
+
+var chatCommand = models.CommandDefinition{
+	Name:    "chat",
+	Aliases: []string{"chat"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("chat"),
+				models.Slot("channel"),
+				models.SlotRest("message"),
+			},
+			HelpMessage: "Say something on a channel you've joined.",
+		},
+	},
+}
+
+var channelsCommand = models.CommandDefinition{
+	Name:    "channels",
+	Aliases: []string{"channels"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("channels"),
+			},
+			HelpMessage: "List the chat channels, and which you've joined.",
+		},
+	},
+}
+
+var joinCommand = models.CommandDefinition{
+	Name:    "join",
+	Aliases: []string{"join"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("join"),
+				models.Slot("channel"),
+			},
+			HelpMessage: "Join a chat channel.",
+		},
+	},
+}
+
+var leaveCommand = models.CommandDefinition{
+	Name:    "leave",
+	Aliases: []string{"leave"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("leave"),
+				models.Slot("channel"),
+			},
+			HelpMessage: "Leave a chat channel.",
+		},
+	},
+}
+
+var historyCommand = models.CommandDefinition{
+	Name:    "history",
+	Aliases: []string{"history"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("history"),
+				models.Slot("channel"),
+			},
+			HelpMessage: "Show what was said lately on a chat channel.",
+		},
+	},
+}
+
+var ignoreCommand = models.CommandDefinition{
+	Name:    "ignore",
+	Aliases: []string{"ignore"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("ignore"),
+			},
+			HelpMessage: "List the players you're ignoring.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("ignore"),
+				models.Slot("player"),
+			},
+			HelpMessage: "Stop hearing anything a player says, on any channel.",
+		},
+	},
+}
+
+var unignoreCommand = models.CommandDefinition{
+	Name:    "unignore",
+	Aliases: []string{"unignore"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("unignore"),
+				models.Slot("player"),
+			},
+			HelpMessage: "Hear a player you were ignoring again.",
+		},
+	},
+}
@@ -2,10 +2,15 @@ package commands
 
 import (
 	"fmt"
+	"sync"
 
 	"example.com/mud/models"
 )
 
+// mu guards the registered commands, which are replaced while players are
+// parsing when the world is reloaded.
+var mu sync.RWMutex
+
 var Commands = map[string]struct{}{}
 
 var DirectionAliases = map[string]string{
@@ -32,25 +37,133 @@ var VerbAliases = map[string]string{}
 
 var Patterns = []models.Pattern{}
 
+// Roles holds the role each restricted command needs, by command name.
+var Roles = map[string]models.Role{}
+
+// builtIns are the engine's own commands, kept when the world's commands are
+// replaced.
+var builtIns []*models.CommandDefinition
+
+// RequiredRole returns the role needed to use a command.
+func RequiredRole(kind string) models.Role {
+	mu.RLock()
+	defer mu.RUnlock()
+	if role, ok := Roles[kind]; ok {
+		return role
+	}
+	return models.RolePlayer
+}
+
+// CanonicalVerb returns the command name a verb is an alias of.
+func CanonicalVerb(verb string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	canonical, ok := VerbAliases[verb]
+	return canonical, ok
+}
+
+// AllPatterns returns every registered pattern, in the order they're tried.
+func AllPatterns() []models.Pattern {
+	mu.RLock()
+	defer mu.RUnlock()
+	return Patterns
+}
+
 func RegisterBuiltInCommands() error {
-	return RegisterCommands([]*models.CommandDefinition{
+	builtIns = []*models.CommandDefinition{
 		&helpCommand,
 		&inventoryCommand,
 		&lookCommand,
 		&moveCommand,
 		&mapCommand,
 		&trackCommand,
-	})
+		&whoCommand,
+		&sshkeyCommand,
+		&snapshotCommand,
+		&rollbackCommand,
+		&outboxesCommand,
+		&pluginStatsCommand,
+		&sayCommand,
+		&tellCommand,
+		&shoutCommand,
+		&chatCommand,
+		&channelsCommand,
+		&joinCommand,
+		&leaveCommand,
+		&historyCommand,
+		&ignoreCommand,
+		&unignoreCommand,
+		&gotoCommand,
+		&transferCommand,
+		&kickCommand,
+		&banCommand,
+		&unbanCommand,
+		&roleCommand,
+		&broadcastCommand,
+		&shutdownCommand,
+		&forceCommand,
+		&statCommand,
+		&purgeCommand,
+		&reloadCommand,
+		&digCommand,
+		&reditCommand,
+		&osetCommand,
+		&ocreateCommand,
+		&tagCommand,
+		&untagCommand,
+		&aliasCommand,
+		&unaliasCommand,
+	}
+	return RegisterCommands(builtIns)
 }
 
 func RegisterCommands(defs []*models.CommandDefinition) error {
+	if err := Validate(defs); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	register(defs)
+	return nil
+}
+
+// ReplaceWorldCommands drops every command but the built-in ones and
+// registers defs in their place. If defs aren't valid nothing changes.
+func ReplaceWorldCommands(defs []*models.CommandDefinition) error {
+	if err := Validate(defs); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	Commands = map[string]struct{}{}
+	VerbAliases = map[string]string{}
+	Patterns = []models.Pattern{}
+	Roles = map[string]models.Role{}
+	register(builtIns)
+	register(defs)
+	return nil
+}
+
+// Validate checks defs can be registered, so a caller can find out before
+// changing anything else.
+func Validate(defs []*models.CommandDefinition) error {
 	for _, cd := range defs {
 		if len(cd.Aliases) == 0 {
 			return fmt.Errorf("command '%s' has no aliases", cd.Name)
 		}
+	}
+	return nil
+}
 
+func register(defs []*models.CommandDefinition) {
+	for _, cd := range defs {
 		canonical := cd.Aliases[0]
 		Commands[canonical] = struct{}{}
+		if cd.Role != "" {
+			Roles[cd.Name] = cd.Role
+		}
 
 		for _, alias := range cd.Aliases {
 			VerbAliases[alias] = canonical
@@ -65,5 +178,4 @@ func RegisterCommands(defs []*models.CommandDefinition) error {
 			})
 		}
 	}
-	return nil
 }
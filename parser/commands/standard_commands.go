@@ -108,3 +108,115 @@ var trackCommand = models.CommandDefinition{
 		},
 	},
 }
+
+var whoCommand = models.CommandDefinition{
+	Name:    "who",
+	Aliases: []string{"who"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("who"),
+			},
+			HelpMessage: "List the players online, and how long they've been idle.",
+		},
+	},
+}
+
+var sshkeyCommand = models.CommandDefinition{
+	Name:    "sshkey",
+	Aliases: []string{"sshkey"},
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("sshkey"),
+			},
+			HelpMessage: "The same as sshkey list.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("sshkey"),
+				models.Lit("list"),
+			},
+			HelpMessage: "List the public keys you can log in over SSH with.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("sshkey"),
+				models.Lit("add"),
+				models.SlotRest("key"),
+			},
+			HelpMessage: "Add a public key you can log in over SSH with.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("sshkey"),
+				models.Lit("remove"),
+				models.Slot("number"),
+			},
+			HelpMessage: "Remove one of your SSH keys, by its number in the list.",
+		},
+	},
+}
+
+var snapshotCommand = models.CommandDefinition{
+	Name:    "snapshot",
+	Aliases: []string{"snapshot"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("snapshot"),
+			},
+			HelpMessage: "Save a snapshot of the whole world.",
+		},
+	},
+}
+
+var rollbackCommand = models.CommandDefinition{
+	Name:    "rollback",
+	Aliases: []string{"rollback"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("rollback"),
+			},
+			HelpMessage: "Roll the world back to the latest snapshot.",
+		},
+		{
+			Tokens: []models.PatToken{
+				models.Lit("rollback"),
+				models.Slot("snapshot"),
+			},
+			HelpMessage: "Roll the world back to a named snapshot.",
+		},
+	},
+}
+
+var outboxesCommand = models.CommandDefinition{
+	Name:    "outboxes",
+	Aliases: []string{"outboxes"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("outboxes"),
+			},
+			HelpMessage: "Show how well each player's connection is keeping up with their messages.",
+		},
+	},
+}
+
+var pluginStatsCommand = models.CommandDefinition{
+	Name:    "pluginstats",
+	Aliases: []string{"pluginstats"},
+	Role:    models.RoleAdmin,
+	Patterns: []models.CommandPattern{
+		{
+			Tokens: []models.PatToken{
+				models.Lit("pluginstats"),
+			},
+			HelpMessage: "Show whether the game plugin is healthy, and how long it takes to handle each command.",
+		},
+	},
+}
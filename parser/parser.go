@@ -22,7 +22,7 @@ func tokenize(input string) []string {
 
 	// normalize verb aliases
 	if len(parts) > 0 {
-		if base, ok := commands.VerbAliases[parts[0]]; ok {
+		if base, ok := commands.CanonicalVerb(parts[0]); ok {
 			parts[0] = base
 		}
 	}
@@ -155,7 +155,7 @@ func Parse(input string) *models.Command {
 		return nil
 	}
 
-	for _, p := range commands.Patterns {
+	for _, p := range commands.AllPatterns() {
 		if cmd, ok := tryMatch(p, toks); ok {
 			return cmd
 		}
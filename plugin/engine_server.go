@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+)
+
+// EngineWorld is the read-only view of the world that engine queries from the
+// game are answered from.
+type EngineWorld interface {
+	EntitiesById() map[string]*entities.Entity
+	ResolveEntity(id string) (*entities.Entity, bool)
+	OnlinePlayers() []*player.Player
+}
+
+// engineServer answers the game's OrbisEngine queries against the live world.
+type engineServer struct {
+	pb.UnimplementedOrbisEngineServer
+	world EngineWorld
+}
+
+func (s *engineServer) GetEntity(ctx context.Context, q *pb.EntityQuery) (*pb.EntitySnapshot, error) {
+	e, err := s.lookup(q.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return snapshotEntity(e), nil
+}
+
+func (s *engineServer) ListChildren(ctx context.Context, q *pb.ChildrenQuery) (*pb.EntityList, error) {
+	e, err := s.lookup(q.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	var cwcs []entities.ComponentWithChildren
+	if q.GetComponent() == "" {
+		cwcs = e.GetComponentsWithChildren()
+	} else {
+		ct, err := entities.ParseComponentType(q.GetComponent())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		cwc, err := e.RequireComponentWithChildren(ct)
+		if err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		cwcs = []entities.ComponentWithChildren{cwc}
+	}
+
+	list := &pb.EntityList{}
+	for _, cwc := range cwcs {
+		for _, child := range cwc.GetChildren().GetChildren() {
+			list.Entities = append(list.Entities, snapshotEntity(child))
+		}
+	}
+	return list, nil
+}
+
+func (s *engineServer) ListPlayers(ctx context.Context, _ *pb.Empty) (*pb.PlayerList, error) {
+	list := &pb.PlayerList{}
+	for _, p := range s.world.OnlinePlayers() {
+		list.Players = append(list.Players, &pb.PlayerInfo{
+			Player:     snapshotEntity(p.Entity),
+			RoomId:     p.CurrentRoom().TemplateID,
+			Connection: p.Connection(),
+			IdleMs:     p.Idle().Milliseconds(),
+		})
+	}
+	return list, nil
+}
+
+func (s *engineServer) FindRoom(ctx context.Context, q *pb.RoomQuery) (*pb.EntitySnapshot, error) {
+	room, err := s.room(q.GetTemplateId())
+	if err != nil {
+		return nil, err
+	}
+	return snapshotEntity(room), nil
+}
+
+func (s *engineServer) ResolveExits(ctx context.Context, q *pb.ExitsQuery) (*pb.ExitList, error) {
+	roomEntity, err := s.room(q.GetRoomId())
+	if err != nil {
+		return nil, err
+	}
+	room, _ := entities.GetComponent[*components.Room](roomEntity)
+
+	exits := room.Exits()
+	directions := make([]string, 0, len(exits))
+	for dir := range exits {
+		directions = append(directions, dir)
+	}
+	slices.Sort(directions)
+
+	byId := s.world.EntitiesById()
+	list := &pb.ExitList{}
+	for _, dir := range directions {
+		exit := &pb.Exit{Direction: dir, RoomId: exits[dir]}
+		if neighbor, ok := byId[exit.RoomId]; ok {
+			exit.RoomName = neighbor.Name
+		}
+		list.Exits = append(list.Exits, exit)
+	}
+	return list, nil
+}
+
+// lookup resolves an instance ID, template ID or online player name.
+func (s *engineServer) lookup(id string) (*entities.Entity, error) {
+	if e, ok := s.world.ResolveEntity(id); ok {
+		return e, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "entity '%s' does not exist", id)
+}
+
+func (s *engineServer) room(id string) (*entities.Entity, error) {
+	e, ok := s.world.EntitiesById()[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "room '%s' does not exist", id)
+	}
+	if _, ok := entities.GetComponent[*components.Room](e); !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "entity '%s' is not a room", id)
+	}
+	return e, nil
+}
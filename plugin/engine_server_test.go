@@ -0,0 +1,189 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/worldtest"
+)
+
+func TestEngineServer(t *testing.T) {
+	t.Parallel()
+
+	hall := worldtest.Room("Hall", map[string]string{"north": "Tower"})
+	tower := worldtest.Room("Tower", map[string]string{"south": "Hall", "up": "Attic"})
+	chest := worldtest.Thing("Chest")
+	container := components.NewContainer()
+	chest.Add(container)
+	orb := worldtest.Thing("Orb")
+	container.AddChild(orb)
+	room, _ := entities.GetComponent[*components.Room](hall)
+	room.AddChild(chest)
+
+	w := world.NewWorld(worldtest.Entities(hall, tower, chest, orb), "Hall")
+	alice, err := w.AddPlayer("Alice", world.NewOutbox(world.OutboxOptions{}, nil))
+	require.NoError(t, err)
+	alice.Connect(context.Background(), "telnet")
+	s := &engineServer{world: w}
+	ctx := context.Background()
+
+	names := func(list *pb.EntityList) []string {
+		var out []string
+		for _, e := range list.GetEntities() {
+			out = append(out, e.GetName())
+		}
+		return out
+	}
+
+	type tc struct {
+		name     string
+		call     func() (proto.Message, error)
+		wantCode codes.Code
+		check    func(t *testing.T, resp proto.Message)
+	}
+
+	cases := []tc{
+		{
+			name: "entity by template ID",
+			call: func() (proto.Message, error) { return s.GetEntity(ctx, &pb.EntityQuery{Id: "Chest"}) },
+			check: func(t *testing.T, resp proto.Message) {
+				snap := resp.(*pb.EntitySnapshot)
+				require.Equal(t, chest.ID, snap.GetId())
+				require.Len(t, snap.GetChildren(), 1)
+				require.Equal(t, "Orb", snap.GetChildren()[0].GetName())
+			},
+		},
+		{
+			name: "entity by instance ID",
+			call: func() (proto.Message, error) { return s.GetEntity(ctx, &pb.EntityQuery{Id: orb.ID}) },
+			check: func(t *testing.T, resp proto.Message) {
+				require.Equal(t, "Orb", resp.(*pb.EntitySnapshot).GetTemplateId())
+			},
+		},
+		{
+			name: "entity by player name",
+			call: func() (proto.Message, error) { return s.GetEntity(ctx, &pb.EntityQuery{Id: "alice"}) },
+			check: func(t *testing.T, resp proto.Message) {
+				require.Equal(t, alice.Entity.ID, resp.(*pb.EntitySnapshot).GetId())
+			},
+		},
+		{
+			name:     "unknown entity",
+			call:     func() (proto.Message, error) { return s.GetEntity(ctx, &pb.EntityQuery{Id: "Ghost"}) },
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "children of every component",
+			call: func() (proto.Message, error) { return s.ListChildren(ctx, &pb.ChildrenQuery{Id: "Hall"}) },
+			check: func(t *testing.T, resp proto.Message) {
+				require.ElementsMatch(t, []string{"Chest", "Alice"}, names(resp.(*pb.EntityList)))
+			},
+		},
+		{
+			name: "children of one component",
+			call: func() (proto.Message, error) {
+				return s.ListChildren(ctx, &pb.ChildrenQuery{Id: "Chest", Component: entities.ComponentContainerString})
+			},
+			check: func(t *testing.T, resp proto.Message) {
+				require.Equal(t, []string{"Orb"}, names(resp.(*pb.EntityList)))
+			},
+		},
+		{
+			name: "no children",
+			call: func() (proto.Message, error) { return s.ListChildren(ctx, &pb.ChildrenQuery{Id: "Orb"}) },
+			check: func(t *testing.T, resp proto.Message) {
+				require.Empty(t, resp.(*pb.EntityList).GetEntities())
+			},
+		},
+		{
+			name: "unknown component",
+			call: func() (proto.Message, error) {
+				return s.ListChildren(ctx, &pb.ChildrenQuery{Id: "Chest", Component: "Gizmo"})
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "component the entity lacks",
+			call: func() (proto.Message, error) {
+				return s.ListChildren(ctx, &pb.ChildrenQuery{Id: "Orb", Component: entities.ComponentContainerString})
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "children of an unknown entity",
+			call:     func() (proto.Message, error) { return s.ListChildren(ctx, &pb.ChildrenQuery{Id: "Ghost"}) },
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "online players",
+			call: func() (proto.Message, error) { return s.ListPlayers(ctx, &pb.Empty{}) },
+			check: func(t *testing.T, resp proto.Message) {
+				players := resp.(*pb.PlayerList).GetPlayers()
+				require.Len(t, players, 1)
+				require.Equal(t, "Alice", players[0].GetPlayer().GetName())
+				require.Equal(t, "Hall", players[0].GetRoomId())
+				require.Equal(t, "telnet", players[0].GetConnection())
+			},
+		},
+		{
+			name: "room",
+			call: func() (proto.Message, error) { return s.FindRoom(ctx, &pb.RoomQuery{TemplateId: "Tower"}) },
+			check: func(t *testing.T, resp proto.Message) {
+				require.Equal(t, tower.ID, resp.(*pb.EntitySnapshot).GetId())
+			},
+		},
+		{
+			name:     "unknown room",
+			call:     func() (proto.Message, error) { return s.FindRoom(ctx, &pb.RoomQuery{TemplateId: "Attic"}) },
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "entity that isn't a room",
+			call:     func() (proto.Message, error) { return s.FindRoom(ctx, &pb.RoomQuery{TemplateId: "Chest"}) },
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "exits, sorted, naming the rooms that exist",
+			call: func() (proto.Message, error) { return s.ResolveExits(ctx, &pb.ExitsQuery{RoomId: "Tower"}) },
+			check: func(t *testing.T, resp proto.Message) {
+				exits := resp.(*pb.ExitList).GetExits()
+				require.Len(t, exits, 2)
+				require.True(t, proto.Equal(&pb.Exit{Direction: "south", RoomId: "Hall", RoomName: "Hall"}, exits[0]))
+				require.True(t, proto.Equal(&pb.Exit{Direction: "up", RoomId: "Attic"}, exits[1]))
+			},
+		},
+		{
+			name:     "exits of an unknown room",
+			call:     func() (proto.Message, error) { return s.ResolveExits(ctx, &pb.ExitsQuery{RoomId: "Attic"}) },
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "exits of an entity that isn't a room",
+			call:     func() (proto.Message, error) { return s.ResolveExits(ctx, &pb.ExitsQuery{RoomId: "Chest"}) },
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := c.call()
+			if c.wantCode != codes.OK {
+				require.Equal(t, c.wantCode, status.Code(err), "error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			c.check(t, resp)
+		})
+	}
+}
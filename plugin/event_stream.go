@@ -0,0 +1,196 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world/entities"
+)
+
+// StreamEventType is the event type given to actions the game sends over the
+// event stream, since they aren't a reaction to a player command.
+const StreamEventType = "stream"
+
+// StreamWorld is the part of the world an EventStream needs to describe
+// updates and run the actions a game sends back.
+type StreamWorld interface {
+	GetPlayerEntity(name string) (*entities.Entity, bool)
+	ResolveEntity(id string) (*entities.Entity, bool)
+	NewEvent(eventType string, room, source, target *entities.Entity) *entities.Event
+}
+
+// EventStream pushes typed engine updates to the game plugin and executes any
+// actions the plugin sends back, whether in reply or of its own accord.
+type EventStream struct {
+	ctx     context.Context
+	client  GameClient
+	world   StreamWorld
+	updates chan *pb.EngineUpdate
+	ticks   atomic.Int64
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // closes the current stream
+}
+
+// StartEventStream opens the bidirectional stream to the game. Updates are
+// queued without blocking; a tickInterval of zero disables tick updates. The
+// stream runs until ctx is cancelled or the plugin closes it.
+func StartEventStream(ctx context.Context, client GameClient, world StreamWorld, tickInterval time.Duration) (*EventStream, error) {
+	es := &EventStream{
+		ctx:     ctx,
+		client:  client,
+		world:   world,
+		updates: make(chan *pb.EngineUpdate, 256),
+	}
+
+	if err := es.Reopen(); err != nil {
+		return nil, err
+	}
+	if tickInterval > 0 {
+		go es.tickLoop(ctx, tickInterval)
+	}
+
+	return es, nil
+}
+
+// Reopen closes the stream to the game and opens another, as when a new game
+// has taken over from the old one. Updates queued in between go to the new
+// stream.
+func (es *EventStream) Reopen() error {
+	ctx, cancel := context.WithCancel(es.ctx)
+	stream, err := es.client.EventStream(ctx)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("open event stream: %w", err)
+	}
+
+	es.mu.Lock()
+	if es.cancel != nil {
+		es.cancel()
+	}
+	es.cancel = cancel
+	es.mu.Unlock()
+
+	go es.sendLoop(ctx, stream)
+	go es.recvLoop(ctx, stream)
+	return nil
+}
+
+func (es *EventStream) PlayerJoined(player, room *entities.Entity) {
+	es.push(&pb.EngineUpdate{Kind: &pb.EngineUpdate_PlayerJoined{PlayerJoined: &pb.PlayerJoined{
+		Player: snapshotEntity(player),
+		RoomId: room.TemplateID,
+	}}})
+}
+
+func (es *EventStream) PlayerLeft(player, room *entities.Entity) {
+	es.push(&pb.EngineUpdate{Kind: &pb.EngineUpdate_PlayerLeft{PlayerLeft: &pb.PlayerLeft{
+		Player: snapshotEntity(player),
+		RoomId: room.TemplateID,
+	}}})
+}
+
+func (es *EventStream) EntityMoved(entity, from, to *entities.Entity) {
+	es.push(&pb.EngineUpdate{Kind: &pb.EngineUpdate_EntityMoved{EntityMoved: &pb.EntityMoved{
+		Entity:     snapshotEntity(entity),
+		FromRoomId: from.TemplateID,
+		ToRoomId:   to.TemplateID,
+	}}})
+}
+
+func (es *EventStream) push(u *pb.EngineUpdate) {
+	select {
+	case es.updates <- u:
+	default:
+		// drop if the plugin is slow, player goroutines must never block here
+		fmt.Println("event stream: update queue full, dropping update")
+	}
+}
+
+func (es *EventStream) sendLoop(ctx context.Context, stream pb.OrbisGame_EventStreamClient) {
+	defer stream.CloseSend()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u := <-es.updates:
+			if err := stream.Send(u); err != nil {
+				fmt.Printf("event stream: send: %v\n", err)
+				return
+			}
+		}
+	}
+}
+
+func (es *EventStream) tickLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			es.push(&pb.EngineUpdate{Kind: &pb.EngineUpdate_Tick{Tick: &pb.Tick{
+				Sequence: es.ticks.Add(1),
+				UnixMs:   now.UnixMilli(),
+			}}})
+		}
+	}
+}
+
+func (es *EventStream) recvLoop(ctx context.Context, stream pb.OrbisGame_EventStreamClient) {
+	for {
+		list, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fmt.Printf("event stream: receive: %v\n", err)
+			return
+		}
+
+		if err := es.run(list); err != nil {
+			fmt.Printf("event stream: %v\n", err)
+		}
+	}
+}
+
+// run executes an action list against an event built from its scope.
+func (es *EventStream) run(list *pb.ActionList) error {
+	scope := list.GetScope()
+
+	lookup := func(id string) (*entities.Entity, error) {
+		if id == "" {
+			return nil, nil
+		}
+		// players first, so one named like a template isn't mistaken for it
+		if e, ok := es.world.GetPlayerEntity(id); ok {
+			return e, nil
+		}
+		if e, ok := es.world.ResolveEntity(id); ok {
+			return e, nil
+		}
+		return nil, fmt.Errorf("scope references unknown entity '%s'", id)
+	}
+
+	room, err := lookup(scope.GetRoomId())
+	if err != nil {
+		return err
+	}
+	source, err := lookup(scope.GetSourceId())
+	if err != nil {
+		return err
+	}
+	target, err := lookup(scope.GetTargetId())
+	if err != nil {
+		return err
+	}
+
+	ev := es.world.NewEvent(StreamEventType, room, source, target)
+	return executeProtoActions(list.GetActions(), ev)
+}
@@ -0,0 +1,144 @@
+package plugin
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"example.com/mud/models"
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/worldtest"
+)
+
+// streamGame hands out event streams that pass on what the engine sends.
+type streamGame struct {
+	GameClient
+	updates chan *pb.EngineUpdate
+}
+
+func (g *streamGame) EventStream(ctx context.Context) (pb.OrbisGame_EventStreamClient, error) {
+	return &fakeStream{ctx: ctx, updates: g.updates}, nil
+}
+
+// fakeStream is a stream the game never sends actions on.
+type fakeStream struct {
+	grpc.ClientStream
+	ctx     context.Context
+	updates chan<- *pb.EngineUpdate
+}
+
+func (s *fakeStream) Send(u *pb.EngineUpdate) error {
+	s.updates <- u
+	return nil
+}
+
+func (s *fakeStream) Recv() (*pb.ActionList, error) {
+	<-s.ctx.Done()
+	return nil, io.EOF
+}
+
+func (s *fakeStream) CloseSend() error { return nil }
+
+func TestEventStream_Moves(t *testing.T) {
+	t.Parallel()
+
+	hall := worldtest.Room("Hall", map[string]string{"north": "Tower"})
+	tower := worldtest.Room("Tower", map[string]string{"south": "Hall"})
+	orb := worldtest.Thing("Orb")
+	room, _ := entities.GetComponent[*components.Room](hall)
+	room.AddChild(orb)
+	w := world.NewWorld(worldtest.Entities(hall, tower), "Hall")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	game := &streamGame{updates: make(chan *pb.EngineUpdate, 16)}
+	es, err := StartEventStream(ctx, game, w, 0)
+	require.NoError(t, err)
+	w.AddObserver(es)
+
+	next := func() *pb.EngineUpdate {
+		t.Helper()
+		select {
+		case u := <-game.updates:
+			return u
+		case <-time.After(5 * time.Second):
+			t.Fatal("no update reached the game")
+			return nil
+		}
+	}
+
+	p, err := w.AddPlayer("Alice", world.NewOutbox(world.OutboxOptions{}, nil))
+	require.NoError(t, err)
+	require.Equal(t, "Hall", next().GetPlayerJoined().GetRoomId())
+
+	_, err = w.MovePlayer(p, "north")
+	require.NoError(t, err)
+	moved := next().GetEntityMoved()
+	require.Equal(t, p.Entity.ID, moved.GetEntity().GetId(), "players' moves are reported")
+	require.Equal(t, "Hall", moved.GetFromRoomId())
+	require.Equal(t, "Tower", moved.GetToRoomId())
+
+	move := &actions.Move{
+		RoleObject:      entities.EventRoleSource,
+		RoleDestination: entities.EventRoleTarget,
+		ComponentType:   entities.ComponentRoom,
+	}
+	require.NoError(t, move.Execute(w.NewEvent("test", hall, orb, tower)))
+	moved = next().GetEntityMoved()
+	require.Equal(t, orb.ID, moved.GetEntity().GetId(), "so are entities moved by actions")
+	require.Equal(t, "Hall", moved.GetFromRoomId())
+	require.Equal(t, "Tower", moved.GetToRoomId())
+
+	w.RemoveObserver(es)
+	_, err = w.MovePlayer(p, "south")
+	require.NoError(t, err)
+	require.NoError(t, move.Execute(w.NewEvent("test", tower, orb, hall)))
+	select {
+	case u := <-game.updates:
+		t.Fatalf("an update reached the game after it unsubscribed: %v", u)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEventStream_Scope(t *testing.T) {
+	t.Parallel()
+
+	hall := worldtest.Room("Hall", nil)
+	guard := worldtest.Thing("Guard")
+	room, _ := entities.GetComponent[*components.Room](hall)
+	room.AddChild(guard)
+	w := world.NewWorld(worldtest.Entities(hall, guard), "Hall")
+	p, err := w.AddPlayer("Guard", world.NewOutbox(world.OutboxOptions{}, nil))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	es, err := StartEventStream(ctx, &streamGame{updates: make(chan *pb.EngineUpdate, 16)}, w, 0)
+	require.NoError(t, err)
+
+	greet := func(targetID string) error {
+		return es.run(&pb.ActionList{
+			Scope: &pb.ActionScope{RoomId: "Hall", TargetId: targetID},
+			Actions: []*pb.Action{{Kind: &pb.Action_SetField{SetField: &pb.SetFieldAction{
+				Role: "target", Field: "greeted", Value: "true", ValueType: "bool",
+			}}}},
+		})
+	}
+
+	require.NoError(t, greet("Guard"))
+	require.Equal(t, models.VBool(true), p.Entity.GetField("greeted"), "a player's name finds the player")
+	require.NotEqual(t, models.VBool(true), guard.GetField("greeted"), "not the template they share a name with")
+
+	require.NoError(t, greet(guard.ID))
+	require.Equal(t, models.VBool(true), guard.GetField("greeted"), "instance IDs find entities")
+
+	require.ErrorContains(t, greet("Ghost"), "scope references unknown entity 'Ghost'")
+}
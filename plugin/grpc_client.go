@@ -2,6 +2,11 @@ package plugin
 
 import (
 	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
 
 	pb "example.com/mud/plugin/proto"
 )
@@ -9,6 +14,11 @@ import (
 // grpcClient is the client-side wrapper used by the engine.
 type grpcClient struct {
 	client pb.OrbisGameClient
+	broker *plugin.GRPCBroker // nil when connected in debug mode
+
+	// where the game can reach the engine query service, set by ServeEngine
+	engineBrokerID uint32
+	engineAddr     string
 }
 
 func (c *grpcClient) GetManifest(ctx context.Context) (*pb.GameManifest, error) {
@@ -16,5 +26,40 @@ func (c *grpcClient) GetManifest(ctx context.Context) (*pb.GameManifest, error)
 }
 
 func (c *grpcClient) HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.ActionList, error) {
+	req.EngineBrokerId = c.engineBrokerID
+	req.EngineAddr = c.engineAddr
 	return c.client.HandleEvent(ctx, req)
 }
+
+func (c *grpcClient) EventStream(ctx context.Context) (pb.OrbisGame_EventStreamClient, error) {
+	return c.client.EventStream(ctx)
+}
+
+// ServeEngine starts answering the game's engine queries. Under go-plugin the
+// service is served over the broker; in debug mode there is no broker, so it
+// listens on a loopback port instead.
+func (c *grpcClient) ServeEngine(world EngineWorld) error {
+	srv := &engineServer{world: world}
+
+	if c.broker != nil {
+		id := c.broker.NextId()
+		go c.broker.AcceptAndServe(id, func(opts []grpc.ServerOption) *grpc.Server {
+			s := grpc.NewServer(opts...)
+			pb.RegisterOrbisEngineServer(s, srv)
+			return s
+		})
+		c.engineBrokerID = id
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("serve engine: listen: %w", err)
+	}
+	s := grpc.NewServer()
+	pb.RegisterOrbisEngineServer(s, srv)
+	go s.Serve(lis)
+	c.engineAddr = lis.Addr().String()
+
+	return nil
+}
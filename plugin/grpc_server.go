@@ -2,6 +2,12 @@ package plugin
 
 import (
 	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
 	pb "example.com/mud/plugin/proto"
 )
@@ -9,7 +15,12 @@ import (
 // grpcServer wraps a GameServer implementation and serves it over gRPC.
 type grpcServer struct {
 	pb.UnimplementedOrbisGameServer
-	impl GameServer
+	impl   GameServer
+	broker *plugin.GRPCBroker // nil when serving in debug mode
+
+	mu        sync.Mutex
+	engine    pb.OrbisEngineClient
+	engineKey string
 }
 
 func (s *grpcServer) GetManifest(ctx context.Context, req *pb.Empty) (*pb.GameManifest, error) {
@@ -17,9 +28,57 @@ func (s *grpcServer) GetManifest(ctx context.Context, req *pb.Empty) (*pb.GameMa
 }
 
 func (s *grpcServer) HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.ActionList, error) {
+	if engine, err := s.engineFor(req); err != nil {
+		fmt.Printf("engine queries unavailable: %v\n", err)
+	} else if engine != nil {
+		ctx = withEngine(ctx, engine)
+	}
 	return s.impl.HandleEvent(ctx, req)
 }
 
 func (s *grpcServer) EventStream(stream pb.OrbisGame_EventStreamServer) error {
 	return s.impl.EventStream(stream)
 }
+
+// engineFor connects to the engine query service named in the request,
+// reusing the connection for as long as the engine keeps naming the same one.
+func (s *grpcServer) engineFor(req *pb.EventRequest) (pb.OrbisEngineClient, error) {
+	key := fmt.Sprintf("%d/%s", req.EngineBrokerId, req.EngineAddr)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.engine != nil && s.engineKey == key {
+		return s.engine, nil
+	}
+
+	var conn *grpc.ClientConn
+	var err error
+	switch {
+	case req.EngineBrokerId != 0 && s.broker != nil:
+		conn, err = s.broker.Dial(req.EngineBrokerId)
+	case req.EngineAddr != "":
+		conn, err = grpc.NewClient(req.EngineAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial engine: %w", err)
+	}
+
+	s.engine = pb.NewOrbisEngineClient(conn)
+	s.engineKey = key
+	return s.engine, nil
+}
+
+type engineContextKey struct{}
+
+func withEngine(ctx context.Context, engine pb.OrbisEngineClient) context.Context {
+	return context.WithValue(ctx, engineContextKey{}, engine)
+}
+
+// EngineFromContext returns the engine query client for the event being
+// handled, if the engine offered one.
+func EngineFromContext(ctx context.Context) (pb.OrbisEngineClient, bool) {
+	engine, ok := ctx.Value(engineContextKey{}).(pb.OrbisEngineClient)
+	return engine, ok
+}
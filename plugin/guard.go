@@ -0,0 +1,214 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pb "example.com/mud/plugin/proto"
+)
+
+var errBreakerOpen = errors.New("the game has been failing, so it wasn't asked")
+
+// GuardOptions configures a Guard. Zero values get the engine's defaults.
+type GuardOptions struct {
+	Timeout            time.Duration // how long the game has to handle an event
+	BreakerFailures    int           // failed events in a row before the game isn't asked
+	BreakerCooldown    time.Duration // how long it isn't asked for
+	UnavailableMessage string        // what players see instead of the game's reply
+}
+
+func (o GuardOptions) withDefaults() GuardOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = 2 * time.Second
+	}
+	if o.BreakerFailures <= 0 {
+		o.BreakerFailures = 5
+	}
+	if o.BreakerCooldown <= 0 {
+		o.BreakerCooldown = 30 * time.Second
+	}
+	if o.UnavailableMessage == "" {
+		o.UnavailableMessage = "The world seems to hold its breath, and nothing happens."
+	}
+	return o
+}
+
+// UnavailableError is what a Guard returns instead of the game's reply to an
+// event it failed to handle, or wasn't asked to. Message is for the player.
+type UnavailableError struct {
+	Message string
+	Err     error
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("game unavailable: %v", e.Err)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Guard stands between the engine and a game so that a game that hangs or
+// keeps failing can't hold players up. Every event gets a deadline. After
+// enough failures in a row the game isn't asked for a while, then one event
+// is let through to see whether it's recovered. The guard also keeps latency
+// histograms of the game's events, by command and by template.
+type Guard struct {
+	GameClient
+	opts GuardOptions
+
+	mu         sync.Mutex
+	failures   int       // in a row
+	openUntil  time.Time // zero while the game is being asked
+	probing    bool      // an event is through to see whether the game has recovered
+	byCommand  map[string]*Histogram
+	byTemplate map[string]*Histogram
+}
+
+var _ GameClient = &Guard{}
+
+func NewGuard(client GameClient, opts GuardOptions) *Guard {
+	return &Guard{
+		GameClient: client,
+		opts:       opts.withDefaults(),
+		byCommand:  make(map[string]*Histogram),
+		byTemplate: make(map[string]*Histogram),
+	}
+}
+
+// HandleEvent asks the game to handle an event, giving up when ctx is
+// cancelled or the game takes too long. Failures come back as an
+// UnavailableError; ctx being cancelled comes back as its error.
+func (g *Guard) HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.ActionList, error) {
+	if !g.allow() {
+		return nil, &UnavailableError{Message: g.opts.UnavailableMessage, Err: errBreakerOpen}
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, g.opts.Timeout)
+	defer cancel()
+	start := time.Now()
+	resp, err := g.GameClient.HandleEvent(callCtx, req)
+	elapsed := time.Since(start)
+
+	if err != nil && ctx.Err() != nil {
+		// whoever caused the event gave up on it, which says nothing about
+		// the game
+		g.mu.Lock()
+		g.probing = false
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+
+	g.record(req, elapsed, err)
+	if err != nil {
+		return nil, &UnavailableError{Message: g.opts.UnavailableMessage, Err: err}
+	}
+	return resp, nil
+}
+
+// allow reports whether the game should be asked to handle an event.
+func (g *Guard) allow() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.openUntil.IsZero() {
+		return true
+	}
+	if g.probing || time.Now().Before(g.openUntil) {
+		return false
+	}
+	g.probing = true
+	return true
+}
+
+func (g *Guard) record(req *pb.EventRequest, elapsed time.Duration, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	observe(g.byCommand, req.GetCommand(), elapsed, err != nil)
+	observe(g.byTemplate, req.GetTargetId(), elapsed, err != nil)
+
+	g.probing = false
+	if err == nil {
+		if !g.openUntil.IsZero() {
+			fmt.Println("game plugin: handling events again")
+		}
+		g.failures = 0
+		g.openUntil = time.Time{}
+		return
+	}
+
+	g.failures++
+	if g.failures >= g.opts.BreakerFailures {
+		if g.openUntil.IsZero() {
+			fmt.Printf("game plugin: %d events failed in a row, not asking it for %s: %v\n", g.failures, g.opts.BreakerCooldown, err)
+		}
+		g.openUntil = time.Now().Add(g.opts.BreakerCooldown)
+	}
+}
+
+func observe(histograms map[string]*Histogram, key string, elapsed time.Duration, failed bool) {
+	h, ok := histograms[key]
+	if !ok {
+		h = &Histogram{}
+		histograms[key] = h
+	}
+	h.Observe(elapsed, failed)
+}
+
+// Latency returns copies of the guard's latency histograms, by command and
+// by template ID.
+func (g *Guard) Latency() (byCommand, byTemplate map[string]Histogram) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return copyHistograms(g.byCommand), copyHistograms(g.byTemplate)
+}
+
+func copyHistograms(histograms map[string]*Histogram) map[string]Histogram {
+	out := make(map[string]Histogram, len(histograms))
+	for key, h := range histograms {
+		c := *h
+		c.Counts = slices.Clone(h.Counts)
+		out[key] = c
+	}
+	return out
+}
+
+// Report describes how the game is doing, for admins.
+func (g *Guard) Report() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var b strings.Builder
+	switch {
+	case g.openUntil.IsZero():
+		fmt.Fprintf(&b, "The game is handling events, with %d failures in a row.\n", g.failures)
+	case g.probing:
+		b.WriteString("The game has been failing, and is being checked on.\n")
+	default:
+		fmt.Fprintf(&b, "The game has been failing, and won't be asked for another %s.\n", time.Until(g.openUntil).Round(time.Second))
+	}
+
+	for _, section := range []struct {
+		title      string
+		histograms map[string]*Histogram
+	}{
+		{"By command:", g.byCommand},
+		{"By template:", g.byTemplate},
+	} {
+		if len(section.histograms) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s\n", section.title)
+		for _, key := range slices.Sorted(maps.Keys(section.histograms)) {
+			fmt.Fprintf(&b, "  %s: %s\n", key, section.histograms[key])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pb "example.com/mud/plugin/proto"
+)
+
+// slowGame answers events after delay, or fails them when failing is set.
+type slowGame struct {
+	GameClient
+	delay   time.Duration
+	failing bool
+	calls   int
+}
+
+func (g *slowGame) HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.ActionList, error) {
+	g.calls++
+	select {
+	case <-time.After(g.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if g.failing {
+		return nil, errors.New("boom")
+	}
+	return &pb.ActionList{}, nil
+}
+
+func TestGuard(t *testing.T) {
+	t.Parallel()
+
+	game := &slowGame{}
+	guard := NewGuard(game, GuardOptions{
+		Timeout:            20 * time.Millisecond,
+		BreakerFailures:    2,
+		BreakerCooldown:    50 * time.Millisecond,
+		UnavailableMessage: "Nothing happens.",
+	})
+	kiss := &pb.EventRequest{Command: "kiss", TargetId: "Goblin"}
+	hit := &pb.EventRequest{Command: "hit", TargetId: "Goblin"}
+
+	steps := []struct {
+		name    string
+		setup   func()
+		ctx     func() context.Context
+		req     *pb.EventRequest
+		wantErr error // nil for a reply
+		asked   bool  // whether the game was asked
+	}{
+		{name: "answered in time", req: kiss, asked: true},
+		{name: "too slow", setup: func() { game.delay = time.Second }, req: kiss, wantErr: context.DeadlineExceeded, asked: true},
+		{
+			name: "the player left, which isn't the game's fault",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			req:     hit,
+			wantErr: context.Canceled,
+			asked:   true,
+		},
+		{name: "failed, tripping the breaker", setup: func() { game.delay, game.failing = 0, true }, req: hit, wantErr: &UnavailableError{}, asked: true},
+		{name: "not asked while tripped", req: kiss, wantErr: errBreakerOpen},
+		{name: "asked again after the cooldown, and still failing", setup: func() { time.Sleep(60 * time.Millisecond) }, req: kiss, wantErr: &UnavailableError{}, asked: true},
+		{name: "not asked after failing again", req: kiss, wantErr: errBreakerOpen},
+		{name: "recovered", setup: func() { game.failing = false; time.Sleep(60 * time.Millisecond) }, req: kiss, asked: true},
+		{name: "asked as usual", req: hit, asked: true},
+	}
+
+	for _, step := range steps {
+		if step.setup != nil {
+			step.setup()
+		}
+		ctx := context.Background()
+		if step.ctx != nil {
+			ctx = step.ctx()
+		}
+
+		calls := game.calls
+		_, err := guard.HandleEvent(ctx, step.req)
+		require.Equal(t, step.asked, game.calls > calls, step.name)
+
+		var unavailable *UnavailableError
+		switch want := step.wantErr.(type) {
+		case nil:
+			require.NoError(t, err, step.name)
+		case *UnavailableError:
+			require.ErrorAs(t, err, &unavailable, step.name)
+			require.Equal(t, "Nothing happens.", unavailable.Message, step.name)
+		default:
+			require.ErrorIs(t, err, want, step.name)
+			if want != context.Canceled {
+				require.ErrorAs(t, err, &unavailable, step.name)
+			}
+		}
+	}
+
+	byCommand, byTemplate := guard.Latency()
+	require.Equal(t, uint64(4), byCommand["kiss"].Calls, "only events the game was asked about are timed")
+	require.Equal(t, uint64(2), byCommand["kiss"].Failed)
+	require.Equal(t, uint64(2), byCommand["hit"].Calls, "nor ones the player gave up on")
+	require.Equal(t, uint64(6), byTemplate["Goblin"].Calls)
+	require.GreaterOrEqual(t, byCommand["kiss"].Max, 20*time.Millisecond)
+	require.Contains(t, guard.Report(), "The game is handling events, with 0 failures in a row.")
+}
+
+func TestHistogram_Quantile(t *testing.T) {
+	t.Parallel()
+
+	h := &Histogram{}
+	for range 90 {
+		h.Observe(3*time.Millisecond, false)
+	}
+	for range 9 {
+		h.Observe(40*time.Millisecond, false)
+	}
+	h.Observe(7*time.Second, true)
+
+	require.Equal(t, 5*time.Millisecond, h.Quantile(0.5))
+	require.Equal(t, 50*time.Millisecond, h.Quantile(0.9))
+	require.Equal(t, 50*time.Millisecond, h.Quantile(0.98))
+	require.Equal(t, 7*time.Second, h.Quantile(0.999))
+	require.Equal(t, uint64(1), h.Failed)
+}
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"fmt"
+	"time"
+)
+
+// latencyBuckets are the upper bounds of a Histogram's buckets. Anything
+// slower goes in one more bucket on the end.
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// Histogram counts how long calls took, in latencyBuckets.
+type Histogram struct {
+	Counts []uint64 // one per bucket, and one for slower than them all
+	Calls  uint64
+	Failed uint64
+	Total  time.Duration
+	Max    time.Duration
+}
+
+// Observe records a call that took d.
+func (h *Histogram) Observe(d time.Duration, failed bool) {
+	if h.Counts == nil {
+		h.Counts = make([]uint64, len(latencyBuckets)+1)
+	}
+
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Calls++
+	if failed {
+		h.Failed++
+	}
+	h.Total += d
+	h.Max = max(h.Max, d)
+}
+
+// Quantile returns the upper bound of the bucket the q'th quantile of calls
+// fell in, or the slowest call if that's past the last bucket.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.Calls == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(h.Calls))
+	var seen uint64
+	for i, bound := range latencyBuckets {
+		seen += h.Counts[i]
+		if seen > rank {
+			return bound
+		}
+	}
+	return h.Max
+}
+
+func (h *Histogram) String() string {
+	if h.Calls == 0 {
+		return "no calls"
+	}
+	mean := h.Total / time.Duration(h.Calls)
+	return fmt.Sprintf("%d calls, %d failed, mean %s, p50 <%s, p90 <%s, p99 <%s, max %s",
+		h.Calls, h.Failed, mean.Round(time.Microsecond), h.Quantile(0.5), h.Quantile(0.9), h.Quantile(0.99), h.Max.Round(time.Microsecond))
+}
@@ -1,7 +1,6 @@
 package plugin
 
 import (
-	"context"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -9,13 +8,22 @@ import (
 	"github.com/hashicorp/go-plugin"
 
 	"example.com/mud/models"
+	pb "example.com/mud/plugin/proto"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/components"
-	pb "example.com/mud/plugin/proto"
 )
 
 // Launch starts the game binary as a plugin and returns a client + cleanup func.
 func Launch(binaryPath string) (GameClient, func(), error) {
+	g, err := launch(binaryPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g.client, g.kill, nil
+}
+
+// launch starts the game binary as a plugin.
+func launch(binaryPath string) (*game, error) {
 	client := plugin.NewClient(&plugin.ClientConfig{
 		HandshakeConfig:  Handshake,
 		Plugins:          PluginMap,
@@ -26,22 +34,22 @@ func Launch(binaryPath string) (GameClient, func(), error) {
 	rpcClient, err := client.Client()
 	if err != nil {
 		client.Kill()
-		return nil, nil, fmt.Errorf("launch game plugin: %w", err)
+		return nil, fmt.Errorf("launch game plugin: %w", err)
 	}
 
 	raw, err := rpcClient.Dispense("game")
 	if err != nil {
 		client.Kill()
-		return nil, nil, fmt.Errorf("dispense game plugin: %w", err)
+		return nil, fmt.Errorf("dispense game plugin: %w", err)
 	}
 
 	gameClient, ok := raw.(GameClient)
 	if !ok {
 		client.Kill()
-		return nil, nil, fmt.Errorf("dispensed plugin does not implement GameClient")
+		return nil, fmt.Errorf("dispensed plugin does not implement GameClient")
 	}
 
-	return gameClient, client.Kill, nil
+	return &game{client: gameClient, kill: client.Kill, exited: client.Exited}, nil
 }
 
 // ManifestToWorld converts a GameManifest into the engine's entity map and command list.
@@ -50,7 +58,10 @@ func ManifestToWorld(manifest *pb.GameManifest, client GameClient) (map[string]*
 
 	// First pass: create all entity stubs (without children resolved)
 	for _, ed := range manifest.GetEntities() {
-		e := buildEntity(ed, client)
+		e, err := buildEntity(ed, client)
+		if err != nil {
+			return nil, nil, fmt.Errorf("entity %q: %w", ed.Id, err)
+		}
 		entityMap[ed.Id] = e
 	}
 
@@ -73,6 +84,11 @@ func ManifestToWorld(manifest *pb.GameManifest, client GameClient) (map[string]*
 			if !ok {
 				return nil, nil, fmt.Errorf("room %q references unknown child %q", rd.Id, childID)
 			}
+			if childEntity.Parent != nil {
+				// it starts in more than one room, so each after the first
+				// gets a copy, as the DSL does
+				childEntity = childEntity.Copy(nil)
+			}
 			if err := roomComp.AddChild(childEntity); err != nil {
 				return nil, nil, fmt.Errorf("add child %q to room %q: %w", childID, rd.Id, err)
 			}
@@ -126,13 +142,19 @@ func ManifestToWorld(manifest *pb.GameManifest, client GameClient) (map[string]*
 	return entityMap, cmds, nil
 }
 
-func buildEntity(ed *pb.EntityDef, client GameClient) *entities.Entity {
+func buildEntity(ed *pb.EntityDef, client GameClient) (*entities.Entity, error) {
 	fields := decodeEntityFields(ed.Fields)
 
 	e := entities.NewEntity(ed.Name, ed.Description, ed.Aliases, ed.Tags, fields, nil)
+	e.TemplateID = ed.Id
 
-	// Add plugin eventful for reaction handling
-	e.Add(&PluginEventful{TemplateID: ed.Id, Client: client})
+	// Add plugin eventful for reaction handling, running declarative
+	// reactions locally before falling back to the plugin
+	local, err := compileReactions(ed.Id, ed.Reactions)
+	if err != nil {
+		return nil, err
+	}
+	e.Add(&PluginEventful{TemplateID: ed.Id, Client: client, Local: local})
 
 	// Add optional components
 	if ed.HasInventory {
@@ -145,11 +167,12 @@ func buildEntity(ed *pb.EntityDef, client GameClient) *entities.Entity {
 		e.Add(c)
 	}
 
-	return e
+	return e, nil
 }
 
 func buildRoomEntity(rd *pb.RoomDef, client GameClient) *entities.Entity {
 	e := entities.NewEntity(rd.Name, rd.Description, []string{"room"}, []string{"room"}, map[string]models.Value{}, nil)
+	e.TemplateID = rd.Id
 
 	room := components.NewRoom()
 	room.MapIcon = rd.Icon
@@ -157,7 +180,7 @@ func buildRoomEntity(rd *pb.RoomDef, client GameClient) *entities.Entity {
 		room.MapIcon = "O"
 	}
 	room.MapColor = rd.Color
-	room.Exits = rd.Exits
+	room.SetExits(rd.Exits)
 
 	e.Add(room)
 
@@ -185,12 +208,3 @@ func decodeEntityFields(raw map[string]string) map[string]models.Value {
 	}
 	return out
 }
-
-// StartEventStream starts a goroutine that consumes proactive actions from the game binary.
-func StartEventStream(ctx context.Context, client GameClient, execAction func([]*pb.Action) error) {
-	// The bidirectional stream is optional for Phase 1.
-	// Game binaries that don't need proactive events simply don't use it.
-	_ = ctx
-	_ = client
-	_ = execAction
-}
@@ -0,0 +1,335 @@
+package plugin
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"example.com/mud/models"
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/entities/conditions"
+	"example.com/mud/world/entities/expressions"
+)
+
+// WorldToManifest describes a world built some other way, like from DSL
+// files, as a game manifest, so it can be loaded the way a game plugin's
+// world is. The entities are the world's templates, keyed by template ID.
+// Anything a manifest can't hold, like a reaction with an expression in it,
+// is an error; they're joined, one for each entity or command.
+func WorldToManifest(startingRoom string, entityMap map[string]*entities.Entity, commands []*models.CommandDefinition) (*pb.GameManifest, error) {
+	start, ok := entityMap[startingRoom]
+	if !ok {
+		return nil, fmt.Errorf("room '%s' does not exist in world", startingRoom)
+	}
+	if _, ok := entities.GetComponent[*components.Room](start); !ok {
+		return nil, fmt.Errorf("starting room '%s' isn't a room", startingRoom)
+	}
+
+	manifest := &pb.GameManifest{StartingRoom: startingRoom}
+	var errs []error
+
+	// the rooms each template starts in
+	inRooms := make(map[string][]string)
+
+	entityDefs := make(map[string]*pb.EntityDef)
+	for _, id := range slices.Sorted(maps.Keys(entityMap)) {
+		e := entityMap[id]
+
+		if room, ok := entities.GetComponent[*components.Room](e); ok {
+			rd, err := roomToProto(id, e, room)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("room '%s': %w", id, err))
+				continue
+			}
+			rd.ChildIds = templateIDs(room.GetChildren().GetChildren())
+			for _, childID := range rd.ChildIds {
+				inRooms[childID] = append(inRooms[childID], id)
+			}
+			manifest.Rooms = append(manifest.Rooms, rd)
+			continue
+		}
+
+		ed, err := entityToProto(id, e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entity '%s': %w", id, err))
+			continue
+		}
+		manifest.Entities = append(manifest.Entities, ed)
+		entityDefs[id] = ed
+	}
+
+	// entities that start in another's inventory or container say so, and
+	// can only start there
+	for _, ed := range manifest.Entities {
+		for _, component := range []entities.ComponentType{entities.ComponentInventory, entities.ComponentContainer} {
+			holder, ok := entityMap[ed.Id].GetComponentWithChildren(component)
+			if !ok {
+				continue
+			}
+			for _, childID := range templateIDs(holder.GetChildren().GetChildren()) {
+				cd, ok := entityDefs[childID]
+				switch {
+				case !ok:
+					errs = append(errs, fmt.Errorf("entity '%s': '%s' can't start in its %s", ed.Id, childID, component))
+				case cd.ContainerId != "":
+					errs = append(errs, fmt.Errorf("entity '%s': '%s' starts in both its %s and %s's %s, and a manifest entity can only start in one of them", ed.Id, childID, component, cd.ContainerId, cd.ContainerComponent))
+				case len(inRooms[childID]) > 0:
+					errs = append(errs, fmt.Errorf("entity '%s': '%s' starts in both its %s and %s, and a manifest entity can only start in one of them", ed.Id, childID, component, inRooms[childID][0]))
+				default:
+					cd.ContainerId, cd.ContainerComponent = ed.Id, component.String()
+				}
+			}
+		}
+	}
+
+	// a template in more than one room is copied into each, but the things
+	// in it aren't
+	for _, ed := range manifest.Entities {
+		if rooms := inRooms[ed.ContainerId]; len(rooms) > 1 {
+			errs = append(errs, fmt.Errorf("entity '%s': starts in '%s', which starts in more than one room, and only empty things can", ed.Id, ed.ContainerId))
+		}
+	}
+
+	byName := func(a, b *models.CommandDefinition) int { return cmp.Compare(a.Name, b.Name) }
+	for _, cmd := range slices.SortedFunc(slices.Values(commands), byName) {
+		cd := &pb.CommandDef{Name: cmd.Name, Aliases: cmd.Aliases}
+		for _, p := range cmd.Patterns {
+			cd.Patterns = append(cd.Patterns, &pb.CommandPattern{
+				Syntax:  models.FormatSyntax(p.Tokens),
+				NoMatch: p.NoMatchMessage,
+				Help:    p.HelpMessage,
+			})
+		}
+		manifest.Commands = append(manifest.Commands, cd)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return manifest, nil
+}
+
+// templateIDs returns the template IDs of children, sorted, so a manifest
+// comes out the same each time.
+func templateIDs(children []*entities.Entity) []string {
+	ids := make([]string, 0, len(children))
+	for _, child := range children {
+		ids = append(ids, child.TemplateID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// roomToProto describes a room. Manifest rooms have no fields or reactions
+// of their own, and always have the alias and tag "room".
+func roomToProto(id string, e *entities.Entity, room *components.Room) (*pb.RoomDef, error) {
+	if len(e.Fields) > 0 {
+		return nil, fmt.Errorf("a manifest room can't have fields")
+	}
+	if eventful, ok := entities.GetComponent[*components.Eventful](e); ok && len(eventful.Rules) > 0 {
+		return nil, fmt.Errorf("a manifest room can't have reactions")
+	}
+
+	return &pb.RoomDef{
+		Id:          id,
+		Name:        e.Name,
+		Description: e.Description,
+		Icon:        room.MapIcon,
+		Color:       room.MapColor,
+		Exits:       room.Exits(),
+	}, nil
+}
+
+func entityToProto(id string, e *entities.Entity) (*pb.EntityDef, error) {
+	ed := &pb.EntityDef{
+		Id:          id,
+		Name:        e.Name,
+		Description: e.Description,
+		Aliases:     e.Aliases,
+		Tags:        e.Tags,
+		Fields:      make(map[string]string, len(e.Fields)),
+	}
+
+	for name, v := range e.Fields {
+		val, _, err := encodeFieldValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", name, err)
+		}
+		ed.Fields[name] = val
+	}
+
+	if _, ok := entities.GetComponent[*components.Inventory](e); ok {
+		ed.HasInventory = true
+	}
+	if container, ok := entities.GetComponent[*components.Container](e); ok {
+		ed.HasContainer = true
+		ed.ContainerPrefix = container.GetChildren().GetPrefix()
+		ed.ContainerRevealed = container.GetChildren().GetRevealed()
+	}
+
+	if eventful, ok := entities.GetComponent[*components.Eventful](e); ok {
+		for _, command := range slices.Sorted(maps.Keys(eventful.Rules)) {
+			for _, rule := range eventful.Rules[command] {
+				r, err := ruleToProto(command, rule)
+				if err != nil {
+					return nil, fmt.Errorf("reaction to '%s': %w", command, err)
+				}
+				ed.Reactions = append(ed.Reactions, r)
+			}
+		}
+	}
+
+	return ed, nil
+}
+
+func ruleToProto(command string, rule *entities.Rule) (*pb.Reaction, error) {
+	r := &pb.Reaction{Command: command}
+	for _, cond := range rule.When {
+		pc, err := conditionToProto(cond)
+		if err != nil {
+			return nil, err
+		}
+		r.When = append(r.When, pc)
+	}
+	for _, a := range rule.Then {
+		pa, err := actionToProto(a)
+		if err != nil {
+			return nil, err
+		}
+		r.Then = append(r.Then, pa)
+	}
+	return r, nil
+}
+
+func conditionToProto(cond entities.Condition) (*pb.Condition, error) {
+	switch c := cond.(type) {
+	case *conditions.HasTag:
+		return &pb.Condition{Kind: &pb.Condition_HasTag{HasTag: &pb.HasTagCondition{
+			Role: c.EventRole.String(), Tag: c.Tag,
+		}}}, nil
+
+	case *conditions.FieldEquals:
+		val, valType, err := encodeFieldValue(c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", c.Field, err)
+		}
+		return &pb.Condition{Kind: &pb.Condition_FieldEquals{FieldEquals: &pb.FieldEqualsCondition{
+			Role: c.EventRole.String(), Field: c.Field, Value: val, ValueType: valType,
+		}}}, nil
+
+	case *conditions.IsPresent:
+		return &pb.Condition{Kind: &pb.Condition_RolePresent{RolePresent: &pb.RolePresentCondition{
+			Role: c.EventRole.String(),
+		}}}, nil
+
+	case *conditions.EventRolesEqual:
+		return &pb.Condition{Kind: &pb.Condition_RolesEqual{RolesEqual: &pb.RolesEqualCondition{
+			Role: c.EventRole1.String(), Other: c.EventRole2.String(),
+		}}}, nil
+
+	case *conditions.HasChild:
+		return &pb.Condition{Kind: &pb.Condition_HasChild{HasChild: &pb.HasChildCondition{
+			ChildRole: c.ChildRole.String(), ParentRole: c.ParentRole.String(), Component: c.ComponentType.String(),
+		}}}, nil
+
+	case *conditions.Not:
+		pc, err := conditionToProto(c.Cond)
+		if err != nil {
+			return nil, err
+		}
+		pc.Negate = !pc.Negate
+		return pc, nil
+	}
+
+	return nil, fmt.Errorf("a manifest can't hold %s conditions", kindName(cond))
+}
+
+func actionToProto(a entities.Action) (*pb.Action, error) {
+	switch act := a.(type) {
+	case *actions.Print:
+		return &pb.Action{Kind: &pb.Action_Print{Print: &pb.PrintAction{
+			Role: act.EventRole.String(), Message: act.Text,
+		}}}, nil
+
+	case *actions.Publish:
+		return &pb.Action{Kind: &pb.Action_Publish{Publish: &pb.PublishAction{Message: act.Text}}}, nil
+
+	case *actions.Move:
+		return &pb.Action{Kind: &pb.Action_Move{Move: &pb.MoveAction{
+			EntityRole: act.RoleObject.String(), DestRole: act.RoleDestination.String(), DestComponent: act.ComponentType.String(),
+		}}}, nil
+
+	case *actions.SetField:
+		value, ok := act.Expression.(*expressions.ExpressionConst)
+		if !ok {
+			return nil, fmt.Errorf("a manifest can only set field '%s' to a constant", act.Field)
+		}
+		val, valType, err := encodeFieldValue(value.V)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", act.Field, err)
+		}
+		return &pb.Action{Kind: &pb.Action_SetField{SetField: &pb.SetFieldAction{
+			Role: act.Role.String(), Field: act.Field, Value: val, ValueType: valType,
+		}}}, nil
+
+	case *actions.Destroy:
+		return &pb.Action{Kind: &pb.Action_Destroy{Destroy: &pb.DestroyAction{Role: act.Role.String()}}}, nil
+
+	case *actions.Copy:
+		return &pb.Action{Kind: &pb.Action_Spawn{Spawn: &pb.SpawnAction{
+			TemplateId: act.EntityId, DestRole: act.EventRole.String(), DestComponent: act.ComponentType.String(),
+		}}}, nil
+
+	case *actions.ScheduleOnce:
+		after := &pb.AfterAction{DelayMs: act.Nanoseconds.Milliseconds()}
+		for _, child := range act.Actions {
+			pa, err := actionToProto(child)
+			if err != nil {
+				return nil, err
+			}
+			after.Actions = append(after.Actions, pa)
+		}
+		return &pb.Action{Kind: &pb.Action_After{After: after}}, nil
+
+	case *actions.RevealChildren:
+		if act.Reveal {
+			return &pb.Action{Kind: &pb.Action_Reveal{Reveal: &pb.RevealAction{
+				Role: act.Role.String(), Component: act.ComponentType.String(),
+			}}}, nil
+		}
+		return &pb.Action{Kind: &pb.Action_Hide{Hide: &pb.HideAction{
+			Role: act.Role.String(), Component: act.ComponentType.String(),
+		}}}, nil
+	}
+
+	return nil, fmt.Errorf("a manifest can't hold %s actions", kindName(a))
+}
+
+// encodeFieldValue is the inverse of decodeFieldValue.
+func encodeFieldValue(v models.Value) (value, valueType string, err error) {
+	switch v.K {
+	case models.KindInt:
+		return strconv.Itoa(v.I), "int", nil
+	case models.KindString:
+		return v.S, "string", nil
+	case models.KindBool:
+		return strconv.FormatBool(v.B), "bool", nil
+	}
+	return "", "", fmt.Errorf("a manifest can only hold ints, strings and bools")
+}
+
+// kindName names the type of a condition or action, like "Or".
+func kindName(v any) string {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,188 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"example.com/mud/dsl"
+	"example.com/mud/models"
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+const manifestWorld = `entity Hall {
+    name is "Hall"
+    description is "A long hall."
+    aliases is ["hall"]
+    component Room {
+        icon is "H"
+        exits is { "north": "Tower" }
+        children is ["Lamp", "Chest"]
+    }
+}
+
+entity Tower {
+    name is "Tower"
+    description is "A tall tower."
+    aliases is ["tower"]
+    component Room {
+        exits is { "south": "Hall" }
+        children is ["Lamp"]
+    }
+}
+
+entity Lamp {
+    name is "Lamp"
+    description is "A lamp."
+    aliases is ["lamp"]
+    lit is false
+    react light, rub {
+        when {
+            not target in source.Inventory
+            instrument is target
+        } then {
+            print source "You can't light it like that."
+        }
+        when {
+            target.lit is false
+        } then {
+            set target.lit to true
+            print source "You light {target}."
+            in 5 seconds {
+                publish "The lamp flickers."
+            }
+        }
+    }
+}
+
+entity Chest {
+    name is "Chest"
+    description is "A chest."
+    aliases is ["chest"]
+    component Container {
+        prefix is "In the chest:"
+        children is ["Coin"]
+    }
+}
+
+entity Coin {
+    name is "Coin"
+    description is "A coin."
+    aliases is ["coin"]
+}
+
+command Light {
+    aliases is ["light"]
+    pattern {
+        syntax is "light {target}"
+        help is "Light something."
+    }
+}
+
+command Rub {
+    aliases is ["rub"]
+    pattern {
+        syntax is "rub {target...}"
+    }
+}
+`
+
+func TestWorldToManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "world.mud"), []byte(manifestWorld), 0o644))
+	entityMap, commands, err := dsl.LoadEntitiesFromDirectory(dir)
+	require.NoError(t, err)
+
+	manifest, err := WorldToManifest("Hall", entityMap, commands)
+	require.NoError(t, err)
+
+	// it survives the trip to a file and back
+	data, err := proto.Marshal(manifest)
+	require.NoError(t, err)
+	manifest = &pb.GameManifest{}
+	require.NoError(t, proto.Unmarshal(data, manifest))
+
+	require.Equal(t, "Hall", manifest.StartingRoom)
+	require.Len(t, manifest.Rooms, 2)
+	require.Equal(t, []string{"Chest", "Lamp"}, manifest.Rooms[0].ChildIds)
+	require.Equal(t, "rub {target...}", manifest.Commands[1].Patterns[0].Syntax)
+
+	world, worldCommands, err := ManifestToWorld(manifest, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, commands, worldCommands)
+
+	hall, _ := entities.GetComponent[*components.Room](world["Hall"])
+	tower, _ := entities.GetComponent[*components.Room](world["Tower"])
+	require.Equal(t, map[string]string{"north": "Tower"}, hall.Exits())
+	require.Equal(t, "H", hall.MapIcon)
+	hallLamp := hall.GetChildren().GetChildrenByAlias("lamp")[0].Entity
+	towerLamp := tower.GetChildren().GetChildrenByAlias("lamp")[0].Entity
+	require.NotSame(t, hallLamp, towerLamp, "a template in two rooms is copied into the second")
+	require.Equal(t, models.VBool(false), towerLamp.Fields["lit"])
+
+	chest, _ := entities.GetComponent[*components.Container](world["Chest"])
+	require.Equal(t, "In the chest:", chest.GetChildren().GetPrefix())
+	require.Len(t, chest.GetChildren().GetChildrenByAlias("coin"), 1)
+
+	eventful, ok := entities.GetComponent[*PluginEventful](world["Lamp"])
+	require.True(t, ok)
+	require.Len(t, eventful.Local.Rules["light"], 2)
+	require.Len(t, eventful.Local.Rules["rub"], 2)
+	require.Len(t, eventful.Local.Rules["light"][0].When, 2)
+	require.Len(t, eventful.Local.Rules["light"][1].Then, 3)
+}
+
+func TestWorldToManifest_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "world.mud"), []byte(`entity Hall {
+    name is "Hall"
+    description is "A long hall."
+    aliases is ["hall"]
+    component Room {}
+    react knock {
+        then {
+            print source "Nobody answers."
+        }
+    }
+}
+
+entity Lamp {
+    name is "Lamp"
+    description is "A lamp."
+    aliases is ["lamp"]
+    colors is ["red", "green"]
+}
+
+entity Bell {
+    name is "Bell"
+    description is "A bell."
+    aliases is ["bell"]
+    react ring {
+        when {
+            message contains "loud"
+        } then {
+            print source "It rings."
+        }
+    }
+}
+`), 0o644))
+	entityMap, commands, err := dsl.LoadEntitiesFromDirectory(dir)
+	require.NoError(t, err)
+
+	_, err = WorldToManifest("Hall", entityMap, commands)
+	require.EqualError(t, err, "entity 'Bell': reaction to 'ring': a manifest can't hold MessageContains conditions\n"+
+		"room 'Hall': a manifest room can't have reactions\n"+
+		"entity 'Lamp': field 'colors': a manifest can only hold ints, strings and bools")
+
+	_, err = WorldToManifest("Lamp", entityMap, commands)
+	require.EqualError(t, err, "starting room 'Lamp' isn't a room")
+}
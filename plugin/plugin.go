@@ -29,12 +29,12 @@ type GameGRPCPlugin struct {
 }
 
 func (p *GameGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
-	pb.RegisterOrbisGameServer(s, &grpcServer{impl: p.Impl})
+	pb.RegisterOrbisGameServer(s, &grpcServer{impl: p.Impl, broker: broker})
 	return nil
 }
 
 func (p *GameGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
-	return &grpcClient{client: pb.NewOrbisGameClient(c)}, nil
+	return &grpcClient{client: pb.NewOrbisGameClient(c), broker: broker}, nil
 }
 
 // Satisfy net/rpc plugin interface (unused but required by go-plugin)
@@ -54,4 +54,8 @@ type GameServer interface {
 type GameClient interface {
 	GetManifest(ctx context.Context) (*pb.GameManifest, error)
 	HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.ActionList, error)
+	EventStream(ctx context.Context) (pb.OrbisGame_EventStreamClient, error)
+
+	// ServeEngine lets the game query the given world while handling events.
+	ServeEngine(world EngineWorld) error
 }
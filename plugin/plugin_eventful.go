@@ -1,7 +1,7 @@
 package plugin
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -10,13 +10,17 @@ import (
 	pb "example.com/mud/plugin/proto"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/components"
 	"example.com/mud/world/entities/expressions"
 )
 
-// PluginEventful delegates all event handling to the game plugin via gRPC.
+// PluginEventful handles events for a plugin entity. Declarative reactions
+// from the manifest run locally; anything they don't match is delegated to the
+// game plugin via gRPC.
 type PluginEventful struct {
 	TemplateID string
 	Client     GameClient
+	Local      *components.Eventful // nil when the entity has no reactions
 }
 
 var _ entities.Component = &PluginEventful{}
@@ -30,14 +34,37 @@ func (p *PluginEventful) Copy() entities.Component {
 	return &PluginEventful{
 		TemplateID: p.TemplateID,
 		Client:     p.Client,
+		Local:      p.Local,
 	}
 }
 
 func (p *PluginEventful) OnEvent(ev *entities.Event) (bool, error) {
+	if p.Local != nil {
+		match, err := p.Local.OnEvent(ev)
+		if err != nil {
+			return false, fmt.Errorf("local reaction: %w", err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+
 	req := buildEventRequest(p.TemplateID, ev)
 
-	resp, err := p.Client.HandleEvent(context.Background(), req)
-	if err != nil {
+	resp, err := p.Client.HandleEvent(ev.Context(), req)
+	var unavailable *UnavailableError
+	switch {
+	case errors.As(err, &unavailable):
+		fmt.Printf("plugin handle event '%s' on '%s': %v\n", ev.Type, p.TemplateID, err)
+		if ev.Source == nil {
+			return true, nil
+		}
+		notice := &actions.Print{Text: unavailable.Message, EventRole: entities.EventRoleSource}
+		return true, notice.Execute(ev)
+	case err != nil && ev.Context().Err() != nil:
+		// whoever caused the event has gone, so there's no one to tell
+		return true, nil
+	case err != nil:
 		return false, fmt.Errorf("plugin handle event: %w", err)
 	}
 
@@ -61,24 +88,25 @@ func buildEventRequest(targetID string, ev *entities.Event) *pb.EventRequest {
 	}
 
 	if ev.Source != nil {
-		req.Source = snapshotEntity(ev.Source, ev)
+		req.Source = snapshotEntity(ev.Source)
 	}
 	if ev.Target != nil {
-		req.Target = snapshotEntity(ev.Target, ev)
+		req.Target = snapshotEntity(ev.Target)
 	}
 	if ev.Instrument != nil {
-		req.Instrument = snapshotEntity(ev.Instrument, ev)
+		req.Instrument = snapshotEntity(ev.Instrument)
 	}
 	if ev.Room != nil {
-		req.Room = snapshotEntity(ev.Room, ev)
+		req.Room = snapshotEntity(ev.Room)
 	}
 
 	return req
 }
 
-func snapshotEntity(e *entities.Entity, ev *entities.Event) *pb.EntitySnapshot {
+func snapshotEntity(e *entities.Entity) *pb.EntitySnapshot {
 	snap := &pb.EntitySnapshot{
-		TemplateId:  templateIDOf(e, ev),
+		Id:          e.ID,
+		TemplateId:  e.TemplateID,
 		Name:        e.Name,
 		Description: e.Description,
 		Aliases:     e.Aliases,
@@ -90,7 +118,8 @@ func snapshotEntity(e *entities.Entity, ev *entities.Event) *pb.EntitySnapshot {
 		compName := cwc.(entities.Component).Id().String()
 		for _, child := range cwc.GetChildren().GetChildren() {
 			snap.Children = append(snap.Children, &pb.ChildRef{
-				TemplateId: templateIDOf(child, ev),
+				Id:         child.ID,
+				TemplateId: child.TemplateID,
 				Name:       child.Name,
 				Tags:       child.Tags,
 				Component:  compName,
@@ -101,17 +130,6 @@ func snapshotEntity(e *entities.Entity, ev *entities.Event) *pb.EntitySnapshot {
 	return snap
 }
 
-// templateIDOf finds the template ID for an entity by reverse lookup in EntitiesById.
-// For player entities not in the map, falls back to the entity name.
-func templateIDOf(e *entities.Entity, ev *entities.Event) string {
-	for id, ent := range ev.EntitiesById {
-		if ent == e {
-			return id
-		}
-	}
-	return e.Name
-}
-
 func encodeFields(fields map[string]models.Value) map[string]string {
 	out := make(map[string]string, len(fields))
 	for k, v := range fields {
@@ -130,7 +148,7 @@ func encodeFields(fields map[string]models.Value) map[string]string {
 // executeProtoActions converts proto actions to engine actions and executes them.
 func executeProtoActions(protoActions []*pb.Action, ev *entities.Event) error {
 	for _, pa := range protoActions {
-		a, err := protoActionToEngineAction(pa, ev)
+		a, err := protoActionToEngineAction(pa)
 		if err != nil {
 			return err
 		}
@@ -143,7 +161,7 @@ func executeProtoActions(protoActions []*pb.Action, ev *entities.Event) error {
 	return nil
 }
 
-func protoActionToEngineAction(pa *pb.Action, ev *entities.Event) (entities.Action, error) {
+func protoActionToEngineAction(pa *pb.Action) (entities.Action, error) {
 	switch kind := pa.Kind.(type) {
 	case *pb.Action_Print:
 		role, err := parseRole(kind.Print.Role)
@@ -216,7 +234,7 @@ func protoActionToEngineAction(pa *pb.Action, ev *entities.Event) (entities.Acti
 	case *pb.Action_After:
 		childActions := make([]entities.Action, 0, len(kind.After.Actions))
 		for _, ca := range kind.After.Actions {
-			a, err := protoActionToEngineAction(ca, ev)
+			a, err := protoActionToEngineAction(ca)
 			if err != nil {
 				return nil, err
 			}
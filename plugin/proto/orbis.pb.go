@@ -231,6 +231,7 @@ type EntityDef struct {
 	HasContainer       bool                   `protobuf:"varint,10,opt,name=has_container,json=hasContainer,proto3" json:"has_container,omitempty"`                                         // entity has a Container component
 	ContainerPrefix    string                 `protobuf:"bytes,11,opt,name=container_prefix,json=containerPrefix,proto3" json:"container_prefix,omitempty"`                                 // prefix string for container
 	ContainerRevealed  bool                   `protobuf:"varint,12,opt,name=container_revealed,json=containerRevealed,proto3" json:"container_revealed,omitempty"`                          // initial revealed state
+	Reactions          []*Reaction            `protobuf:"bytes,13,rep,name=reactions,proto3" json:"reactions,omitempty"`                                                                    // run by the engine without calling HandleEvent
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
@@ -349,29 +350,39 @@ func (x *EntityDef) GetContainerRevealed() bool {
 	return false
 }
 
-type CommandDef struct {
+func (x *EntityDef) GetReactions() []*Reaction {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+// Reaction is a declarative response to a command. The engine tries an
+// entity's reactions for a command in order and runs the first whose
+// conditions all hold; if none do, the event is sent to HandleEvent.
+type Reaction struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Aliases       []string               `protobuf:"bytes,2,rep,name=aliases,proto3" json:"aliases,omitempty"`
-	Patterns      []*CommandPattern      `protobuf:"bytes,3,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	Command       string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	When          []*Condition           `protobuf:"bytes,2,rep,name=when,proto3" json:"when,omitempty"`
+	Then          []*Action              `protobuf:"bytes,3,rep,name=then,proto3" json:"then,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CommandDef) Reset() {
-	*x = CommandDef{}
+func (x *Reaction) Reset() {
+	*x = Reaction{}
 	mi := &file_plugin_proto_orbis_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CommandDef) String() string {
+func (x *Reaction) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommandDef) ProtoMessage() {}
+func (*Reaction) ProtoMessage() {}
 
-func (x *CommandDef) ProtoReflect() protoreflect.Message {
+func (x *Reaction) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_orbis_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -383,55 +394,61 @@ func (x *CommandDef) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommandDef.ProtoReflect.Descriptor instead.
-func (*CommandDef) Descriptor() ([]byte, []int) {
+// Deprecated: Use Reaction.ProtoReflect.Descriptor instead.
+func (*Reaction) Descriptor() ([]byte, []int) {
 	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CommandDef) GetName() string {
+func (x *Reaction) GetCommand() string {
 	if x != nil {
-		return x.Name
+		return x.Command
 	}
 	return ""
 }
 
-func (x *CommandDef) GetAliases() []string {
+func (x *Reaction) GetWhen() []*Condition {
 	if x != nil {
-		return x.Aliases
+		return x.When
 	}
 	return nil
 }
 
-func (x *CommandDef) GetPatterns() []*CommandPattern {
+func (x *Reaction) GetThen() []*Action {
 	if x != nil {
-		return x.Patterns
+		return x.Then
 	}
 	return nil
 }
 
-type CommandPattern struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Syntax        string                 `protobuf:"bytes,1,opt,name=syntax,proto3" json:"syntax,omitempty"`
-	NoMatch       string                 `protobuf:"bytes,2,opt,name=no_match,json=noMatch,proto3" json:"no_match,omitempty"`
-	Help          string                 `protobuf:"bytes,3,opt,name=help,proto3" json:"help,omitempty"`
+type Condition struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Negate bool                   `protobuf:"varint,1,opt,name=negate,proto3" json:"negate,omitempty"` // invert the result of the condition
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*Condition_HasTag
+	//	*Condition_FieldEquals
+	//	*Condition_RolePresent
+	//	*Condition_RolesEqual
+	//	*Condition_HasChild
+	Kind          isCondition_Kind `protobuf_oneof:"kind"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CommandPattern) Reset() {
-	*x = CommandPattern{}
+func (x *Condition) Reset() {
+	*x = Condition{}
 	mi := &file_plugin_proto_orbis_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CommandPattern) String() string {
+func (x *Condition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommandPattern) ProtoMessage() {}
+func (*Condition) ProtoMessage() {}
 
-func (x *CommandPattern) ProtoReflect() protoreflect.Message {
+func (x *Condition) ProtoReflect() protoreflect.Message {
 	mi := &file_plugin_proto_orbis_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -443,60 +460,1261 @@ func (x *CommandPattern) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommandPattern.ProtoReflect.Descriptor instead.
-func (*CommandPattern) Descriptor() ([]byte, []int) {
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
 	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *CommandPattern) GetSyntax() string {
+func (x *Condition) GetNegate() bool {
 	if x != nil {
-		return x.Syntax
+		return x.Negate
+	}
+	return false
+}
+
+func (x *Condition) GetKind() isCondition_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *Condition) GetHasTag() *HasTagCondition {
+	if x != nil {
+		if x, ok := x.Kind.(*Condition_HasTag); ok {
+			return x.HasTag
+		}
+	}
+	return nil
+}
+
+func (x *Condition) GetFieldEquals() *FieldEqualsCondition {
+	if x != nil {
+		if x, ok := x.Kind.(*Condition_FieldEquals); ok {
+			return x.FieldEquals
+		}
+	}
+	return nil
+}
+
+func (x *Condition) GetRolePresent() *RolePresentCondition {
+	if x != nil {
+		if x, ok := x.Kind.(*Condition_RolePresent); ok {
+			return x.RolePresent
+		}
+	}
+	return nil
+}
+
+func (x *Condition) GetRolesEqual() *RolesEqualCondition {
+	if x != nil {
+		if x, ok := x.Kind.(*Condition_RolesEqual); ok {
+			return x.RolesEqual
+		}
+	}
+	return nil
+}
+
+func (x *Condition) GetHasChild() *HasChildCondition {
+	if x != nil {
+		if x, ok := x.Kind.(*Condition_HasChild); ok {
+			return x.HasChild
+		}
+	}
+	return nil
+}
+
+type isCondition_Kind interface {
+	isCondition_Kind()
+}
+
+type Condition_HasTag struct {
+	HasTag *HasTagCondition `protobuf:"bytes,2,opt,name=has_tag,json=hasTag,proto3,oneof"`
+}
+
+type Condition_FieldEquals struct {
+	FieldEquals *FieldEqualsCondition `protobuf:"bytes,3,opt,name=field_equals,json=fieldEquals,proto3,oneof"`
+}
+
+type Condition_RolePresent struct {
+	RolePresent *RolePresentCondition `protobuf:"bytes,4,opt,name=role_present,json=rolePresent,proto3,oneof"`
+}
+
+type Condition_RolesEqual struct {
+	RolesEqual *RolesEqualCondition `protobuf:"bytes,5,opt,name=roles_equal,json=rolesEqual,proto3,oneof"`
+}
+
+type Condition_HasChild struct {
+	HasChild *HasChildCondition `protobuf:"bytes,6,opt,name=has_child,json=hasChild,proto3,oneof"`
+}
+
+func (*Condition_HasTag) isCondition_Kind() {}
+
+func (*Condition_FieldEquals) isCondition_Kind() {}
+
+func (*Condition_RolePresent) isCondition_Kind() {}
+
+func (*Condition_RolesEqual) isCondition_Kind() {}
+
+func (*Condition_HasChild) isCondition_Kind() {}
+
+type HasTagCondition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasTagCondition) Reset() {
+	*x = HasTagCondition{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasTagCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasTagCondition) ProtoMessage() {}
+
+func (x *HasTagCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasTagCondition.ProtoReflect.Descriptor instead.
+func (*HasTagCondition) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HasTagCondition) GetRole() string {
+	if x != nil {
+		return x.Role
 	}
 	return ""
 }
 
-func (x *CommandPattern) GetNoMatch() string {
+func (x *HasTagCondition) GetTag() string {
 	if x != nil {
-		return x.NoMatch
+		return x.Tag
 	}
 	return ""
 }
 
-func (x *CommandPattern) GetHelp() string {
+type FieldEqualsCondition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`                          // encoded like SetFieldAction.value
+	ValueType     string                 `protobuf:"bytes,4,opt,name=value_type,json=valueType,proto3" json:"value_type,omitempty"` // "int", "string", "bool"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldEqualsCondition) Reset() {
+	*x = FieldEqualsCondition{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldEqualsCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldEqualsCondition) ProtoMessage() {}
+
+func (x *FieldEqualsCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[7]
 	if x != nil {
-		return x.Help
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldEqualsCondition.ProtoReflect.Descriptor instead.
+func (*FieldEqualsCondition) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FieldEqualsCondition) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *FieldEqualsCondition) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldEqualsCondition) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *FieldEqualsCondition) GetValueType() string {
+	if x != nil {
+		return x.ValueType
+	}
+	return ""
+}
+
+type RolePresentCondition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RolePresentCondition) Reset() {
+	*x = RolePresentCondition{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RolePresentCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RolePresentCondition) ProtoMessage() {}
+
+func (x *RolePresentCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RolePresentCondition.ProtoReflect.Descriptor instead.
+func (*RolePresentCondition) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RolePresentCondition) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+// RolesEqualCondition holds when both roles are the same entity, e.g. when
+// something is used on itself.
+type RolesEqualCondition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Other         string                 `protobuf:"bytes,2,opt,name=other,proto3" json:"other,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RolesEqualCondition) Reset() {
+	*x = RolesEqualCondition{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RolesEqualCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RolesEqualCondition) ProtoMessage() {}
+
+func (x *RolesEqualCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RolesEqualCondition.ProtoReflect.Descriptor instead.
+func (*RolesEqualCondition) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RolesEqualCondition) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *RolesEqualCondition) GetOther() string {
+	if x != nil {
+		return x.Other
+	}
+	return ""
+}
+
+type HasChildCondition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChildRole     string                 `protobuf:"bytes,1,opt,name=child_role,json=childRole,proto3" json:"child_role,omitempty"`
+	ParentRole    string                 `protobuf:"bytes,2,opt,name=parent_role,json=parentRole,proto3" json:"parent_role,omitempty"`
+	Component     string                 `protobuf:"bytes,3,opt,name=component,proto3" json:"component,omitempty"` // "Room", "Inventory", "Container"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasChildCondition) Reset() {
+	*x = HasChildCondition{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasChildCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasChildCondition) ProtoMessage() {}
+
+func (x *HasChildCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasChildCondition.ProtoReflect.Descriptor instead.
+func (*HasChildCondition) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HasChildCondition) GetChildRole() string {
+	if x != nil {
+		return x.ChildRole
+	}
+	return ""
+}
+
+func (x *HasChildCondition) GetParentRole() string {
+	if x != nil {
+		return x.ParentRole
+	}
+	return ""
+}
+
+func (x *HasChildCondition) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+type CommandDef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Aliases       []string               `protobuf:"bytes,2,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Patterns      []*CommandPattern      `protobuf:"bytes,3,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandDef) Reset() {
+	*x = CommandDef{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandDef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandDef) ProtoMessage() {}
+
+func (x *CommandDef) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandDef.ProtoReflect.Descriptor instead.
+func (*CommandDef) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CommandDef) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CommandDef) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *CommandDef) GetPatterns() []*CommandPattern {
+	if x != nil {
+		return x.Patterns
+	}
+	return nil
+}
+
+type CommandPattern struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Syntax        string                 `protobuf:"bytes,1,opt,name=syntax,proto3" json:"syntax,omitempty"`
+	NoMatch       string                 `protobuf:"bytes,2,opt,name=no_match,json=noMatch,proto3" json:"no_match,omitempty"`
+	Help          string                 `protobuf:"bytes,3,opt,name=help,proto3" json:"help,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandPattern) Reset() {
+	*x = CommandPattern{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandPattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandPattern) ProtoMessage() {}
+
+func (x *CommandPattern) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandPattern.ProtoReflect.Descriptor instead.
+func (*CommandPattern) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CommandPattern) GetSyntax() string {
+	if x != nil {
+		return x.Syntax
+	}
+	return ""
+}
+
+func (x *CommandPattern) GetNoMatch() string {
+	if x != nil {
+		return x.NoMatch
+	}
+	return ""
+}
+
+func (x *CommandPattern) GetHelp() string {
+	if x != nil {
+		return x.Help
+	}
+	return ""
+}
+
+type EventRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Command        string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	TargetId       string                 `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // template ID of the reacting entity
+	Source         *EntitySnapshot        `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Target         *EntitySnapshot        `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Instrument     *EntitySnapshot        `protobuf:"bytes,5,opt,name=instrument,proto3" json:"instrument,omitempty"`
+	Room           *EntitySnapshot        `protobuf:"bytes,6,opt,name=room,proto3" json:"room,omitempty"`
+	Message        string                 `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
+	EngineBrokerId uint32                 `protobuf:"varint,8,opt,name=engine_broker_id,json=engineBrokerId,proto3" json:"engine_broker_id,omitempty"` // broker stream serving OrbisEngine, 0 if unavailable
+	EngineAddr     string                 `protobuf:"bytes,9,opt,name=engine_addr,json=engineAddr,proto3" json:"engine_addr,omitempty"`                // direct OrbisEngine address, used in debug mode
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EventRequest) Reset() {
+	*x = EventRequest{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventRequest) ProtoMessage() {}
+
+func (x *EventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventRequest.ProtoReflect.Descriptor instead.
+func (*EventRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EventRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *EventRequest) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *EventRequest) GetSource() *EntitySnapshot {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *EventRequest) GetTarget() *EntitySnapshot {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *EventRequest) GetInstrument() *EntitySnapshot {
+	if x != nil {
+		return x.Instrument
+	}
+	return nil
+}
+
+func (x *EventRequest) GetRoom() *EntitySnapshot {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *EventRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *EventRequest) GetEngineBrokerId() uint32 {
+	if x != nil {
+		return x.EngineBrokerId
+	}
+	return 0
+}
+
+func (x *EventRequest) GetEngineAddr() string {
+	if x != nil {
+		return x.EngineAddr
+	}
+	return ""
+}
+
+type EntitySnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Aliases       []string               `protobuf:"bytes,4,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // same encoding as EntityDef.fields
+	Children      []*ChildRef            `protobuf:"bytes,7,rep,name=children,proto3" json:"children,omitempty"`                                                                       // immediate children across all components
+	Id            string                 `protobuf:"bytes,8,opt,name=id,proto3" json:"id,omitempty"`                                                                                   // unique to this instance, unlike template_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntitySnapshot) Reset() {
+	*x = EntitySnapshot{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntitySnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntitySnapshot) ProtoMessage() {}
+
+func (x *EntitySnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntitySnapshot.ProtoReflect.Descriptor instead.
+func (*EntitySnapshot) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EntitySnapshot) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *EntitySnapshot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EntitySnapshot) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *EntitySnapshot) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *EntitySnapshot) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *EntitySnapshot) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *EntitySnapshot) GetChildren() []*ChildRef {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *EntitySnapshot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ChildRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Tags          []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	Component     string                 `protobuf:"bytes,4,opt,name=component,proto3" json:"component,omitempty"` // "Room", "Inventory", "Container"
+	Id            string                 `protobuf:"bytes,5,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChildRef) Reset() {
+	*x = ChildRef{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChildRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChildRef) ProtoMessage() {}
+
+func (x *ChildRef) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChildRef.ProtoReflect.Descriptor instead.
+func (*ChildRef) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ChildRef) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+func (x *ChildRef) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChildRef) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ChildRef) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *ChildRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Entity IDs in queries and scopes may be instance IDs, template IDs, or the
+// names of online players, tried in that order.
+type EntityQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityQuery) Reset() {
+	*x = EntityQuery{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityQuery) ProtoMessage() {}
+
+func (x *EntityQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityQuery.ProtoReflect.Descriptor instead.
+func (*EntityQuery) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EntityQuery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ChildrenQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Component     string                 `protobuf:"bytes,2,opt,name=component,proto3" json:"component,omitempty"` // "Room", "Inventory", "Container", or empty for all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChildrenQuery) Reset() {
+	*x = ChildrenQuery{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChildrenQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChildrenQuery) ProtoMessage() {}
+
+func (x *ChildrenQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChildrenQuery.ProtoReflect.Descriptor instead.
+func (*ChildrenQuery) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ChildrenQuery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChildrenQuery) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+type EntityList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entities      []*EntitySnapshot      `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityList) Reset() {
+	*x = EntityList{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityList) ProtoMessage() {}
+
+func (x *EntityList) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityList.ProtoReflect.Descriptor instead.
+func (*EntityList) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EntityList) GetEntities() []*EntitySnapshot {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+type PlayerList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Players       []*PlayerInfo          `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerList) Reset() {
+	*x = PlayerList{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerList) ProtoMessage() {}
+
+func (x *PlayerList) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerList.ProtoReflect.Descriptor instead.
+func (*PlayerList) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PlayerList) GetPlayers() []*PlayerInfo {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+type PlayerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Player        *EntitySnapshot        `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Connection    string                 `protobuf:"bytes,3,opt,name=connection,proto3" json:"connection,omitempty"`        // protocol, empty while link-dead
+	IdleMs        int64                  `protobuf:"varint,4,opt,name=idle_ms,json=idleMs,proto3" json:"idle_ms,omitempty"` // since the player last sent anything
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerInfo) Reset() {
+	*x = PlayerInfo{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerInfo) ProtoMessage() {}
+
+func (x *PlayerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerInfo.ProtoReflect.Descriptor instead.
+func (*PlayerInfo) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PlayerInfo) GetPlayer() *EntitySnapshot {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+func (x *PlayerInfo) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetConnection() string {
+	if x != nil {
+		return x.Connection
+	}
+	return ""
+}
+
+func (x *PlayerInfo) GetIdleMs() int64 {
+	if x != nil {
+		return x.IdleMs
+	}
+	return 0
+}
+
+type RoomQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomQuery) Reset() {
+	*x = RoomQuery{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomQuery) ProtoMessage() {}
+
+func (x *RoomQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomQuery.ProtoReflect.Descriptor instead.
+func (*RoomQuery) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RoomQuery) GetTemplateId() string {
+	if x != nil {
+		return x.TemplateId
+	}
+	return ""
+}
+
+type ExitsQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExitsQuery) Reset() {
+	*x = ExitsQuery{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExitsQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExitsQuery) ProtoMessage() {}
+
+func (x *ExitsQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExitsQuery.ProtoReflect.Descriptor instead.
+func (*ExitsQuery) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExitsQuery) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type ExitList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exits         []*Exit                `protobuf:"bytes,1,rep,name=exits,proto3" json:"exits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExitList) Reset() {
+	*x = ExitList{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExitList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExitList) ProtoMessage() {}
+
+func (x *ExitList) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExitList.ProtoReflect.Descriptor instead.
+func (*ExitList) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExitList) GetExits() []*Exit {
+	if x != nil {
+		return x.Exits
+	}
+	return nil
+}
+
+type Exit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Direction     string                 `protobuf:"bytes,1,opt,name=direction,proto3" json:"direction,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	RoomName      string                 `protobuf:"bytes,3,opt,name=room_name,json=roomName,proto3" json:"room_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Exit) Reset() {
+	*x = Exit{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Exit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Exit) ProtoMessage() {}
+
+func (x *Exit) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Exit.ProtoReflect.Descriptor instead.
+func (*Exit) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Exit) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *Exit) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *Exit) GetRoomName() string {
+	if x != nil {
+		return x.RoomName
 	}
 	return ""
 }
 
-type EventRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Command       string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
-	TargetId      string                 `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"` // template ID of the reacting entity
-	Source        *EntitySnapshot        `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
-	Target        *EntitySnapshot        `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
-	Instrument    *EntitySnapshot        `protobuf:"bytes,5,opt,name=instrument,proto3" json:"instrument,omitempty"`
-	Room          *EntitySnapshot        `protobuf:"bytes,6,opt,name=room,proto3" json:"room,omitempty"`
-	Message       string                 `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
+// EngineUpdate is pushed from the engine to the game over EventStream.
+type EngineUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*EngineUpdate_PlayerJoined
+	//	*EngineUpdate_PlayerLeft
+	//	*EngineUpdate_EntityMoved
+	//	*EngineUpdate_Tick
+	Kind          isEngineUpdate_Kind `protobuf_oneof:"kind"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EventRequest) Reset() {
-	*x = EventRequest{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[6]
+func (x *EngineUpdate) Reset() {
+	*x = EngineUpdate{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EventRequest) String() string {
+func (x *EngineUpdate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EventRequest) ProtoMessage() {}
+func (*EngineUpdate) ProtoMessage() {}
 
-func (x *EventRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[6]
+func (x *EngineUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -507,88 +1725,105 @@ func (x *EventRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EventRequest.ProtoReflect.Descriptor instead.
-func (*EventRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use EngineUpdate.ProtoReflect.Descriptor instead.
+func (*EngineUpdate) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *EventRequest) GetCommand() string {
+func (x *EngineUpdate) GetKind() isEngineUpdate_Kind {
 	if x != nil {
-		return x.Command
+		return x.Kind
 	}
-	return ""
+	return nil
 }
 
-func (x *EventRequest) GetTargetId() string {
+func (x *EngineUpdate) GetPlayerJoined() *PlayerJoined {
 	if x != nil {
-		return x.TargetId
+		if x, ok := x.Kind.(*EngineUpdate_PlayerJoined); ok {
+			return x.PlayerJoined
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *EventRequest) GetSource() *EntitySnapshot {
+func (x *EngineUpdate) GetPlayerLeft() *PlayerLeft {
 	if x != nil {
-		return x.Source
+		if x, ok := x.Kind.(*EngineUpdate_PlayerLeft); ok {
+			return x.PlayerLeft
+		}
 	}
 	return nil
 }
 
-func (x *EventRequest) GetTarget() *EntitySnapshot {
+func (x *EngineUpdate) GetEntityMoved() *EntityMoved {
 	if x != nil {
-		return x.Target
+		if x, ok := x.Kind.(*EngineUpdate_EntityMoved); ok {
+			return x.EntityMoved
+		}
 	}
 	return nil
 }
 
-func (x *EventRequest) GetInstrument() *EntitySnapshot {
+func (x *EngineUpdate) GetTick() *Tick {
 	if x != nil {
-		return x.Instrument
+		if x, ok := x.Kind.(*EngineUpdate_Tick); ok {
+			return x.Tick
+		}
 	}
 	return nil
 }
 
-func (x *EventRequest) GetRoom() *EntitySnapshot {
-	if x != nil {
-		return x.Room
-	}
-	return nil
+type isEngineUpdate_Kind interface {
+	isEngineUpdate_Kind()
 }
 
-func (x *EventRequest) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
+type EngineUpdate_PlayerJoined struct {
+	PlayerJoined *PlayerJoined `protobuf:"bytes,2,opt,name=player_joined,json=playerJoined,proto3,oneof"`
 }
 
-type EntitySnapshot struct {
+type EngineUpdate_PlayerLeft struct {
+	PlayerLeft *PlayerLeft `protobuf:"bytes,3,opt,name=player_left,json=playerLeft,proto3,oneof"`
+}
+
+type EngineUpdate_EntityMoved struct {
+	EntityMoved *EntityMoved `protobuf:"bytes,4,opt,name=entity_moved,json=entityMoved,proto3,oneof"`
+}
+
+type EngineUpdate_Tick struct {
+	Tick *Tick `protobuf:"bytes,5,opt,name=tick,proto3,oneof"`
+}
+
+func (*EngineUpdate_PlayerJoined) isEngineUpdate_Kind() {}
+
+func (*EngineUpdate_PlayerLeft) isEngineUpdate_Kind() {}
+
+func (*EngineUpdate_EntityMoved) isEngineUpdate_Kind() {}
+
+func (*EngineUpdate_Tick) isEngineUpdate_Kind() {}
+
+type PlayerJoined struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Aliases       []string               `protobuf:"bytes,4,rep,name=aliases,proto3" json:"aliases,omitempty"`
-	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
-	Fields        map[string]string      `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // same encoding as EntityDef.fields
-	Children      []*ChildRef            `protobuf:"bytes,7,rep,name=children,proto3" json:"children,omitempty"`                                                                       // immediate children across all components
+	Player        *EntitySnapshot        `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EntitySnapshot) Reset() {
-	*x = EntitySnapshot{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[7]
+func (x *PlayerJoined) Reset() {
+	*x = PlayerJoined{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EntitySnapshot) String() string {
+func (x *PlayerJoined) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EntitySnapshot) ProtoMessage() {}
+func (*PlayerJoined) ProtoMessage() {}
 
-func (x *EntitySnapshot) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[7]
+func (x *PlayerJoined) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,85 +1834,101 @@ func (x *EntitySnapshot) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EntitySnapshot.ProtoReflect.Descriptor instead.
-func (*EntitySnapshot) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use PlayerJoined.ProtoReflect.Descriptor instead.
+func (*PlayerJoined) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *EntitySnapshot) GetTemplateId() string {
+func (x *PlayerJoined) GetPlayer() *EntitySnapshot {
 	if x != nil {
-		return x.TemplateId
+		return x.Player
 	}
-	return ""
+	return nil
 }
 
-func (x *EntitySnapshot) GetName() string {
+func (x *PlayerJoined) GetRoomId() string {
 	if x != nil {
-		return x.Name
+		return x.RoomId
 	}
 	return ""
 }
 
-func (x *EntitySnapshot) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+type PlayerLeft struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Player        *EntitySnapshot        `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EntitySnapshot) GetAliases() []string {
-	if x != nil {
-		return x.Aliases
-	}
-	return nil
+func (x *PlayerLeft) Reset() {
+	*x = PlayerLeft{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *EntitySnapshot) GetTags() []string {
+func (x *PlayerLeft) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerLeft) ProtoMessage() {}
+
+func (x *PlayerLeft) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[27]
 	if x != nil {
-		return x.Tags
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *EntitySnapshot) GetFields() map[string]string {
+// Deprecated: Use PlayerLeft.ProtoReflect.Descriptor instead.
+func (*PlayerLeft) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PlayerLeft) GetPlayer() *EntitySnapshot {
 	if x != nil {
-		return x.Fields
+		return x.Player
 	}
 	return nil
 }
 
-func (x *EntitySnapshot) GetChildren() []*ChildRef {
+func (x *PlayerLeft) GetRoomId() string {
 	if x != nil {
-		return x.Children
+		return x.RoomId
 	}
-	return nil
+	return ""
 }
 
-type ChildRef struct {
+type EntityMoved struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TemplateId    string                 `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Tags          []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
-	Component     string                 `protobuf:"bytes,4,opt,name=component,proto3" json:"component,omitempty"` // "Room", "Inventory", "Container"
+	Entity        *EntitySnapshot        `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	FromRoomId    string                 `protobuf:"bytes,2,opt,name=from_room_id,json=fromRoomId,proto3" json:"from_room_id,omitempty"`
+	ToRoomId      string                 `protobuf:"bytes,3,opt,name=to_room_id,json=toRoomId,proto3" json:"to_room_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChildRef) Reset() {
-	*x = ChildRef{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[8]
+func (x *EntityMoved) Reset() {
+	*x = EntityMoved{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChildRef) String() string {
+func (x *EntityMoved) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChildRef) ProtoMessage() {}
+func (*EntityMoved) ProtoMessage() {}
 
-func (x *ChildRef) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[8]
+func (x *EntityMoved) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -688,61 +1939,110 @@ func (x *ChildRef) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChildRef.ProtoReflect.Descriptor instead.
-func (*ChildRef) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use EntityMoved.ProtoReflect.Descriptor instead.
+func (*EntityMoved) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *ChildRef) GetTemplateId() string {
+func (x *EntityMoved) GetEntity() *EntitySnapshot {
 	if x != nil {
-		return x.TemplateId
+		return x.Entity
+	}
+	return nil
+}
+
+func (x *EntityMoved) GetFromRoomId() string {
+	if x != nil {
+		return x.FromRoomId
 	}
 	return ""
 }
 
-func (x *ChildRef) GetName() string {
+func (x *EntityMoved) GetToRoomId() string {
 	if x != nil {
-		return x.Name
+		return x.ToRoomId
 	}
 	return ""
 }
 
-func (x *ChildRef) GetTags() []string {
+type Tick struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	UnixMs        int64                  `protobuf:"varint,2,opt,name=unix_ms,json=unixMs,proto3" json:"unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tick) Reset() {
+	*x = Tick{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tick) ProtoMessage() {}
+
+func (x *Tick) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[29]
 	if x != nil {
-		return x.Tags
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ChildRef) GetComponent() string {
+// Deprecated: Use Tick.ProtoReflect.Descriptor instead.
+func (*Tick) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Tick) GetSequence() int64 {
 	if x != nil {
-		return x.Component
+		return x.Sequence
 	}
-	return ""
+	return 0
 }
 
-type EngineUpdate struct {
+func (x *Tick) GetUnixMs() int64 {
+	if x != nil {
+		return x.UnixMs
+	}
+	return 0
+}
+
+// ActionScope fills the event roles for actions the game sends over
+// EventStream, since they aren't a reply to any player command.
+type ActionScope struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	SourceId      string                 `protobuf:"bytes,2,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	TargetId      string                 `protobuf:"bytes,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EngineUpdate) Reset() {
-	*x = EngineUpdate{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[9]
+func (x *ActionScope) Reset() {
+	*x = ActionScope{}
+	mi := &file_plugin_proto_orbis_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EngineUpdate) String() string {
+func (x *ActionScope) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EngineUpdate) ProtoMessage() {}
+func (*ActionScope) ProtoMessage() {}
 
-func (x *EngineUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[9]
+func (x *ActionScope) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_orbis_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -753,14 +2053,28 @@ func (x *EngineUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EngineUpdate.ProtoReflect.Descriptor instead.
-func (*EngineUpdate) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ActionScope.ProtoReflect.Descriptor instead.
+func (*ActionScope) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ActionScope) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ActionScope) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
 }
 
-func (x *EngineUpdate) GetType() string {
+func (x *ActionScope) GetTargetId() string {
 	if x != nil {
-		return x.Type
+		return x.TargetId
 	}
 	return ""
 }
@@ -768,13 +2082,14 @@ func (x *EngineUpdate) GetType() string {
 type ActionList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Actions       []*Action              `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+	Scope         *ActionScope           `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"` // only set on actions sent over EventStream
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ActionList) Reset() {
 	*x = ActionList{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[10]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -786,7 +2101,7 @@ func (x *ActionList) String() string {
 func (*ActionList) ProtoMessage() {}
 
 func (x *ActionList) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[10]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -799,7 +2114,7 @@ func (x *ActionList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActionList.ProtoReflect.Descriptor instead.
 func (*ActionList) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{10}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *ActionList) GetActions() []*Action {
@@ -809,6 +2124,13 @@ func (x *ActionList) GetActions() []*Action {
 	return nil
 }
 
+func (x *ActionList) GetScope() *ActionScope {
+	if x != nil {
+		return x.Scope
+	}
+	return nil
+}
+
 type Action struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Kind:
@@ -829,7 +2151,7 @@ type Action struct {
 
 func (x *Action) Reset() {
 	*x = Action{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[11]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -841,7 +2163,7 @@ func (x *Action) String() string {
 func (*Action) ProtoMessage() {}
 
 func (x *Action) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[11]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -854,7 +2176,7 @@ func (x *Action) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Action.ProtoReflect.Descriptor instead.
 func (*Action) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{11}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *Action) GetKind() isAction_Kind {
@@ -1014,7 +2336,7 @@ type PrintAction struct {
 
 func (x *PrintAction) Reset() {
 	*x = PrintAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[12]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1026,7 +2348,7 @@ func (x *PrintAction) String() string {
 func (*PrintAction) ProtoMessage() {}
 
 func (x *PrintAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[12]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1039,7 +2361,7 @@ func (x *PrintAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PrintAction.ProtoReflect.Descriptor instead.
 func (*PrintAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{12}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *PrintAction) GetRole() string {
@@ -1066,7 +2388,7 @@ type PublishAction struct {
 
 func (x *PublishAction) Reset() {
 	*x = PublishAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[13]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1078,7 +2400,7 @@ func (x *PublishAction) String() string {
 func (*PublishAction) ProtoMessage() {}
 
 func (x *PublishAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[13]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1091,7 +2413,7 @@ func (x *PublishAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PublishAction.ProtoReflect.Descriptor instead.
 func (*PublishAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{13}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *PublishAction) GetMessage() string {
@@ -1119,7 +2441,7 @@ type MoveAction struct {
 
 func (x *MoveAction) Reset() {
 	*x = MoveAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[14]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1131,7 +2453,7 @@ func (x *MoveAction) String() string {
 func (*MoveAction) ProtoMessage() {}
 
 func (x *MoveAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[14]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1144,7 +2466,7 @@ func (x *MoveAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MoveAction.ProtoReflect.Descriptor instead.
 func (*MoveAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{14}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *MoveAction) GetEntityRole() string {
@@ -1180,7 +2502,7 @@ type SetFieldAction struct {
 
 func (x *SetFieldAction) Reset() {
 	*x = SetFieldAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[15]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1192,7 +2514,7 @@ func (x *SetFieldAction) String() string {
 func (*SetFieldAction) ProtoMessage() {}
 
 func (x *SetFieldAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[15]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1205,7 +2527,7 @@ func (x *SetFieldAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetFieldAction.ProtoReflect.Descriptor instead.
 func (*SetFieldAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{15}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *SetFieldAction) GetRole() string {
@@ -1245,7 +2567,7 @@ type DestroyAction struct {
 
 func (x *DestroyAction) Reset() {
 	*x = DestroyAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[16]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1257,7 +2579,7 @@ func (x *DestroyAction) String() string {
 func (*DestroyAction) ProtoMessage() {}
 
 func (x *DestroyAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[16]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1270,7 +2592,7 @@ func (x *DestroyAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DestroyAction.ProtoReflect.Descriptor instead.
 func (*DestroyAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{16}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *DestroyAction) GetRole() string {
@@ -1291,7 +2613,7 @@ type SpawnAction struct {
 
 func (x *SpawnAction) Reset() {
 	*x = SpawnAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[17]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1303,7 +2625,7 @@ func (x *SpawnAction) String() string {
 func (*SpawnAction) ProtoMessage() {}
 
 func (x *SpawnAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[17]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1316,7 +2638,7 @@ func (x *SpawnAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SpawnAction.ProtoReflect.Descriptor instead.
 func (*SpawnAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{17}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *SpawnAction) GetTemplateId() string {
@@ -1350,7 +2672,7 @@ type AfterAction struct {
 
 func (x *AfterAction) Reset() {
 	*x = AfterAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[18]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1362,7 +2684,7 @@ func (x *AfterAction) String() string {
 func (*AfterAction) ProtoMessage() {}
 
 func (x *AfterAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[18]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1375,7 +2697,7 @@ func (x *AfterAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AfterAction.ProtoReflect.Descriptor instead.
 func (*AfterAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{18}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *AfterAction) GetDelayMs() int64 {
@@ -1402,7 +2724,7 @@ type RevealAction struct {
 
 func (x *RevealAction) Reset() {
 	*x = RevealAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[19]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1414,7 +2736,7 @@ func (x *RevealAction) String() string {
 func (*RevealAction) ProtoMessage() {}
 
 func (x *RevealAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[19]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1427,7 +2749,7 @@ func (x *RevealAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevealAction.ProtoReflect.Descriptor instead.
 func (*RevealAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{19}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *RevealAction) GetRole() string {
@@ -1454,7 +2776,7 @@ type HideAction struct {
 
 func (x *HideAction) Reset() {
 	*x = HideAction{}
-	mi := &file_plugin_proto_orbis_proto_msgTypes[20]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1466,7 +2788,7 @@ func (x *HideAction) String() string {
 func (*HideAction) ProtoMessage() {}
 
 func (x *HideAction) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_orbis_proto_msgTypes[20]
+	mi := &file_plugin_proto_orbis_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1479,7 +2801,7 @@ func (x *HideAction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HideAction.ProtoReflect.Descriptor instead.
 func (*HideAction) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{20}
+	return file_plugin_proto_orbis_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *HideAction) GetRole() string {
@@ -1518,7 +2840,7 @@ const file_plugin_proto_orbis_proto_rawDesc = "" +
 	"\n" +
 	"ExitsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe8\x03\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x97\x04\n" +
 	"\tEntityDef\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -1532,10 +2854,44 @@ const file_plugin_proto_orbis_proto_rawDesc = "" +
 	"\rhas_container\x18\n" +
 	" \x01(\bR\fhasContainer\x12)\n" +
 	"\x10container_prefix\x18\v \x01(\tR\x0fcontainerPrefix\x12-\n" +
-	"\x12container_revealed\x18\f \x01(\bR\x11containerRevealed\x1a9\n" +
+	"\x12container_revealed\x18\f \x01(\bR\x11containerRevealed\x12-\n" +
+	"\treactions\x18\r \x03(\v2\x0f.orbis.ReactionR\treactions\x1a9\n" +
 	"\vFieldsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"m\n" +
+	"\bReaction\x12\x18\n" +
+	"\acommand\x18\x01 \x01(\tR\acommand\x12$\n" +
+	"\x04when\x18\x02 \x03(\v2\x10.orbis.ConditionR\x04when\x12!\n" +
+	"\x04then\x18\x03 \x03(\v2\r.orbis.ActionR\x04then\"\xda\x02\n" +
+	"\tCondition\x12\x16\n" +
+	"\x06negate\x18\x01 \x01(\bR\x06negate\x121\n" +
+	"\ahas_tag\x18\x02 \x01(\v2\x16.orbis.HasTagConditionH\x00R\x06hasTag\x12@\n" +
+	"\ffield_equals\x18\x03 \x01(\v2\x1b.orbis.FieldEqualsConditionH\x00R\vfieldEquals\x12@\n" +
+	"\frole_present\x18\x04 \x01(\v2\x1b.orbis.RolePresentConditionH\x00R\vrolePresent\x12=\n" +
+	"\vroles_equal\x18\x05 \x01(\v2\x1a.orbis.RolesEqualConditionH\x00R\n" +
+	"rolesEqual\x127\n" +
+	"\thas_child\x18\x06 \x01(\v2\x18.orbis.HasChildConditionH\x00R\bhasChildB\x06\n" +
+	"\x04kind\"7\n" +
+	"\x0fHasTagCondition\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\"u\n" +
+	"\x14FieldEqualsCondition\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x1d\n" +
+	"\n" +
+	"value_type\x18\x04 \x01(\tR\tvalueType\"*\n" +
+	"\x14RolePresentCondition\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\"?\n" +
+	"\x13RolesEqualCondition\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x14\n" +
+	"\x05other\x18\x02 \x01(\tR\x05other\"q\n" +
+	"\x11HasChildCondition\x12\x1d\n" +
+	"\n" +
+	"child_role\x18\x01 \x01(\tR\tchildRole\x12\x1f\n" +
+	"\vparent_role\x18\x02 \x01(\tR\n" +
+	"parentRole\x12\x1c\n" +
+	"\tcomponent\x18\x03 \x01(\tR\tcomponent\"m\n" +
 	"\n" +
 	"CommandDef\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
@@ -1544,7 +2900,7 @@ const file_plugin_proto_orbis_proto_rawDesc = "" +
 	"\x0eCommandPattern\x12\x16\n" +
 	"\x06syntax\x18\x01 \x01(\tR\x06syntax\x12\x19\n" +
 	"\bno_match\x18\x02 \x01(\tR\anoMatch\x12\x12\n" +
-	"\x04help\x18\x03 \x01(\tR\x04help\"\x9f\x02\n" +
+	"\x04help\x18\x03 \x01(\tR\x04help\"\xea\x02\n" +
 	"\fEventRequest\x12\x18\n" +
 	"\acommand\x18\x01 \x01(\tR\acommand\x12\x1b\n" +
 	"\ttarget_id\x18\x02 \x01(\tR\btargetId\x12-\n" +
@@ -1554,7 +2910,10 @@ const file_plugin_proto_orbis_proto_rawDesc = "" +
 	"instrument\x18\x05 \x01(\v2\x15.orbis.EntitySnapshotR\n" +
 	"instrument\x12)\n" +
 	"\x04room\x18\x06 \x01(\v2\x15.orbis.EntitySnapshotR\x04room\x12\x18\n" +
-	"\amessage\x18\a \x01(\tR\amessage\"\xb8\x02\n" +
+	"\amessage\x18\a \x01(\tR\amessage\x12(\n" +
+	"\x10engine_broker_id\x18\b \x01(\rR\x0eengineBrokerId\x12\x1f\n" +
+	"\vengine_addr\x18\t \x01(\tR\n" +
+	"engineAddr\"\xc8\x02\n" +
 	"\x0eEntitySnapshot\x12\x1f\n" +
 	"\vtemplate_id\x18\x01 \x01(\tR\n" +
 	"templateId\x12\x12\n" +
@@ -1563,21 +2922,80 @@ const file_plugin_proto_orbis_proto_rawDesc = "" +
 	"\aaliases\x18\x04 \x03(\tR\aaliases\x12\x12\n" +
 	"\x04tags\x18\x05 \x03(\tR\x04tags\x129\n" +
 	"\x06fields\x18\x06 \x03(\v2!.orbis.EntitySnapshot.FieldsEntryR\x06fields\x12+\n" +
-	"\bchildren\x18\a \x03(\v2\x0f.orbis.ChildRefR\bchildren\x1a9\n" +
+	"\bchildren\x18\a \x03(\v2\x0f.orbis.ChildRefR\bchildren\x12\x0e\n" +
+	"\x02id\x18\b \x01(\tR\x02id\x1a9\n" +
 	"\vFieldsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"q\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x81\x01\n" +
 	"\bChildRef\x12\x1f\n" +
 	"\vtemplate_id\x18\x01 \x01(\tR\n" +
 	"templateId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
 	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x1c\n" +
-	"\tcomponent\x18\x04 \x01(\tR\tcomponent\"\"\n" +
-	"\fEngineUpdate\x12\x12\n" +
-	"\x04type\x18\x01 \x01(\tR\x04type\"5\n" +
+	"\tcomponent\x18\x04 \x01(\tR\tcomponent\x12\x0e\n" +
+	"\x02id\x18\x05 \x01(\tR\x02id\"\x1d\n" +
+	"\vEntityQuery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"=\n" +
+	"\rChildrenQuery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tcomponent\x18\x02 \x01(\tR\tcomponent\"?\n" +
+	"\n" +
+	"EntityList\x121\n" +
+	"\bentities\x18\x01 \x03(\v2\x15.orbis.EntitySnapshotR\bentities\"9\n" +
+	"\n" +
+	"PlayerList\x12+\n" +
+	"\aplayers\x18\x01 \x03(\v2\x11.orbis.PlayerInfoR\aplayers\"\x8d\x01\n" +
+	"\n" +
+	"PlayerInfo\x12-\n" +
+	"\x06player\x18\x01 \x01(\v2\x15.orbis.EntitySnapshotR\x06player\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1e\n" +
+	"\n" +
+	"connection\x18\x03 \x01(\tR\n" +
+	"connection\x12\x17\n" +
+	"\aidle_ms\x18\x04 \x01(\x03R\x06idleMs\",\n" +
+	"\tRoomQuery\x12\x1f\n" +
+	"\vtemplate_id\x18\x01 \x01(\tR\n" +
+	"templateId\"%\n" +
+	"\n" +
+	"ExitsQuery\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"-\n" +
+	"\bExitList\x12!\n" +
+	"\x05exits\x18\x01 \x03(\v2\v.orbis.ExitR\x05exits\"Z\n" +
+	"\x04Exit\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\tR\tdirection\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\troom_name\x18\x03 \x01(\tR\broomName\"\xea\x01\n" +
+	"\fEngineUpdate\x12:\n" +
+	"\rplayer_joined\x18\x02 \x01(\v2\x13.orbis.PlayerJoinedH\x00R\fplayerJoined\x124\n" +
+	"\vplayer_left\x18\x03 \x01(\v2\x11.orbis.PlayerLeftH\x00R\n" +
+	"playerLeft\x127\n" +
+	"\fentity_moved\x18\x04 \x01(\v2\x12.orbis.EntityMovedH\x00R\ventityMoved\x12!\n" +
+	"\x04tick\x18\x05 \x01(\v2\v.orbis.TickH\x00R\x04tickB\x06\n" +
+	"\x04kindJ\x04\b\x01\x10\x02\"V\n" +
+	"\fPlayerJoined\x12-\n" +
+	"\x06player\x18\x01 \x01(\v2\x15.orbis.EntitySnapshotR\x06player\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\"T\n" +
+	"\n" +
+	"PlayerLeft\x12-\n" +
+	"\x06player\x18\x01 \x01(\v2\x15.orbis.EntitySnapshotR\x06player\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\"|\n" +
+	"\vEntityMoved\x12-\n" +
+	"\x06entity\x18\x01 \x01(\v2\x15.orbis.EntitySnapshotR\x06entity\x12 \n" +
+	"\ffrom_room_id\x18\x02 \x01(\tR\n" +
+	"fromRoomId\x12\x1c\n" +
+	"\n" +
+	"to_room_id\x18\x03 \x01(\tR\btoRoomId\";\n" +
+	"\x04Tick\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\x17\n" +
+	"\aunix_ms\x18\x02 \x01(\x03R\x06unixMs\"`\n" +
+	"\vActionScope\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\tsource_id\x18\x02 \x01(\tR\bsourceId\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\tR\btargetId\"_\n" +
 	"\n" +
 	"ActionList\x12'\n" +
-	"\aactions\x18\x01 \x03(\v2\r.orbis.ActionR\aactions\"\xaf\x03\n" +
+	"\aactions\x18\x01 \x03(\v2\r.orbis.ActionR\aactions\x12(\n" +
+	"\x05scope\x18\x02 \x01(\v2\x12.orbis.ActionScopeR\x05scope\"\xaf\x03\n" +
 	"\x06Action\x12*\n" +
 	"\x05print\x18\x01 \x01(\v2\x12.orbis.PrintActionH\x00R\x05print\x120\n" +
 	"\apublish\x18\x02 \x01(\v2\x14.orbis.PublishActionH\x00R\apublish\x12'\n" +
@@ -1627,7 +3045,13 @@ const file_plugin_proto_orbis_proto_rawDesc = "" +
 	"\tOrbisGame\x120\n" +
 	"\vGetManifest\x12\f.orbis.Empty\x1a\x13.orbis.GameManifest\x125\n" +
 	"\vHandleEvent\x12\x13.orbis.EventRequest\x1a\x11.orbis.ActionList\x129\n" +
-	"\vEventStream\x12\x13.orbis.EngineUpdate\x1a\x11.orbis.ActionList(\x010\x01B\x1eZ\x1cexample.com/mud/plugin/protob\x06proto3"
+	"\vEventStream\x12\x13.orbis.EngineUpdate\x1a\x11.orbis.ActionList(\x010\x012\x97\x02\n" +
+	"\vOrbisEngine\x126\n" +
+	"\tGetEntity\x12\x12.orbis.EntityQuery\x1a\x15.orbis.EntitySnapshot\x127\n" +
+	"\fListChildren\x12\x14.orbis.ChildrenQuery\x1a\x11.orbis.EntityList\x12.\n" +
+	"\vListPlayers\x12\f.orbis.Empty\x1a\x11.orbis.PlayerList\x123\n" +
+	"\bFindRoom\x12\x10.orbis.RoomQuery\x1a\x15.orbis.EntitySnapshot\x122\n" +
+	"\fResolveExits\x12\x11.orbis.ExitsQuery\x1a\x0f.orbis.ExitListB\x1eZ\x1cexample.com/mud/plugin/protob\x06proto3"
 
 var (
 	file_plugin_proto_orbis_proto_rawDescOnce sync.Once
@@ -1641,68 +3065,119 @@ func file_plugin_proto_orbis_proto_rawDescGZIP() []byte {
 	return file_plugin_proto_orbis_proto_rawDescData
 }
 
-var file_plugin_proto_orbis_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_plugin_proto_orbis_proto_msgTypes = make([]protoimpl.MessageInfo, 45)
 var file_plugin_proto_orbis_proto_goTypes = []any{
-	(*Empty)(nil),          // 0: orbis.Empty
-	(*GameManifest)(nil),   // 1: orbis.GameManifest
-	(*RoomDef)(nil),        // 2: orbis.RoomDef
-	(*EntityDef)(nil),      // 3: orbis.EntityDef
-	(*CommandDef)(nil),     // 4: orbis.CommandDef
-	(*CommandPattern)(nil), // 5: orbis.CommandPattern
-	(*EventRequest)(nil),   // 6: orbis.EventRequest
-	(*EntitySnapshot)(nil), // 7: orbis.EntitySnapshot
-	(*ChildRef)(nil),       // 8: orbis.ChildRef
-	(*EngineUpdate)(nil),   // 9: orbis.EngineUpdate
-	(*ActionList)(nil),     // 10: orbis.ActionList
-	(*Action)(nil),         // 11: orbis.Action
-	(*PrintAction)(nil),    // 12: orbis.PrintAction
-	(*PublishAction)(nil),  // 13: orbis.PublishAction
-	(*MoveAction)(nil),     // 14: orbis.MoveAction
-	(*SetFieldAction)(nil), // 15: orbis.SetFieldAction
-	(*DestroyAction)(nil),  // 16: orbis.DestroyAction
-	(*SpawnAction)(nil),    // 17: orbis.SpawnAction
-	(*AfterAction)(nil),    // 18: orbis.AfterAction
-	(*RevealAction)(nil),   // 19: orbis.RevealAction
-	(*HideAction)(nil),     // 20: orbis.HideAction
-	nil,                    // 21: orbis.RoomDef.ExitsEntry
-	nil,                    // 22: orbis.EntityDef.FieldsEntry
-	nil,                    // 23: orbis.EntitySnapshot.FieldsEntry
+	(*Empty)(nil),                // 0: orbis.Empty
+	(*GameManifest)(nil),         // 1: orbis.GameManifest
+	(*RoomDef)(nil),              // 2: orbis.RoomDef
+	(*EntityDef)(nil),            // 3: orbis.EntityDef
+	(*Reaction)(nil),             // 4: orbis.Reaction
+	(*Condition)(nil),            // 5: orbis.Condition
+	(*HasTagCondition)(nil),      // 6: orbis.HasTagCondition
+	(*FieldEqualsCondition)(nil), // 7: orbis.FieldEqualsCondition
+	(*RolePresentCondition)(nil), // 8: orbis.RolePresentCondition
+	(*RolesEqualCondition)(nil),  // 9: orbis.RolesEqualCondition
+	(*HasChildCondition)(nil),    // 10: orbis.HasChildCondition
+	(*CommandDef)(nil),           // 11: orbis.CommandDef
+	(*CommandPattern)(nil),       // 12: orbis.CommandPattern
+	(*EventRequest)(nil),         // 13: orbis.EventRequest
+	(*EntitySnapshot)(nil),       // 14: orbis.EntitySnapshot
+	(*ChildRef)(nil),             // 15: orbis.ChildRef
+	(*EntityQuery)(nil),          // 16: orbis.EntityQuery
+	(*ChildrenQuery)(nil),        // 17: orbis.ChildrenQuery
+	(*EntityList)(nil),           // 18: orbis.EntityList
+	(*PlayerList)(nil),           // 19: orbis.PlayerList
+	(*PlayerInfo)(nil),           // 20: orbis.PlayerInfo
+	(*RoomQuery)(nil),            // 21: orbis.RoomQuery
+	(*ExitsQuery)(nil),           // 22: orbis.ExitsQuery
+	(*ExitList)(nil),             // 23: orbis.ExitList
+	(*Exit)(nil),                 // 24: orbis.Exit
+	(*EngineUpdate)(nil),         // 25: orbis.EngineUpdate
+	(*PlayerJoined)(nil),         // 26: orbis.PlayerJoined
+	(*PlayerLeft)(nil),           // 27: orbis.PlayerLeft
+	(*EntityMoved)(nil),          // 28: orbis.EntityMoved
+	(*Tick)(nil),                 // 29: orbis.Tick
+	(*ActionScope)(nil),          // 30: orbis.ActionScope
+	(*ActionList)(nil),           // 31: orbis.ActionList
+	(*Action)(nil),               // 32: orbis.Action
+	(*PrintAction)(nil),          // 33: orbis.PrintAction
+	(*PublishAction)(nil),        // 34: orbis.PublishAction
+	(*MoveAction)(nil),           // 35: orbis.MoveAction
+	(*SetFieldAction)(nil),       // 36: orbis.SetFieldAction
+	(*DestroyAction)(nil),        // 37: orbis.DestroyAction
+	(*SpawnAction)(nil),          // 38: orbis.SpawnAction
+	(*AfterAction)(nil),          // 39: orbis.AfterAction
+	(*RevealAction)(nil),         // 40: orbis.RevealAction
+	(*HideAction)(nil),           // 41: orbis.HideAction
+	nil,                          // 42: orbis.RoomDef.ExitsEntry
+	nil,                          // 43: orbis.EntityDef.FieldsEntry
+	nil,                          // 44: orbis.EntitySnapshot.FieldsEntry
 }
 var file_plugin_proto_orbis_proto_depIdxs = []int32{
 	2,  // 0: orbis.GameManifest.rooms:type_name -> orbis.RoomDef
 	3,  // 1: orbis.GameManifest.entities:type_name -> orbis.EntityDef
-	4,  // 2: orbis.GameManifest.commands:type_name -> orbis.CommandDef
-	21, // 3: orbis.RoomDef.exits:type_name -> orbis.RoomDef.ExitsEntry
-	22, // 4: orbis.EntityDef.fields:type_name -> orbis.EntityDef.FieldsEntry
-	5,  // 5: orbis.CommandDef.patterns:type_name -> orbis.CommandPattern
-	7,  // 6: orbis.EventRequest.source:type_name -> orbis.EntitySnapshot
-	7,  // 7: orbis.EventRequest.target:type_name -> orbis.EntitySnapshot
-	7,  // 8: orbis.EventRequest.instrument:type_name -> orbis.EntitySnapshot
-	7,  // 9: orbis.EventRequest.room:type_name -> orbis.EntitySnapshot
-	23, // 10: orbis.EntitySnapshot.fields:type_name -> orbis.EntitySnapshot.FieldsEntry
-	8,  // 11: orbis.EntitySnapshot.children:type_name -> orbis.ChildRef
-	11, // 12: orbis.ActionList.actions:type_name -> orbis.Action
-	12, // 13: orbis.Action.print:type_name -> orbis.PrintAction
-	13, // 14: orbis.Action.publish:type_name -> orbis.PublishAction
-	14, // 15: orbis.Action.move:type_name -> orbis.MoveAction
-	15, // 16: orbis.Action.set_field:type_name -> orbis.SetFieldAction
-	16, // 17: orbis.Action.destroy:type_name -> orbis.DestroyAction
-	17, // 18: orbis.Action.spawn:type_name -> orbis.SpawnAction
-	18, // 19: orbis.Action.after:type_name -> orbis.AfterAction
-	19, // 20: orbis.Action.reveal:type_name -> orbis.RevealAction
-	20, // 21: orbis.Action.hide:type_name -> orbis.HideAction
-	11, // 22: orbis.AfterAction.actions:type_name -> orbis.Action
-	0,  // 23: orbis.OrbisGame.GetManifest:input_type -> orbis.Empty
-	6,  // 24: orbis.OrbisGame.HandleEvent:input_type -> orbis.EventRequest
-	9,  // 25: orbis.OrbisGame.EventStream:input_type -> orbis.EngineUpdate
-	1,  // 26: orbis.OrbisGame.GetManifest:output_type -> orbis.GameManifest
-	10, // 27: orbis.OrbisGame.HandleEvent:output_type -> orbis.ActionList
-	10, // 28: orbis.OrbisGame.EventStream:output_type -> orbis.ActionList
-	26, // [26:29] is the sub-list for method output_type
-	23, // [23:26] is the sub-list for method input_type
-	23, // [23:23] is the sub-list for extension type_name
-	23, // [23:23] is the sub-list for extension extendee
-	0,  // [0:23] is the sub-list for field type_name
+	11, // 2: orbis.GameManifest.commands:type_name -> orbis.CommandDef
+	42, // 3: orbis.RoomDef.exits:type_name -> orbis.RoomDef.ExitsEntry
+	43, // 4: orbis.EntityDef.fields:type_name -> orbis.EntityDef.FieldsEntry
+	4,  // 5: orbis.EntityDef.reactions:type_name -> orbis.Reaction
+	5,  // 6: orbis.Reaction.when:type_name -> orbis.Condition
+	32, // 7: orbis.Reaction.then:type_name -> orbis.Action
+	6,  // 8: orbis.Condition.has_tag:type_name -> orbis.HasTagCondition
+	7,  // 9: orbis.Condition.field_equals:type_name -> orbis.FieldEqualsCondition
+	8,  // 10: orbis.Condition.role_present:type_name -> orbis.RolePresentCondition
+	9,  // 11: orbis.Condition.roles_equal:type_name -> orbis.RolesEqualCondition
+	10, // 12: orbis.Condition.has_child:type_name -> orbis.HasChildCondition
+	12, // 13: orbis.CommandDef.patterns:type_name -> orbis.CommandPattern
+	14, // 14: orbis.EventRequest.source:type_name -> orbis.EntitySnapshot
+	14, // 15: orbis.EventRequest.target:type_name -> orbis.EntitySnapshot
+	14, // 16: orbis.EventRequest.instrument:type_name -> orbis.EntitySnapshot
+	14, // 17: orbis.EventRequest.room:type_name -> orbis.EntitySnapshot
+	44, // 18: orbis.EntitySnapshot.fields:type_name -> orbis.EntitySnapshot.FieldsEntry
+	15, // 19: orbis.EntitySnapshot.children:type_name -> orbis.ChildRef
+	14, // 20: orbis.EntityList.entities:type_name -> orbis.EntitySnapshot
+	20, // 21: orbis.PlayerList.players:type_name -> orbis.PlayerInfo
+	14, // 22: orbis.PlayerInfo.player:type_name -> orbis.EntitySnapshot
+	24, // 23: orbis.ExitList.exits:type_name -> orbis.Exit
+	26, // 24: orbis.EngineUpdate.player_joined:type_name -> orbis.PlayerJoined
+	27, // 25: orbis.EngineUpdate.player_left:type_name -> orbis.PlayerLeft
+	28, // 26: orbis.EngineUpdate.entity_moved:type_name -> orbis.EntityMoved
+	29, // 27: orbis.EngineUpdate.tick:type_name -> orbis.Tick
+	14, // 28: orbis.PlayerJoined.player:type_name -> orbis.EntitySnapshot
+	14, // 29: orbis.PlayerLeft.player:type_name -> orbis.EntitySnapshot
+	14, // 30: orbis.EntityMoved.entity:type_name -> orbis.EntitySnapshot
+	32, // 31: orbis.ActionList.actions:type_name -> orbis.Action
+	30, // 32: orbis.ActionList.scope:type_name -> orbis.ActionScope
+	33, // 33: orbis.Action.print:type_name -> orbis.PrintAction
+	34, // 34: orbis.Action.publish:type_name -> orbis.PublishAction
+	35, // 35: orbis.Action.move:type_name -> orbis.MoveAction
+	36, // 36: orbis.Action.set_field:type_name -> orbis.SetFieldAction
+	37, // 37: orbis.Action.destroy:type_name -> orbis.DestroyAction
+	38, // 38: orbis.Action.spawn:type_name -> orbis.SpawnAction
+	39, // 39: orbis.Action.after:type_name -> orbis.AfterAction
+	40, // 40: orbis.Action.reveal:type_name -> orbis.RevealAction
+	41, // 41: orbis.Action.hide:type_name -> orbis.HideAction
+	32, // 42: orbis.AfterAction.actions:type_name -> orbis.Action
+	0,  // 43: orbis.OrbisGame.GetManifest:input_type -> orbis.Empty
+	13, // 44: orbis.OrbisGame.HandleEvent:input_type -> orbis.EventRequest
+	25, // 45: orbis.OrbisGame.EventStream:input_type -> orbis.EngineUpdate
+	16, // 46: orbis.OrbisEngine.GetEntity:input_type -> orbis.EntityQuery
+	17, // 47: orbis.OrbisEngine.ListChildren:input_type -> orbis.ChildrenQuery
+	0,  // 48: orbis.OrbisEngine.ListPlayers:input_type -> orbis.Empty
+	21, // 49: orbis.OrbisEngine.FindRoom:input_type -> orbis.RoomQuery
+	22, // 50: orbis.OrbisEngine.ResolveExits:input_type -> orbis.ExitsQuery
+	1,  // 51: orbis.OrbisGame.GetManifest:output_type -> orbis.GameManifest
+	31, // 52: orbis.OrbisGame.HandleEvent:output_type -> orbis.ActionList
+	31, // 53: orbis.OrbisGame.EventStream:output_type -> orbis.ActionList
+	14, // 54: orbis.OrbisEngine.GetEntity:output_type -> orbis.EntitySnapshot
+	18, // 55: orbis.OrbisEngine.ListChildren:output_type -> orbis.EntityList
+	19, // 56: orbis.OrbisEngine.ListPlayers:output_type -> orbis.PlayerList
+	14, // 57: orbis.OrbisEngine.FindRoom:output_type -> orbis.EntitySnapshot
+	23, // 58: orbis.OrbisEngine.ResolveExits:output_type -> orbis.ExitList
+	51, // [51:59] is the sub-list for method output_type
+	43, // [43:51] is the sub-list for method input_type
+	43, // [43:43] is the sub-list for extension type_name
+	43, // [43:43] is the sub-list for extension extendee
+	0,  // [0:43] is the sub-list for field type_name
 }
 
 func init() { file_plugin_proto_orbis_proto_init() }
@@ -1710,7 +3185,20 @@ func file_plugin_proto_orbis_proto_init() {
 	if File_plugin_proto_orbis_proto != nil {
 		return
 	}
-	file_plugin_proto_orbis_proto_msgTypes[11].OneofWrappers = []any{
+	file_plugin_proto_orbis_proto_msgTypes[5].OneofWrappers = []any{
+		(*Condition_HasTag)(nil),
+		(*Condition_FieldEquals)(nil),
+		(*Condition_RolePresent)(nil),
+		(*Condition_RolesEqual)(nil),
+		(*Condition_HasChild)(nil),
+	}
+	file_plugin_proto_orbis_proto_msgTypes[25].OneofWrappers = []any{
+		(*EngineUpdate_PlayerJoined)(nil),
+		(*EngineUpdate_PlayerLeft)(nil),
+		(*EngineUpdate_EntityMoved)(nil),
+		(*EngineUpdate_Tick)(nil),
+	}
+	file_plugin_proto_orbis_proto_msgTypes[32].OneofWrappers = []any{
 		(*Action_Print)(nil),
 		(*Action_Publish)(nil),
 		(*Action_Move)(nil),
@@ -1727,9 +3215,9 @@ func file_plugin_proto_orbis_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_proto_orbis_proto_rawDesc), len(file_plugin_proto_orbis_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   24,
+			NumMessages:   45,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_plugin_proto_orbis_proto_goTypes,
 		DependencyIndexes: file_plugin_proto_orbis_proto_depIdxs,
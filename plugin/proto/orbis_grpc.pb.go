@@ -190,3 +190,263 @@ var OrbisGame_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "plugin/proto/orbis.proto",
 }
+
+const (
+	OrbisEngine_GetEntity_FullMethodName    = "/orbis.OrbisEngine/GetEntity"
+	OrbisEngine_ListChildren_FullMethodName = "/orbis.OrbisEngine/ListChildren"
+	OrbisEngine_ListPlayers_FullMethodName  = "/orbis.OrbisEngine/ListPlayers"
+	OrbisEngine_FindRoom_FullMethodName     = "/orbis.OrbisEngine/FindRoom"
+	OrbisEngine_ResolveExits_FullMethodName = "/orbis.OrbisEngine/ResolveExits"
+)
+
+// OrbisEngineClient is the client API for OrbisEngine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OrbisEngine is served by the engine back to the game, over the go-plugin
+// broker, so the game can query world state while it handles an event.
+type OrbisEngineClient interface {
+	GetEntity(ctx context.Context, in *EntityQuery, opts ...grpc.CallOption) (*EntitySnapshot, error)
+	ListChildren(ctx context.Context, in *ChildrenQuery, opts ...grpc.CallOption) (*EntityList, error)
+	ListPlayers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PlayerList, error)
+	FindRoom(ctx context.Context, in *RoomQuery, opts ...grpc.CallOption) (*EntitySnapshot, error)
+	ResolveExits(ctx context.Context, in *ExitsQuery, opts ...grpc.CallOption) (*ExitList, error)
+}
+
+type orbisEngineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrbisEngineClient(cc grpc.ClientConnInterface) OrbisEngineClient {
+	return &orbisEngineClient{cc}
+}
+
+func (c *orbisEngineClient) GetEntity(ctx context.Context, in *EntityQuery, opts ...grpc.CallOption) (*EntitySnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EntitySnapshot)
+	err := c.cc.Invoke(ctx, OrbisEngine_GetEntity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orbisEngineClient) ListChildren(ctx context.Context, in *ChildrenQuery, opts ...grpc.CallOption) (*EntityList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EntityList)
+	err := c.cc.Invoke(ctx, OrbisEngine_ListChildren_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orbisEngineClient) ListPlayers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PlayerList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlayerList)
+	err := c.cc.Invoke(ctx, OrbisEngine_ListPlayers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orbisEngineClient) FindRoom(ctx context.Context, in *RoomQuery, opts ...grpc.CallOption) (*EntitySnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EntitySnapshot)
+	err := c.cc.Invoke(ctx, OrbisEngine_FindRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orbisEngineClient) ResolveExits(ctx context.Context, in *ExitsQuery, opts ...grpc.CallOption) (*ExitList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExitList)
+	err := c.cc.Invoke(ctx, OrbisEngine_ResolveExits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrbisEngineServer is the server API for OrbisEngine service.
+// All implementations must embed UnimplementedOrbisEngineServer
+// for forward compatibility.
+//
+// OrbisEngine is served by the engine back to the game, over the go-plugin
+// broker, so the game can query world state while it handles an event.
+type OrbisEngineServer interface {
+	GetEntity(context.Context, *EntityQuery) (*EntitySnapshot, error)
+	ListChildren(context.Context, *ChildrenQuery) (*EntityList, error)
+	ListPlayers(context.Context, *Empty) (*PlayerList, error)
+	FindRoom(context.Context, *RoomQuery) (*EntitySnapshot, error)
+	ResolveExits(context.Context, *ExitsQuery) (*ExitList, error)
+	mustEmbedUnimplementedOrbisEngineServer()
+}
+
+// UnimplementedOrbisEngineServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrbisEngineServer struct{}
+
+func (UnimplementedOrbisEngineServer) GetEntity(context.Context, *EntityQuery) (*EntitySnapshot, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetEntity not implemented")
+}
+func (UnimplementedOrbisEngineServer) ListChildren(context.Context, *ChildrenQuery) (*EntityList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListChildren not implemented")
+}
+func (UnimplementedOrbisEngineServer) ListPlayers(context.Context, *Empty) (*PlayerList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPlayers not implemented")
+}
+func (UnimplementedOrbisEngineServer) FindRoom(context.Context, *RoomQuery) (*EntitySnapshot, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindRoom not implemented")
+}
+func (UnimplementedOrbisEngineServer) ResolveExits(context.Context, *ExitsQuery) (*ExitList, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveExits not implemented")
+}
+func (UnimplementedOrbisEngineServer) mustEmbedUnimplementedOrbisEngineServer() {}
+func (UnimplementedOrbisEngineServer) testEmbeddedByValue()                     {}
+
+// UnsafeOrbisEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrbisEngineServer will
+// result in compilation errors.
+type UnsafeOrbisEngineServer interface {
+	mustEmbedUnimplementedOrbisEngineServer()
+}
+
+func RegisterOrbisEngineServer(s grpc.ServiceRegistrar, srv OrbisEngineServer) {
+	// If the following call panics, it indicates UnimplementedOrbisEngineServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrbisEngine_ServiceDesc, srv)
+}
+
+func _OrbisEngine_GetEntity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EntityQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrbisEngineServer).GetEntity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrbisEngine_GetEntity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrbisEngineServer).GetEntity(ctx, req.(*EntityQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrbisEngine_ListChildren_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChildrenQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrbisEngineServer).ListChildren(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrbisEngine_ListChildren_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrbisEngineServer).ListChildren(ctx, req.(*ChildrenQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrbisEngine_ListPlayers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrbisEngineServer).ListPlayers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrbisEngine_ListPlayers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrbisEngineServer).ListPlayers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrbisEngine_FindRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrbisEngineServer).FindRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrbisEngine_FindRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrbisEngineServer).FindRoom(ctx, req.(*RoomQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrbisEngine_ResolveExits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExitsQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrbisEngineServer).ResolveExits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrbisEngine_ResolveExits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrbisEngineServer).ResolveExits(ctx, req.(*ExitsQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrbisEngine_ServiceDesc is the grpc.ServiceDesc for OrbisEngine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrbisEngine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orbis.OrbisEngine",
+	HandlerType: (*OrbisEngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEntity",
+			Handler:    _OrbisEngine_GetEntity_Handler,
+		},
+		{
+			MethodName: "ListChildren",
+			Handler:    _OrbisEngine_ListChildren_Handler,
+		},
+		{
+			MethodName: "ListPlayers",
+			Handler:    _OrbisEngine_ListPlayers_Handler,
+		},
+		{
+			MethodName: "FindRoom",
+			Handler:    _OrbisEngine_FindRoom_Handler,
+		},
+		{
+			MethodName: "ResolveExits",
+			Handler:    _OrbisEngine_ResolveExits_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin/proto/orbis.proto",
+}
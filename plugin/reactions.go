@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"fmt"
+
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/entities/conditions"
+)
+
+// compileReactions turns an entity's declarative reactions into a local
+// Eventful, so they run in the engine without a call to the game. Returns nil
+// when there are no reactions.
+func compileReactions(templateID string, reactions []*pb.Reaction) (*components.Eventful, error) {
+	if len(reactions) == 0 {
+		return nil, nil
+	}
+
+	eventful := &components.Eventful{Rules: make(map[string][]*entities.Rule)}
+	for i, r := range reactions {
+		rule := &entities.Rule{}
+
+		for _, pc := range r.When {
+			cond, err := protoConditionToEngineCondition(pc)
+			if err != nil {
+				return nil, fmt.Errorf("reaction to '%s': %w", r.Command, err)
+			}
+			rule.When = append(rule.When, cond)
+		}
+
+		for j, pa := range r.Then {
+			a, err := protoActionToEngineAction(pa)
+			if err != nil {
+				return nil, fmt.Errorf("reaction to '%s': %w", r.Command, err)
+			}
+			keyScheduled(a, fmt.Sprintf("plugin:%s/%d/%d", templateID, i, j))
+			rule.Then = append(rule.Then, a)
+		}
+
+		eventful.AddRule(r.Command, rule)
+	}
+
+	return eventful, nil
+}
+
+func protoConditionToEngineCondition(pc *pb.Condition) (entities.Condition, error) {
+	var cond entities.Condition
+
+	switch kind := pc.Kind.(type) {
+	case *pb.Condition_HasTag:
+		role, err := parseRole(kind.HasTag.Role)
+		if err != nil {
+			return nil, err
+		}
+		cond = &conditions.HasTag{EventRole: role, Tag: kind.HasTag.Tag}
+
+	case *pb.Condition_FieldEquals:
+		role, err := parseRole(kind.FieldEquals.Role)
+		if err != nil {
+			return nil, err
+		}
+		val, err := decodeFieldValue(kind.FieldEquals.Value, kind.FieldEquals.ValueType)
+		if err != nil {
+			return nil, err
+		}
+		cond = &conditions.FieldEquals{EventRole: role, Field: kind.FieldEquals.Field, Value: val}
+
+	case *pb.Condition_RolePresent:
+		role, err := parseRole(kind.RolePresent.Role)
+		if err != nil {
+			return nil, err
+		}
+		cond = &conditions.IsPresent{EventRole: role}
+
+	case *pb.Condition_RolesEqual:
+		role, err := parseRole(kind.RolesEqual.Role)
+		if err != nil {
+			return nil, err
+		}
+		other, err := parseRole(kind.RolesEqual.Other)
+		if err != nil {
+			return nil, err
+		}
+		cond = &conditions.EventRolesEqual{EventRole1: role, EventRole2: other}
+
+	case *pb.Condition_HasChild:
+		child, err := parseRole(kind.HasChild.ChildRole)
+		if err != nil {
+			return nil, err
+		}
+		parent, err := parseRole(kind.HasChild.ParentRole)
+		if err != nil {
+			return nil, err
+		}
+		comp, err := entities.ParseComponentType(kind.HasChild.Component)
+		if err != nil {
+			return nil, err
+		}
+		cond = &conditions.HasChild{ParentRole: parent, ComponentType: comp, ChildRole: child}
+
+	default:
+		return nil, fmt.Errorf("unknown condition kind: %T", pc.Kind)
+	}
+
+	if pc.Negate {
+		cond = &conditions.Not{Cond: cond}
+	}
+	return cond, nil
+}
+
+// keyScheduled gives delayed actions from the manifest a stable key, so jobs
+// they leave pending survive a restart. Delayed actions the game returns from
+// HandleEvent are built per event and stay unkeyed.
+func keyScheduled(a entities.Action, key string) {
+	so, ok := a.(*actions.ScheduleOnce)
+	if !ok {
+		return
+	}
+
+	so.Key = key
+	actions.RegisterResumable(key, so)
+	for i, child := range so.Actions {
+		keyScheduled(child, fmt.Sprintf("%s/%d", key, i))
+	}
+}
@@ -0,0 +1,206 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/models"
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/entities/conditions"
+)
+
+func TestProtoConditionToEngineCondition(t *testing.T) {
+	t.Parallel()
+
+	hasTag := func(role, tag string) *pb.Condition_HasTag {
+		return &pb.Condition_HasTag{HasTag: &pb.HasTagCondition{Role: role, Tag: tag}}
+	}
+	fieldEquals := func(value, valueType string) *pb.Condition_FieldEquals {
+		return &pb.Condition_FieldEquals{FieldEquals: &pb.FieldEqualsCondition{Role: "target", Field: "hp", Value: value, ValueType: valueType}}
+	}
+
+	type tc struct {
+		name    string
+		cond    *pb.Condition
+		want    entities.Condition
+		wantErr string
+	}
+
+	cases := []tc{
+		{
+			name: "has tag",
+			cond: &pb.Condition{Kind: hasTag("target", "door")},
+			want: &conditions.HasTag{EventRole: entities.EventRoleTarget, Tag: "door"},
+		},
+		{
+			name: "negated",
+			cond: &pb.Condition{Negate: true, Kind: hasTag("target", "door")},
+			want: &conditions.Not{Cond: &conditions.HasTag{EventRole: entities.EventRoleTarget, Tag: "door"}},
+		},
+		{
+			name: "field equals an int",
+			cond: &pb.Condition{Kind: fieldEquals("3", "int")},
+			want: &conditions.FieldEquals{EventRole: entities.EventRoleTarget, Field: "hp", Value: models.VInt(3)},
+		},
+		{
+			name: "field equals a bool",
+			cond: &pb.Condition{Kind: fieldEquals("true", "bool")},
+			want: &conditions.FieldEquals{EventRole: entities.EventRoleTarget, Field: "hp", Value: models.VBool(true)},
+		},
+		{
+			name:    "field equals a value that doesn't decode",
+			cond:    &pb.Condition{Kind: fieldEquals("three", "int")},
+			wantErr: "decode int field",
+		},
+		{
+			name:    "field equals a value of unknown type",
+			cond:    &pb.Condition{Kind: fieldEquals("3.5", "float")},
+			wantErr: `unknown value type: "float"`,
+		},
+		{
+			name: "role present",
+			cond: &pb.Condition{Kind: &pb.Condition_RolePresent{RolePresent: &pb.RolePresentCondition{Role: "instrument"}}},
+			want: &conditions.IsPresent{EventRole: entities.EventRoleInstrument},
+		},
+		{
+			name: "roles equal",
+			cond: &pb.Condition{Kind: &pb.Condition_RolesEqual{RolesEqual: &pb.RolesEqualCondition{Role: "source", Other: "target"}}},
+			want: &conditions.EventRolesEqual{EventRole1: entities.EventRoleSource, EventRole2: entities.EventRoleTarget},
+		},
+		{
+			name:    "roles equal to an unknown role",
+			cond:    &pb.Condition{Kind: &pb.Condition_RolesEqual{RolesEqual: &pb.RolesEqualCondition{Role: "source", Other: "villain"}}},
+			wantErr: "unknown event role 'villain'",
+		},
+		{
+			name: "has child",
+			cond: &pb.Condition{Kind: &pb.Condition_HasChild{HasChild: &pb.HasChildCondition{ChildRole: "target", ParentRole: "source", Component: "Inventory"}}},
+			want: &conditions.HasChild{ParentRole: entities.EventRoleSource, ComponentType: entities.ComponentInventory, ChildRole: entities.EventRoleTarget},
+		},
+		{
+			name:    "has child in an unknown component",
+			cond:    &pb.Condition{Kind: &pb.Condition_HasChild{HasChild: &pb.HasChildCondition{ChildRole: "target", ParentRole: "source", Component: "Pocket"}}},
+			wantErr: "unknown component type 'Pocket'",
+		},
+		{
+			name:    "unknown role",
+			cond:    &pb.Condition{Kind: hasTag("villain", "door")},
+			wantErr: "unknown event role 'villain'",
+		},
+		{
+			name:    "unknown kind",
+			cond:    &pb.Condition{Negate: true},
+			wantErr: "unknown condition kind: <nil>",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := protoConditionToEngineCondition(c.cond)
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestCompileReactions(t *testing.T) {
+	t.Parallel()
+
+	isLit := &pb.Condition{Kind: &pb.Condition_HasTag{HasTag: &pb.HasTagCondition{Role: "target", Tag: "lit"}}}
+	glow := &pb.Action{Kind: &pb.Action_Print{Print: &pb.PrintAction{Role: "source", Message: "It glows."}}}
+	later := func(actions ...*pb.Action) *pb.Action {
+		return &pb.Action{Kind: &pb.Action_After{After: &pb.AfterAction{DelayMs: 1000, Actions: actions}}}
+	}
+
+	type tc struct {
+		name      string
+		reactions []*pb.Reaction
+		want      *components.Eventful
+		wantErr   string
+	}
+
+	cases := []tc{
+		{name: "no reactions"},
+		{
+			name: "rules for each command, in order",
+			reactions: []*pb.Reaction{
+				{Command: "rub", When: []*pb.Condition{isLit}, Then: []*pb.Action{glow}},
+				{Command: "rub", Then: []*pb.Action{glow}},
+				{Command: "shake"},
+			},
+			want: &components.Eventful{Rules: map[string][]*entities.Rule{
+				"rub": {
+					{
+						When: []entities.Condition{&conditions.HasTag{EventRole: entities.EventRoleTarget, Tag: "lit"}},
+						Then: []entities.Action{&actions.Print{Text: "It glows.", EventRole: entities.EventRoleSource}},
+					},
+					{Then: []entities.Action{&actions.Print{Text: "It glows.", EventRole: entities.EventRoleSource}}},
+				},
+				"shake": {{}},
+			}},
+		},
+		{
+			name: "delayed actions are keyed by where they're declared",
+			reactions: []*pb.Reaction{
+				{Command: "shake"},
+				{Command: "rub", Then: []*pb.Action{glow, later(glow, later(glow))}},
+			},
+			want: &components.Eventful{Rules: map[string][]*entities.Rule{
+				"shake": {{}},
+				"rub": {{Then: []entities.Action{
+					&actions.Print{Text: "It glows.", EventRole: entities.EventRoleSource},
+					&actions.ScheduleOnce{
+						Nanoseconds: time.Second,
+						Key:         "plugin:CompiledOrb/1/1",
+						Actions: []entities.Action{
+							&actions.Print{Text: "It glows.", EventRole: entities.EventRoleSource},
+							&actions.ScheduleOnce{
+								Nanoseconds: time.Second,
+								Key:         "plugin:CompiledOrb/1/1/1",
+								Actions:     []entities.Action{&actions.Print{Text: "It glows.", EventRole: entities.EventRoleSource}},
+							},
+						},
+					},
+				}}},
+			}},
+		},
+		{
+			name: "bad condition",
+			reactions: []*pb.Reaction{{
+				Command: "rub",
+				When:    []*pb.Condition{{Kind: &pb.Condition_RolePresent{RolePresent: &pb.RolePresentCondition{Role: "villain"}}}},
+			}},
+			wantErr: "reaction to 'rub': unknown event role 'villain'",
+		},
+		{
+			name:      "bad action",
+			reactions: []*pb.Reaction{{Command: "rub", Then: []*pb.Action{{}}}},
+			wantErr:   "reaction to 'rub': unknown action kind: <nil>",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := compileReactions("CompiledOrb", c.reactions)
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
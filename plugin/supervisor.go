@@ -0,0 +1,368 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"example.com/mud/models"
+	pb "example.com/mud/plugin/proto"
+	"example.com/mud/world/entities"
+)
+
+const (
+	superviseInterval = 500 * time.Millisecond // how often the game is checked on
+	restartBackoff    = time.Second            // first wait between failed restarts
+	maxRestartBackoff = 30 * time.Second
+	manifestTimeout   = 10 * time.Second
+	drainTimeout      = 10 * time.Second // how long a replaced game has to finish its calls
+)
+
+var (
+	errGameDown = errors.New("the game plugin isn't running")
+	errStopped  = errors.New("the game plugin supervisor is stopped")
+)
+
+// game is one run of the game binary.
+type game struct {
+	client GameClient
+	kill   func()
+	exited func() bool
+	calls  sync.WaitGroup // calls in flight
+}
+
+// Supervisor keeps a game plugin running. It relaunches the game binary when
+// it dies, and swaps in a rebuilt one on request, as long as the new game's
+// manifest still declares everything the world was built from. The world's
+// PluginEventfuls hold the supervisor as their client, so a swap reaches all
+// of them at once.
+type Supervisor struct {
+	binaryPath string
+	launch     func(binaryPath string) (*game, error)
+
+	mu       sync.RWMutex
+	current  *game
+	manifest *pb.GameManifest // the current game's
+	world    EngineWorld      // served to every new game, once set by ServeEngine
+	onSwap   []func()
+	report   func(text string)
+
+	swapMu sync.Mutex // one launch at a time
+	stop   chan struct{}
+}
+
+var _ GameClient = &Supervisor{}
+
+// Supervise launches the game binary and keeps it running until Stop is
+// called.
+func Supervise(binaryPath string) (*Supervisor, error) {
+	return supervise(binaryPath, launch)
+}
+
+// supervise is Supervise, starting games with launch.
+func supervise(binaryPath string, launch func(binaryPath string) (*game, error)) (*Supervisor, error) {
+	s := &Supervisor{
+		binaryPath: binaryPath,
+		launch:     launch,
+		report:     func(text string) { fmt.Println(text) },
+		stop:       make(chan struct{}),
+	}
+
+	g, manifest, err := s.start(nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.current, s.manifest = g, manifest
+
+	go s.watch()
+	return s, nil
+}
+
+// Manifest returns the running game's manifest.
+func (s *Supervisor) Manifest() *pb.GameManifest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.manifest
+}
+
+// OnSwap calls fn each time a new game takes over, so anything holding a
+// connection of its own to the old one, like an EventStream, can reopen it.
+func (s *Supervisor) OnSwap(fn func()) {
+	s.mu.Lock()
+	s.onSwap = append(s.onSwap, fn)
+	s.mu.Unlock()
+}
+
+// SetReporter sets where the supervisor says what it's done, which is
+// standard output until it's set.
+func (s *Supervisor) SetReporter(report func(text string)) {
+	s.mu.Lock()
+	s.report = report
+	s.mu.Unlock()
+}
+
+// Reload swaps in the game binary as it is now, after it's been rebuilt, and
+// returns the world built from its manifest, whose entities call the game
+// through client. The new game is launched alongside the old one, which
+// finishes the calls it's handling before it's stopped. If the new game
+// doesn't start, doesn't declare everything the world was built from, or
+// declares a world that can't be built, the old one carries on.
+func (s *Supervisor) Reload(client GameClient) (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+	var entityMap map[string]*entities.Entity
+	var cmds []*models.CommandDefinition
+	err := s.replace(nil, func(manifest *pb.GameManifest) error {
+		var err error
+		entityMap, cmds, err = ManifestToWorld(manifest, client)
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("reload game plugin: %w", err)
+	}
+	return entityMap, cmds, nil
+}
+
+// Stop stops the game, and stops relaunching it.
+func (s *Supervisor) Stop() {
+	s.swapMu.Lock()
+	defer s.swapMu.Unlock()
+
+	select {
+	case <-s.stop:
+		return
+	default:
+	}
+	close(s.stop)
+
+	s.mu.RLock()
+	g := s.current
+	s.mu.RUnlock()
+	g.kill()
+}
+
+func (s *Supervisor) GetManifest(ctx context.Context) (*pb.GameManifest, error) {
+	g := s.acquire()
+	defer g.calls.Done()
+	return g.client.GetManifest(ctx)
+}
+
+func (s *Supervisor) HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.ActionList, error) {
+	g := s.acquire()
+	defer g.calls.Done()
+
+	resp, err := g.client.HandleEvent(ctx, req)
+	if err != nil && g.exited() {
+		return nil, errGameDown
+	}
+	return resp, err
+}
+
+func (s *Supervisor) EventStream(ctx context.Context) (pb.OrbisGame_EventStreamClient, error) {
+	s.mu.RLock()
+	g := s.current
+	s.mu.RUnlock()
+	return g.client.EventStream(ctx)
+}
+
+// ServeEngine serves engine queries to the running game, and to every game
+// that takes over from it.
+func (s *Supervisor) ServeEngine(world EngineWorld) error {
+	s.mu.Lock()
+	s.world = world
+	g := s.current
+	s.mu.Unlock()
+	return g.client.ServeEngine(world)
+}
+
+// acquire returns the current game, counting a call in flight on it.
+func (s *Supervisor) acquire() *game {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.current.calls.Add(1)
+	return s.current
+}
+
+// watch relaunches the game whenever it dies.
+func (s *Supervisor) watch() {
+	ticker := time.NewTicker(superviseInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.RLock()
+		g := s.current
+		s.mu.RUnlock()
+		if g.exited() {
+			s.restart(g)
+		}
+	}
+}
+
+// restart relaunches the game after dead has died, trying again, less and
+// less often, until it's back or the supervisor is stopped.
+func (s *Supervisor) restart(dead *game) {
+	s.say("The game plugin stopped unexpectedly. Restarting it...")
+
+	backoff := restartBackoff
+	for {
+		err := s.replace(dead, s.buildable)
+		if err == nil {
+			s.say("The game plugin is running again.")
+			return
+		}
+		if errors.Is(err, errStopped) {
+			return
+		}
+		s.say(fmt.Sprintf("Restarting the game plugin failed, trying again in %s: %v", backoff, err))
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRestartBackoff)
+	}
+}
+
+// replace launches a new game and swaps it in for the current one, if the
+// current one is still expected, and if build succeeds with the new game's
+// manifest. A nil expected game replaces whatever is running.
+func (s *Supervisor) replace(expected *game, build func(manifest *pb.GameManifest) error) error {
+	s.swapMu.Lock()
+	defer s.swapMu.Unlock()
+
+	select {
+	case <-s.stop:
+		return errStopped
+	default:
+	}
+
+	s.mu.RLock()
+	current, running, world := s.current, s.manifest, s.world
+	s.mu.RUnlock()
+	if expected != nil && current != expected {
+		// someone else got there first
+		return nil
+	}
+
+	g, manifest, err := s.start(running, world, build)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.current, s.manifest = g, manifest
+	hooks := slices.Clone(s.onSwap)
+	s.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+	go retire(current)
+	return nil
+}
+
+// start launches the game binary and gets it ready to take over from a game
+// that's running the world built from running, if there is one. A non-nil
+// build is given the new game's manifest, and fails the start if it fails.
+func (s *Supervisor) start(running *pb.GameManifest, world EngineWorld, build func(manifest *pb.GameManifest) error) (*game, *pb.GameManifest, error) {
+	g, err := s.launch(s.binaryPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), manifestTimeout)
+	defer cancel()
+	manifest, err := g.client.GetManifest(ctx)
+	if err != nil {
+		g.kill()
+		return nil, nil, fmt.Errorf("get game manifest: %w", err)
+	}
+
+	if running != nil {
+		if err := checkCompatible(running, manifest); err != nil {
+			g.kill()
+			return nil, nil, fmt.Errorf("the new game can't take over: %w", err)
+		}
+	}
+	if build != nil {
+		if err := build(manifest); err != nil {
+			g.kill()
+			return nil, nil, fmt.Errorf("build world from manifest: %w", err)
+		}
+	}
+	if world != nil {
+		if err := g.client.ServeEngine(world); err != nil {
+			g.kill()
+			return nil, nil, fmt.Errorf("serve engine queries: %w", err)
+		}
+	}
+	return g, manifest, nil
+}
+
+// buildable checks that a world can be built from manifest, for a game that
+// takes over without the world being rebuilt.
+func (s *Supervisor) buildable(manifest *pb.GameManifest) error {
+	_, _, err := ManifestToWorld(manifest, s)
+	return err
+}
+
+func (s *Supervisor) say(text string) {
+	s.mu.RLock()
+	report := s.report
+	s.mu.RUnlock()
+	report(text)
+}
+
+// retire stops a game that's been replaced, once it's finished the calls it
+// was handling or has had long enough to.
+func retire(g *game) {
+	done := make(chan struct{})
+	go func() {
+		g.calls.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(drainTimeout):
+	}
+	g.kill()
+}
+
+// checkCompatible makes sure next still declares every room and entity that
+// running did, as the same kind of thing, and starts players in the same
+// room, so a game built from next can take over a world built from running.
+func checkCompatible(running, next *pb.GameManifest) error {
+	if running.GetStartingRoom() != next.GetStartingRoom() {
+		return fmt.Errorf("the starting room changed from '%s' to '%s'", running.GetStartingRoom(), next.GetStartingRoom())
+	}
+
+	rooms := make(map[string]bool, len(next.GetRooms()))
+	for _, rd := range next.GetRooms() {
+		rooms[rd.Id] = true
+	}
+	entities := make(map[string]bool, len(next.GetEntities()))
+	for _, ed := range next.GetEntities() {
+		entities[ed.Id] = true
+	}
+
+	for _, rd := range running.GetRooms() {
+		if !rooms[rd.Id] {
+			return fmt.Errorf("room '%s' is no longer declared", rd.Id)
+		}
+	}
+	for _, ed := range running.GetEntities() {
+		if !entities[ed.Id] {
+			return fmt.Errorf("entity '%s' is no longer declared", ed.Id)
+		}
+	}
+	return nil
+}
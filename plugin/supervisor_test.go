@@ -0,0 +1,216 @@
+package plugin
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pb "example.com/mud/plugin/proto"
+)
+
+// testManifest declares a Hall and a Tower, with an Orb in the Hall.
+func testManifest() *pb.GameManifest {
+	return &pb.GameManifest{
+		StartingRoom: "Hall",
+		Rooms: []*pb.RoomDef{
+			{Id: "Hall", Name: "Hall", ChildIds: []string{"Orb"}},
+			{Id: "Tower", Name: "Tower"},
+		},
+		Entities: []*pb.EntityDef{{Id: "Orb", Name: "Orb"}},
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name    string
+		next    func(m *pb.GameManifest)
+		wantErr string
+	}
+
+	cases := []tc{
+		{name: "unchanged", next: func(m *pb.GameManifest) {}},
+		{
+			name: "new rooms and entities",
+			next: func(m *pb.GameManifest) {
+				m.Rooms = append(m.Rooms, &pb.RoomDef{Id: "Cellar"})
+				m.Entities = append(m.Entities, &pb.EntityDef{Id: "Lamp"})
+			},
+		},
+		{name: "starting room moved", next: func(m *pb.GameManifest) { m.StartingRoom = "Tower" }, wantErr: "the starting room changed from 'Hall' to 'Tower'"},
+		{name: "room dropped", next: func(m *pb.GameManifest) { m.Rooms = m.Rooms[:1] }, wantErr: "room 'Tower' is no longer declared"},
+		{name: "entity dropped", next: func(m *pb.GameManifest) { m.Entities = nil }, wantErr: "entity 'Orb' is no longer declared"},
+		{
+			name: "room became an entity",
+			next: func(m *pb.GameManifest) {
+				m.Rooms = m.Rooms[:1]
+				m.Entities = append(m.Entities, &pb.EntityDef{Id: "Tower"})
+			},
+			wantErr: "room 'Tower' is no longer declared",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			next := testManifest()
+			c.next(next)
+			err := checkCompatible(testManifest(), next)
+			if c.wantErr != "" {
+				require.EqualError(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// fakeGame is a game that answers with its own manifest.
+type fakeGame struct {
+	GameClient
+	manifest *pb.GameManifest
+	run      int // which launch it came from
+}
+
+func (g *fakeGame) GetManifest(context.Context) (*pb.GameManifest, error) {
+	return g.manifest, nil
+}
+
+func (g *fakeGame) HandleEvent(context.Context, *pb.EventRequest) (*pb.ActionList, error) {
+	return &pb.ActionList{Actions: make([]*pb.Action, g.run)}, nil
+}
+
+// fakeLauncher launches fakeGames, recording each so tests can crash them.
+type fakeLauncher struct {
+	mu       sync.Mutex
+	manifest *pb.GameManifest // what the next game declares
+	games    []*fakeRun
+}
+
+type fakeRun struct {
+	exited atomic.Bool
+	killed atomic.Bool
+}
+
+func (l *fakeLauncher) launch(string) (*game, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	run := &fakeRun{}
+	l.games = append(l.games, run)
+	return &game{
+		client: &fakeGame{manifest: l.manifest, run: len(l.games)},
+		kill:   func() { run.killed.Store(true) },
+		exited: run.exited.Load,
+	}, nil
+}
+
+func (l *fakeLauncher) run(i int) *fakeRun {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.games[i]
+}
+
+func (l *fakeLauncher) setManifest(m *pb.GameManifest) {
+	l.mu.Lock()
+	l.manifest = m
+	l.mu.Unlock()
+}
+
+// currentRun returns which launch the supervisor's calls go to.
+func currentRun(t *testing.T, s *Supervisor) int {
+	t.Helper()
+
+	list, err := s.HandleEvent(context.Background(), &pb.EventRequest{})
+	require.NoError(t, err)
+	return len(list.GetActions())
+}
+
+func TestSupervisor_RestartAfterExit(t *testing.T) {
+	t.Parallel()
+
+	launcher := &fakeLauncher{manifest: testManifest()}
+	s, err := supervise("game", launcher.launch)
+	require.NoError(t, err)
+	defer s.Stop()
+	reports := make(chan string, 16)
+	s.SetReporter(func(text string) { reports <- text })
+	swapped := make(chan struct{}, 1)
+	s.OnSwap(func() { swapped <- struct{}{} })
+	require.Equal(t, 1, currentRun(t, s))
+
+	launcher.run(0).exited.Store(true)
+	select {
+	case <-swapped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the game was never restarted")
+	}
+	require.Equal(t, 2, currentRun(t, s), "calls go to the new game")
+	require.Equal(t, "The game plugin stopped unexpectedly. Restarting it...", <-reports)
+	require.Equal(t, "The game plugin is running again.", <-reports)
+	require.Eventually(t, launcher.run(0).killed.Load, 5*time.Second, time.Millisecond, "the dead game is cleaned up")
+
+	s.Stop()
+	require.True(t, launcher.run(1).killed.Load(), "stopping stops the game")
+}
+
+func TestSupervisor_Reload(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name     string
+		manifest func(m *pb.GameManifest)
+		wantErr  string
+	}
+
+	cases := []tc{
+		{
+			name:     "rebuilt game takes over",
+			manifest: func(m *pb.GameManifest) { m.Rooms = append(m.Rooms, &pb.RoomDef{Id: "Cellar"}) },
+		},
+		{
+			name:     "incompatible game",
+			manifest: func(m *pb.GameManifest) { m.StartingRoom = "Tower" },
+			wantErr:  "the new game can't take over",
+		},
+		{
+			name:     "world can't be built",
+			manifest: func(m *pb.GameManifest) { m.Rooms[1].ChildIds = []string{"Ghost"} },
+			wantErr:  `build world from manifest: room "Tower" references unknown child "Ghost"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			launcher := &fakeLauncher{manifest: testManifest()}
+			s, err := supervise("game", launcher.launch)
+			require.NoError(t, err)
+			defer s.Stop()
+
+			next := testManifest()
+			c.manifest(next)
+			launcher.setManifest(next)
+			entityMap, _, err := s.Reload(s)
+			if c.wantErr != "" {
+				require.ErrorContains(t, err, c.wantErr)
+				require.Equal(t, 1, currentRun(t, s), "the old game carries on")
+				require.True(t, launcher.run(1).killed.Load(), "the new game is stopped")
+				require.Equal(t, "Hall", s.Manifest().GetStartingRoom())
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, entityMap, "Cellar", "the world is built from the new game")
+			require.Equal(t, 2, currentRun(t, s))
+			require.Same(t, next, s.Manifest())
+			require.Eventually(t, launcher.run(0).killed.Load, 5*time.Second, time.Millisecond, "the old game is retired")
+		})
+	}
+}
@@ -70,24 +70,24 @@ func SetField(role, field string, value interface{}) Action {
 }
 
 func (a *setFieldAction) toProto() *pb.Action {
-	var val, valType string
-	switch v := a.value.(type) {
+	val, valType := encodeValue(a.value)
+	return &pb.Action{Kind: &pb.Action_SetField{SetField: &pb.SetFieldAction{
+		Role: a.role, Field: a.field, Value: val, ValueType: valType,
+	}}}
+}
+
+// encodeValue encodes a field value and its type for the wire.
+func encodeValue(value interface{}) (val, valType string) {
+	switch v := value.(type) {
 	case int:
-		val = strconv.Itoa(v)
-		valType = "int"
+		return strconv.Itoa(v), "int"
 	case bool:
-		val = strconv.FormatBool(v)
-		valType = "bool"
+		return strconv.FormatBool(v), "bool"
 	case string:
-		val = v
-		valType = "string"
+		return v, "string"
 	default:
-		val = fmt.Sprintf("%v", v)
-		valType = "string"
+		return fmt.Sprintf("%v", v), "string"
 	}
-	return &pb.Action{Kind: &pb.Action_SetField{SetField: &pb.SetFieldAction{
-		Role: a.role, Field: a.field, Value: val, ValueType: valType,
-	}}}
 }
 
 // ── Destroy ──────────────────────────────────────────────────────────────────
@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"context"
 	"strconv"
 
 	pb "example.com/mud/plugin/proto"
@@ -15,10 +16,16 @@ type Event struct {
 	Instrument *EntitySnapshot
 	Room       *EntitySnapshot
 	Message    string
+
+	// World queries the engine for state not included in the snapshots
+	// above. It is only valid until HandleEvent returns.
+	World World
 }
 
-// EntitySnapshot is a read-only view of an entity's current state.
+// EntitySnapshot is a read-only view of an entity's current state. ID is
+// unique to this instance; copies of the same definition share a TemplateID.
 type EntitySnapshot struct {
+	ID          string
 	TemplateID  string
 	Name        string
 	Description string
@@ -29,6 +36,7 @@ type EntitySnapshot struct {
 }
 
 type ChildRef struct {
+	ID         string
 	TemplateID string
 	Name       string
 	Tags       []string
@@ -80,7 +88,20 @@ func (s *EntitySnapshot) HasChildInComponent(templateID, component string) bool
 	return false
 }
 
-func eventFromProto(req *pb.EventRequest) *Event {
+// HasChildIDInComponent returns true if the entity with the given instance ID is a child in the given component.
+func (s *EntitySnapshot) HasChildIDInComponent(id, component string) bool {
+	if s == nil {
+		return false
+	}
+	for _, c := range s.Children {
+		if c.ID == id && c.Component == component {
+			return true
+		}
+	}
+	return false
+}
+
+func eventFromProto(ctx context.Context, req *pb.EventRequest) *Event {
 	return &Event{
 		Command:    req.Command,
 		TargetID:   req.TargetId,
@@ -89,6 +110,7 @@ func eventFromProto(req *pb.EventRequest) *Event {
 		Instrument: snapshotFromProto(req.Instrument),
 		Room:       snapshotFromProto(req.Room),
 		Message:    req.Message,
+		World:      worldFromContext(ctx),
 	}
 }
 
@@ -100,6 +122,7 @@ func snapshotFromProto(s *pb.EntitySnapshot) *EntitySnapshot {
 	children := make([]*ChildRef, 0, len(s.Children))
 	for _, c := range s.Children {
 		children = append(children, &ChildRef{
+			ID:         c.Id,
 			TemplateID: c.TemplateId,
 			Name:       c.Name,
 			Tags:       c.Tags,
@@ -108,6 +131,7 @@ func snapshotFromProto(s *pb.EntitySnapshot) *EntitySnapshot {
 	}
 
 	return &EntitySnapshot{
+		ID:          s.Id,
 		TemplateID:  s.TemplateId,
 		Name:        s.Name,
 		Description: s.Description,
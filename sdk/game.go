@@ -7,7 +7,8 @@ import (
 	pb "example.com/mud/plugin/proto"
 )
 
-// Game is the interface implemented by a game binary.
+// Game is the interface implemented by a game binary. A Game may also
+// implement UpdateHandler and EmitterReceiver to act on its own.
 type Game interface {
 	GetManifest() *Manifest
 	HandleEvent(e *Event) []Action
@@ -28,7 +29,7 @@ func (a *Adapter) GetManifest(ctx context.Context, _ *pb.Empty) (*pb.GameManifes
 }
 
 func (a *Adapter) HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.ActionList, error) {
-	ev := eventFromProto(req)
+	ev := eventFromProto(ctx, req)
 	acts := a.impl.HandleEvent(ev)
 	protoActs := make([]*pb.Action, 0, len(acts))
 	for _, act := range acts {
@@ -38,10 +39,33 @@ func (a *Adapter) HandleEvent(ctx context.Context, req *pb.EventRequest) (*pb.Ac
 }
 
 func (a *Adapter) EventStream(stream pb.OrbisGame_EventStreamServer) error {
-	// Consume and ignore engine updates for now
+	emitter := &streamEmitter{stream: stream}
+	if r, ok := a.impl.(EmitterReceiver); ok {
+		r.SetEmitter(emitter)
+	}
+	handler, _ := a.impl.(UpdateHandler)
+
 	for {
-		if _, err := stream.Recv(); err != nil {
+		msg, err := stream.Recv()
+		if err != nil {
 			return nil
 		}
+		if handler == nil {
+			continue
+		}
+
+		u := updateFromProto(msg)
+		acts := handler.OnEngineUpdate(u)
+		if len(acts) == 0 {
+			continue
+		}
+
+		scope := Scope{RoomID: u.RoomID}
+		if u.Entity != nil {
+			scope.SourceID = u.Entity.ID
+		}
+		if err := emitter.Emit(scope, acts...); err != nil {
+			return err
+		}
 	}
 }
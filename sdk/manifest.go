@@ -35,7 +35,8 @@ type EntityDef struct {
 	HasContainer       bool
 	ContainerPrefix    string
 	ContainerRevealed  bool
-	Reactions          map[Command][]Action
+	Reactions          map[Command][]Action // always run for the command, after any Rules
+	Rules              []*Rule
 }
 
 type CommandDef struct {
@@ -100,6 +101,7 @@ func (e *EntityDef) toProto() *pb.EntityDef {
 		HasContainer:       e.HasContainer,
 		ContainerPrefix:    e.ContainerPrefix,
 		ContainerRevealed:  e.ContainerRevealed,
+		Reactions:          reactionsToProto(e.Rules, e.Reactions),
 	}
 }
 
@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"sort"
+
+	pb "example.com/mud/plugin/proto"
+)
+
+// Rule is a declarative reaction to a command, guarded by conditions. Rules
+// are sent with the manifest and run by the engine itself, so they cost no
+// round trip to the game. An entity's rules for a command are tried in order
+// and the first whose conditions all hold is run; if none hold, the event is
+// sent to HandleEvent as usual.
+type Rule struct {
+	Command Command
+	When    []Condition
+	Then    []Action
+}
+
+// Condition is a simple check the engine can evaluate without the game.
+type Condition interface {
+	toProto() *pb.Condition
+}
+
+// ── HasTag ───────────────────────────────────────────────────────────────────
+
+type hasTagCondition struct{ role, tag string }
+
+// HasTag holds when the entity in role has the given tag.
+func HasTag(role, tag string) Condition { return &hasTagCondition{role, tag} }
+
+func (c *hasTagCondition) toProto() *pb.Condition {
+	return &pb.Condition{Kind: &pb.Condition_HasTag{HasTag: &pb.HasTagCondition{Role: c.role, Tag: c.tag}}}
+}
+
+// ── FieldEquals ──────────────────────────────────────────────────────────────
+
+type fieldEqualsCondition struct {
+	role, field string
+	value       interface{}
+}
+
+// FieldEquals holds when the entity in role has field set to value.
+func FieldEquals(role, field string, value interface{}) Condition {
+	return &fieldEqualsCondition{role, field, value}
+}
+
+func (c *fieldEqualsCondition) toProto() *pb.Condition {
+	val, valType := encodeValue(c.value)
+	return &pb.Condition{Kind: &pb.Condition_FieldEquals{FieldEquals: &pb.FieldEqualsCondition{
+		Role: c.role, Field: c.field, Value: val, ValueType: valType,
+	}}}
+}
+
+// ── RolePresent ──────────────────────────────────────────────────────────────
+
+type rolePresentCondition struct{ role string }
+
+// RolePresent holds when the event has an entity in role, e.g. an instrument.
+func RolePresent(role string) Condition { return &rolePresentCondition{role} }
+
+func (c *rolePresentCondition) toProto() *pb.Condition {
+	return &pb.Condition{Kind: &pb.Condition_RolePresent{RolePresent: &pb.RolePresentCondition{Role: c.role}}}
+}
+
+// ── RolesEqual ───────────────────────────────────────────────────────────────
+
+type rolesEqualCondition struct{ role, other string }
+
+// RolesEqual holds when both roles are the same entity, e.g. when something
+// is used on itself.
+func RolesEqual(role, other string) Condition { return &rolesEqualCondition{role, other} }
+
+func (c *rolesEqualCondition) toProto() *pb.Condition {
+	return &pb.Condition{Kind: &pb.Condition_RolesEqual{RolesEqual: &pb.RolesEqualCondition{Role: c.role, Other: c.other}}}
+}
+
+// ── HasChild ─────────────────────────────────────────────────────────────────
+
+type hasChildCondition struct{ childRole, parentRole, component string }
+
+// HasChild holds when the entity in childRole is in the given component of
+// the entity in parentRole, e.g. HasChild("target", "source", "Inventory").
+func HasChild(childRole, parentRole, component string) Condition {
+	return &hasChildCondition{childRole, parentRole, component}
+}
+
+func (c *hasChildCondition) toProto() *pb.Condition {
+	return &pb.Condition{Kind: &pb.Condition_HasChild{HasChild: &pb.HasChildCondition{
+		ChildRole: c.childRole, ParentRole: c.parentRole, Component: c.component,
+	}}}
+}
+
+// ── Not ──────────────────────────────────────────────────────────────────────
+
+type notCondition struct{ cond Condition }
+
+// Not inverts a condition.
+func Not(cond Condition) Condition { return &notCondition{cond} }
+
+func (c *notCondition) toProto() *pb.Condition {
+	p := c.cond.toProto()
+	p.Negate = !p.Negate
+	return p
+}
+
+// reactionsToProto serializes an entity's rules followed by its unconditional
+// reactions, which always match and so come last.
+func reactionsToProto(rules []*Rule, reactions map[Command][]Action) []*pb.Reaction {
+	out := make([]*pb.Reaction, 0, len(rules)+len(reactions))
+	for _, r := range rules {
+		out = append(out, reactionToProto(r.Command, r.When, r.Then))
+	}
+
+	commands := make([]Command, 0, len(reactions))
+	for cmd := range reactions {
+		commands = append(commands, cmd)
+	}
+	sort.Strings(commands)
+	for _, cmd := range commands {
+		out = append(out, reactionToProto(cmd, nil, reactions[cmd]))
+	}
+
+	return out
+}
+
+func reactionToProto(cmd Command, when []Condition, then []Action) *pb.Reaction {
+	r := &pb.Reaction{Command: cmd}
+	for _, c := range when {
+		r.When = append(r.When, c.toProto())
+	}
+	for _, a := range then {
+		r.Then = append(r.Then, a.toProto())
+	}
+	return r
+}
@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"fmt"
+	"sync"
+
+	pb "example.com/mud/plugin/proto"
+)
+
+// UpdateKind identifies what happened in an EngineUpdate.
+type UpdateKind int
+
+const (
+	UpdateUnknown UpdateKind = iota
+	UpdatePlayerJoined
+	UpdatePlayerLeft
+	UpdateEntityMoved
+	UpdateTick
+)
+
+// EngineUpdate is a notification streamed from the engine to the game.
+type EngineUpdate struct {
+	Kind UpdateKind
+
+	// Entity is the player who joined or left, or the entity that moved.
+	Entity *EntitySnapshot
+	// RoomID is the room joined, left, or moved into.
+	RoomID string
+	// FromRoomID is the room moved out of, for UpdateEntityMoved.
+	FromRoomID string
+
+	// Tick is the tick sequence number, for UpdateTick.
+	Tick int64
+}
+
+// UpdateHandler is an optional interface a Game can implement to receive
+// engine updates. Returned actions run with the update's room in the room
+// role and its entity, if any, in the source role.
+type UpdateHandler interface {
+	OnEngineUpdate(u *EngineUpdate) []Action
+}
+
+// Scope names the entities that fill the event roles for emitted actions, by
+// instance ID, template ID, or player name.
+type Scope struct {
+	RoomID   string
+	SourceID string
+	TargetID string
+}
+
+// Emitter sends actions to the engine at any time, outside of any event.
+type Emitter interface {
+	Emit(scope Scope, acts ...Action) error
+}
+
+// EmitterReceiver is an optional interface a Game can implement to be handed
+// an Emitter once the engine opens the event stream.
+type EmitterReceiver interface {
+	SetEmitter(e Emitter)
+}
+
+// streamEmitter serialises sends on the event stream, since gRPC streams
+// do not allow concurrent senders.
+type streamEmitter struct {
+	mu     sync.Mutex
+	stream pb.OrbisGame_EventStreamServer
+}
+
+func (s *streamEmitter) Emit(scope Scope, acts ...Action) error {
+	protoActs := make([]*pb.Action, 0, len(acts))
+	for _, act := range acts {
+		protoActs = append(protoActs, act.toProto())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.stream.Send(&pb.ActionList{
+		Actions: protoActs,
+		Scope: &pb.ActionScope{
+			RoomId:   scope.RoomID,
+			SourceId: scope.SourceID,
+			TargetId: scope.TargetID,
+		},
+	}); err != nil {
+		return fmt.Errorf("emit actions: %w", err)
+	}
+	return nil
+}
+
+func updateFromProto(u *pb.EngineUpdate) *EngineUpdate {
+	switch kind := u.Kind.(type) {
+	case *pb.EngineUpdate_PlayerJoined:
+		return &EngineUpdate{
+			Kind:   UpdatePlayerJoined,
+			Entity: snapshotFromProto(kind.PlayerJoined.Player),
+			RoomID: kind.PlayerJoined.RoomId,
+		}
+	case *pb.EngineUpdate_PlayerLeft:
+		return &EngineUpdate{
+			Kind:   UpdatePlayerLeft,
+			Entity: snapshotFromProto(kind.PlayerLeft.Player),
+			RoomID: kind.PlayerLeft.RoomId,
+		}
+	case *pb.EngineUpdate_EntityMoved:
+		return &EngineUpdate{
+			Kind:       UpdateEntityMoved,
+			Entity:     snapshotFromProto(kind.EntityMoved.Entity),
+			RoomID:     kind.EntityMoved.ToRoomId,
+			FromRoomID: kind.EntityMoved.FromRoomId,
+		}
+	case *pb.EngineUpdate_Tick:
+		return &EngineUpdate{
+			Kind: UpdateTick,
+			Tick: kind.Tick.Sequence,
+		}
+	}
+	return &EngineUpdate{Kind: UpdateUnknown}
+}
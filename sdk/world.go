@@ -0,0 +1,127 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"example.com/mud/plugin"
+	pb "example.com/mud/plugin/proto"
+)
+
+// ErrWorldUnavailable is returned by World queries when the engine did not
+// offer a query service for the event being handled.
+var ErrWorldUnavailable = errors.New("engine world queries are unavailable")
+
+// World lets a game look up engine state beyond the snapshots in an Event.
+// Entities are addressed by instance ID, template ID, or name for players.
+type World interface {
+	GetEntity(id string) (*EntitySnapshot, error)
+	// ListChildren returns the children of an entity. An empty component
+	// lists the children of every component.
+	ListChildren(id, component string) ([]*EntitySnapshot, error)
+	ListPlayers() ([]*PlayerInfo, error)
+	FindRoom(templateID string) (*EntitySnapshot, error)
+	ResolveExits(roomID string) ([]*Exit, error)
+}
+
+// PlayerInfo describes an online player, the room they are in, and how
+// they're connected.
+type PlayerInfo struct {
+	Player     *EntitySnapshot
+	RoomID     string
+	Connection string // protocol, empty while link-dead
+	Idle       time.Duration
+}
+
+// Exit is a resolved room exit.
+type Exit struct {
+	Direction string
+	RoomID    string
+	RoomName  string
+}
+
+// engineWorld answers World queries over the engine's query service.
+type engineWorld struct {
+	ctx    context.Context
+	client pb.OrbisEngineClient
+}
+
+func worldFromContext(ctx context.Context) World {
+	client, ok := plugin.EngineFromContext(ctx)
+	if !ok {
+		return unavailableWorld{}
+	}
+	return &engineWorld{ctx: ctx, client: client}
+}
+
+func (w *engineWorld) GetEntity(id string) (*EntitySnapshot, error) {
+	s, err := w.client.GetEntity(w.ctx, &pb.EntityQuery{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return snapshotFromProto(s), nil
+}
+
+func (w *engineWorld) ListChildren(id, component string) ([]*EntitySnapshot, error) {
+	list, err := w.client.ListChildren(w.ctx, &pb.ChildrenQuery{Id: id, Component: component})
+	if err != nil {
+		return nil, err
+	}
+	children := make([]*EntitySnapshot, 0, len(list.Entities))
+	for _, e := range list.Entities {
+		children = append(children, snapshotFromProto(e))
+	}
+	return children, nil
+}
+
+func (w *engineWorld) ListPlayers() ([]*PlayerInfo, error) {
+	list, err := w.client.ListPlayers(w.ctx, &pb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	players := make([]*PlayerInfo, 0, len(list.Players))
+	for _, p := range list.Players {
+		players = append(players, &PlayerInfo{
+			Player:     snapshotFromProto(p.Player),
+			RoomID:     p.RoomId,
+			Connection: p.Connection,
+			Idle:       time.Duration(p.IdleMs) * time.Millisecond,
+		})
+	}
+	return players, nil
+}
+
+func (w *engineWorld) FindRoom(templateID string) (*EntitySnapshot, error) {
+	s, err := w.client.FindRoom(w.ctx, &pb.RoomQuery{TemplateId: templateID})
+	if err != nil {
+		return nil, err
+	}
+	return snapshotFromProto(s), nil
+}
+
+func (w *engineWorld) ResolveExits(roomID string) ([]*Exit, error) {
+	list, err := w.client.ResolveExits(w.ctx, &pb.ExitsQuery{RoomId: roomID})
+	if err != nil {
+		return nil, err
+	}
+	exits := make([]*Exit, 0, len(list.Exits))
+	for _, e := range list.Exits {
+		exits = append(exits, &Exit{
+			Direction: e.Direction,
+			RoomID:    e.RoomId,
+			RoomName:  e.RoomName,
+		})
+	}
+	return exits, nil
+}
+
+type unavailableWorld struct{}
+
+func (unavailableWorld) GetEntity(string) (*EntitySnapshot, error) { return nil, ErrWorldUnavailable }
+func (unavailableWorld) ListChildren(string, string) ([]*EntitySnapshot, error) {
+	return nil, ErrWorldUnavailable
+}
+func (unavailableWorld) ListPlayers() ([]*PlayerInfo, error)      { return nil, ErrWorldUnavailable }
+func (unavailableWorld) FindRoom(string) (*EntitySnapshot, error) { return nil, ErrWorldUnavailable }
+func (unavailableWorld) ResolveExits(string) ([]*Exit, error)     { return nil, ErrWorldUnavailable }
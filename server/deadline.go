@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// writeTimeout is how long one write to a player may take. A client that
+// stops reading would otherwise hold up whatever is writing to it forever;
+// instead its connection is closed, and the player goes link-dead.
+const writeTimeout = 10 * time.Second
+
+// deadlineConn gives every write to a connection writeTimeout to finish,
+// closing the connection if one fails.
+type deadlineConn struct {
+	net.Conn
+}
+
+func (c deadlineConn) Write(p []byte) (int, error) {
+	if err := c.Conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return 0, err
+	}
+	n, err := c.Conn.Write(p)
+	if err != nil {
+		_ = c.Conn.Close()
+	}
+	return n, err
+}
+
+// deadlineChannel does the same for an SSH channel. Channels have no
+// deadlines, and a write can also wait on the client's window, so a write
+// that takes too long closes the whole connection, which ends the write.
+type deadlineChannel struct {
+	ssh.Channel
+	conn ssh.Conn
+}
+
+func (c deadlineChannel) Write(p []byte) (int, error) {
+	stalled := time.AfterFunc(writeTimeout, func() { _ = c.conn.Close() })
+	defer stalled.Stop()
+	return c.Channel.Write(p)
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+
+	"example.com/mud/telnet"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// GMCP packages sent to telnet clients. The names follow the IRE modules
+// that Mudlet and most other clients already understand.
+const (
+	GMCPRoomInfo    = "Room.Info"
+	GMCPRoomMap     = "Room.Map"
+	GMCPCharItemInv = "Char.Items.Inv"
+	GMCPChannelText = "Comm.Channel.Text"
+)
+
+// gmcpRoomInfo is the Room.Info payload. Mappers want numbers for rooms, so
+// num is derived from the room ID, which is sent alongside.
+type gmcpRoomInfo struct {
+	Num   uint32            `json:"num"`
+	ID    string            `json:"id"`
+	Name  string            `json:"name"`
+	Desc  string            `json:"desc"`
+	Exits map[string]uint32 `json:"exits"`
+}
+
+type gmcpChannelText struct {
+	Channel string `json:"channel"`
+	Text    string `json:"text"`
+}
+
+func roomNum(id string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return h.Sum32()
+}
+
+// GMCPForTelnet converts a Response to the GMCP messages that carry the same
+// data. Responses without a GMCP equivalent give none.
+func GMCPForTelnet(r response.Response) []telnet.GMCPMessage {
+	switch v := r.(type) {
+	case response.RoomDescription:
+		info := gmcpRoomInfo{
+			Num:   roomNum(v.ID),
+			ID:    v.ID,
+			Name:  v.Name,
+			Desc:  v.Description,
+			Exits: make(map[string]uint32, len(v.ExitIDs)),
+		}
+		for dir, id := range v.ExitIDs {
+			info.Exits[dir] = roomNum(id)
+		}
+		return []telnet.GMCPMessage{{Package: GMCPRoomInfo, Data: info}}
+
+	case response.InventoryList:
+		return []telnet.GMCPMessage{{Package: GMCPCharItemInv, Data: v}}
+
+	case response.MapView:
+		return []telnet.GMCPMessage{{Package: GMCPRoomMap, Data: v}}
+
+	default:
+		return nil
+	}
+}
+
+// GMCPChannel wraps a message heard on a channel. Text is sent without ANSI
+// codes, since clients show it in their own windows.
+func GMCPChannel(channel, text string) telnet.GMCPMessage {
+	return telnet.GMCPMessage{
+		Package: GMCPChannelText,
+		Data:    gmcpChannelText{Channel: channel, Text: telnet.StripANSI(text)},
+	}
+}
+
+// GMCPForMessage wraps a bus message with text as Comm.Channel.Text.
+// Narrative is heard on the "room" channel, and system messages on "system".
+func GMCPForMessage(msg response.Message) telnet.GMCPMessage {
+	channel := msg.Channel
+	switch msg.Kind {
+	case response.MessageNarrative:
+		channel = "room"
+	case response.MessageSystem:
+		channel = "system"
+	}
+	return GMCPChannel(channel, msg.Text)
+}
+
+// GMCPState gathers p's room and map (response.PanelRoom) and inventory
+// (response.PanelInventory), or all three when no panels are given, so
+// clients stay in sync after changes nobody said anything about.
+func GMCPState(p *player.Player, panels ...string) ([]telnet.GMCPMessage, error) {
+	all := len(panels) == 0
+	var rs []response.Response
+
+	if all || slices.Contains(panels, response.PanelRoom) {
+		room, err := p.GetRoomDescription()
+		if err != nil {
+			return nil, fmt.Errorf("gmcp room for player '%s': %w", p.Name, err)
+		}
+		m, err := p.Map()
+		if err != nil {
+			return nil, fmt.Errorf("gmcp map for player '%s': %w", p.Name, err)
+		}
+		rs = append(rs, room, m)
+	}
+	if all || slices.Contains(panels, response.PanelInventory) {
+		inv, err := p.Inventory()
+		if err != nil {
+			return nil, fmt.Errorf("gmcp inventory for player '%s': %w", p.Name, err)
+		}
+		rs = append(rs, inv)
+	}
+
+	var msgs []telnet.GMCPMessage
+	for _, r := range rs {
+		msgs = append(msgs, GMCPForTelnet(r)...)
+	}
+	return msgs, nil
+}
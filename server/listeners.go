@@ -0,0 +1,147 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"golang.org/x/crypto/ssh"
+
+	"example.com/mud/account"
+	"example.com/mud/config"
+	"example.com/mud/session"
+)
+
+// Server accepts players on every configured listener.
+type Server struct {
+	cfg      *config.Config
+	sessions *session.Manager
+	accounts *account.Manager
+
+	certs  map[certPair]*CertReloader // shared by listeners using the same files
+	sshCfg *ssh.ServerConfig
+}
+
+// certPair is the files a TLS listener's certificate is loaded from.
+type certPair struct {
+	certFile, keyFile string
+}
+
+func New(cfg *config.Config, sessions *session.Manager, accounts *account.Manager) *Server {
+	return &Server{
+		cfg:      cfg,
+		sessions: sessions,
+		accounts: accounts,
+		certs:    make(map[certPair]*CertReloader),
+	}
+}
+
+// Start binds every listener and serves each in its own goroutine. If any
+// can't be bound, none are served.
+func (s *Server) Start() error {
+	type bound struct {
+		l     config.Listener
+		inner net.Listener
+	}
+	var listeners []bound
+	closeAll := func() {
+		for _, b := range listeners {
+			_ = b.inner.Close()
+		}
+	}
+
+	for _, l := range s.cfg.Listeners {
+		inner, err := s.listen(l)
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("%s listener on %s: %w", l.Protocol, l.Address, err)
+		}
+		listeners = append(listeners, bound{l, inner})
+	}
+
+	for _, b := range listeners {
+		fmt.Printf("%s listening on %s...\n", b.l.Protocol, b.l.Address)
+		go s.serve(b.l, b.inner)
+	}
+	return nil
+}
+
+func (s *Server) listen(l config.Listener) (net.Listener, error) {
+	if l.Protocol == config.ProtocolSSH && s.sshCfg == nil {
+		sshCfg, err := NewSSHConfig(s.cfg.SSH.HostKey, s.accounts)
+		if err != nil {
+			return nil, err
+		}
+		s.sshCfg = sshCfg
+	}
+
+	inner, err := net.Listen("tcp", l.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !l.TLS() {
+		return inner, nil
+	}
+
+	pair := certPair{l.CertFile, l.KeyFile}
+	certs, ok := s.certs[pair]
+	if !ok {
+		certs, err = NewCertReloader(l.CertFile, l.KeyFile)
+		if err != nil {
+			_ = inner.Close()
+			return nil, err
+		}
+		s.certs[pair] = certs
+	}
+	return tls.NewListener(inner, certs.TLSConfig()), nil
+}
+
+func (s *Server) serve(l config.Listener, listener net.Listener) {
+	switch l.Protocol {
+	case config.ProtocolWS, config.ProtocolWSS:
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+			HandleWS(w, r, s.sessions)
+		})
+		err := http.Serve(listener, mux)
+		fmt.Printf("%s listener on %s stopped: %v\n", l.Protocol, l.Address, err)
+
+	case config.ProtocolTelnet, config.ProtocolTelnetTLS:
+		s.accept(l, listener, func(conn net.Conn) {
+			HandleTelnet(conn, s.sessions)
+		})
+
+	case config.ProtocolSSH:
+		s.accept(l, listener, func(conn net.Conn) {
+			HandleSSH(conn, s.sshCfg, s.sessions, s.accounts)
+		})
+	}
+}
+
+func (s *Server) accept(l config.Listener, listener net.Listener, handle func(net.Conn)) {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error accepting %s connection: %v\n", l.Protocol, err)
+			continue
+		}
+		go handle(conn)
+	}
+}
+
+// ReloadCerts reads every TLS listener's certificate and key again. Listeners
+// whose files fail to load keep their current certificate.
+func (s *Server) ReloadCerts() error {
+	var errs []error
+	for _, certs := range s.certs {
+		if err := certs.Reload(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
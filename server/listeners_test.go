@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/config"
+)
+
+// freeAddress returns a local address nothing is listening on.
+func freeAddress(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+func TestServer_Start(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeCert(t, dir, "mud.example.com")
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	keyCopy := filepath.Join(dir, "key-copy.pem")
+	key, err := os.ReadFile(keyFile)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyCopy, key, 0o600))
+
+	s := New(&config.Config{Listeners: []config.Listener{
+		{Protocol: config.ProtocolTelnet, Address: "127.0.0.1:0"},
+		{Protocol: config.ProtocolTelnetTLS, Address: "127.0.0.1:0", CertFile: certFile, KeyFile: keyFile},
+		{Protocol: config.ProtocolWSS, Address: "127.0.0.1:0", CertFile: certFile, KeyFile: keyFile},
+		{Protocol: config.ProtocolWSS, Address: "127.0.0.1:0", CertFile: certFile, KeyFile: keyCopy},
+	}}, nil, nil)
+	require.NoError(t, s.Start())
+	require.Len(t, s.certs, 2, "listeners share a certificate only when they share both files")
+	require.NoError(t, s.ReloadCerts())
+
+	// a listener that can't be bound stops the rest from being served
+	first := freeAddress(t)
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+	s = New(&config.Config{Listeners: []config.Listener{
+		{Protocol: config.ProtocolTelnet, Address: first},
+		{Protocol: config.ProtocolTelnet, Address: taken.Addr().String()},
+	}}, nil, nil)
+	require.ErrorContains(t, s.Start(), "telnet listener on "+taken.Addr().String())
+	again, err := net.Listen("tcp", first)
+	require.NoError(t, err, "listeners bound before the failure are closed")
+	require.NoError(t, again.Close())
+
+	s = New(&config.Config{Listeners: []config.Listener{
+		{Protocol: config.ProtocolWSS, Address: "127.0.0.1:0", CertFile: certFile, KeyFile: filepath.Join(dir, "missing.pem")},
+	}}, nil, nil)
+	require.Error(t, s.Start(), "missing key")
+}
@@ -0,0 +1,259 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+
+	"example.com/mud/account"
+	"example.com/mud/config"
+	"example.com/mud/session"
+	"example.com/mud/telnet"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// accountExtension carries the authenticated account's name from the SSH
+// handshake to the session.
+const accountExtension = "orbis-account"
+
+// NewSSHConfig loads the host key, generating it on first use, and
+// authenticates players by account password or registered public key. User
+// names that aren't valid player names are turned away before any lookup.
+// The callbacks only check; HandleSSH records the login once the handshake
+// is over.
+func NewSSHConfig(hostKeyPath string, accounts *account.Manager) (*ssh.ServerConfig, error) {
+	hostKey, err := loadHostKey(hostKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	permit := func(a *account.Account) *ssh.Permissions {
+		return &ssh.Permissions{Extensions: map[string]string{accountExtension: a.Name}}
+	}
+
+	sshCfg := &ssh.ServerConfig{
+		MaxAuthTries:  3,
+		ServerVersion: "SSH-2.0-Orbis",
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if player.NameValidation(c.User()) != "" {
+				return nil, account.ErrBadCredentials
+			}
+			a, err := accounts.CheckPassword(c.User(), string(password))
+			if err != nil {
+				return nil, err
+			}
+			return permit(a), nil
+		},
+		PublicKeyCallback: func(c ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if player.NameValidation(c.User()) != "" {
+				return nil, account.ErrBadCredentials
+			}
+			a, err := accounts.CheckKey(c.User(), key)
+			if err != nil {
+				return nil, err
+			}
+			return permit(a), nil
+		},
+	}
+	sshCfg.AddHostKey(hostKey)
+	return sshCfg, nil
+}
+
+func loadHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("generate SSH host key: %w", err)
+		}
+		block, err := ssh.MarshalPrivateKey(private, "orbis host key")
+		if err != nil {
+			return nil, fmt.Errorf("generate SSH host key: %w", err)
+		}
+		data = pem.EncodeToMemory(block)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return nil, fmt.Errorf("save SSH host key: %w", err)
+		}
+		fmt.Println("Generated SSH host key", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("read SSH host key: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse SSH host key '%s': %w", path, err)
+	}
+	return signer, nil
+}
+
+// HandleSSH runs the SSH handshake and the session of the first shell the
+// client opens.
+func HandleSSH(raw net.Conn, sshCfg *ssh.ServerConfig, sessions *session.Manager, accounts *account.Manager) {
+	conn, channels, requests, err := ssh.NewServerConn(deadlineConn{raw}, sshCfg)
+	if err != nil {
+		fmt.Println("ssh handshake failed:", err)
+		_ = raw.Close()
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(requests)
+
+	name := conn.Permissions.Extensions[accountExtension]
+	if err := accounts.RecordLogin(name); err != nil {
+		fmt.Println("ssh login:", err)
+	}
+	var once sync.Once
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			fmt.Println("ssh channel failed:", err)
+			continue
+		}
+
+		t := &sshTransport{channel: channel, name: name, opts: telnet.Options{Color: true}}
+		t.term = term.NewTerminal(deadlineChannel{channel, conn}, "")
+		go t.serveRequests(requests, func() {
+			once.Do(func() {
+				sessions.Run(t)
+				_ = conn.Close()
+			})
+		})
+	}
+}
+
+// sshTransport renders responses as text, like telnet, through a terminal
+// that does the line editing and echo an SSH client leaves to the server.
+type sshTransport struct {
+	channel ssh.Channel
+	term    *term.Terminal
+	name    string
+
+	mu   sync.Mutex
+	opts telnet.Options
+}
+
+// ptyRequest and windowChange are the payloads from RFC 4254 section 6.
+type ptyRequest struct {
+	Term    string
+	Columns uint32
+	Rows    uint32
+	Width   uint32
+	Height  uint32
+	Modes   string
+}
+
+type windowChange struct {
+	Columns uint32
+	Rows    uint32
+	Width   uint32
+	Height  uint32
+}
+
+// serveRequests answers the channel's requests, calling shell in its own
+// goroutine once the client asks for one.
+func (t *sshTransport) serveRequests(requests <-chan *ssh.Request, shell func()) {
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			var pty ptyRequest
+			err := ssh.Unmarshal(req.Payload, &pty)
+			if err == nil {
+				t.mu.Lock()
+				t.opts.TerminalType = pty.Term
+				t.opts.Color = !strings.EqualFold(pty.Term, "dumb")
+				t.mu.Unlock()
+				t.resize(pty.Columns, pty.Rows)
+			}
+			_ = req.Reply(err == nil, nil)
+
+		case "window-change":
+			var wc windowChange
+			if ssh.Unmarshal(req.Payload, &wc) == nil {
+				t.resize(wc.Columns, wc.Rows)
+			}
+
+		case "shell":
+			_ = req.Reply(true, nil)
+			go shell()
+
+		default:
+			// exec, subsystem, env and the like
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+func (t *sshTransport) resize(columns, rows uint32) {
+	t.mu.Lock()
+	t.opts.Width = int(columns)
+	t.opts.Height = int(rows)
+	t.mu.Unlock()
+	if columns > 0 && rows > 0 {
+		// without a size the terminal wraps after every character
+		_ = t.term.SetSize(int(columns), int(rows))
+	}
+}
+
+func (t *sshTransport) options() telnet.Options {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.opts
+}
+
+func (t *sshTransport) Credentials() (*session.Credentials, error) {
+	return &session.Credentials{Name: t.name, Verified: true}, nil
+}
+
+func (t *sshTransport) Receive() (session.Input, error) {
+	line, err := t.term.ReadLine()
+	if err != nil {
+		return session.Input{}, err
+	}
+	return session.Input{Line: line}, nil
+}
+
+func (t *sshTransport) Send(r response.Response) error {
+	rendered, err := renderForTerminal(r, t.options())
+	if err != nil {
+		rendered = err.Error()
+	}
+	if rendered == "" {
+		return nil
+	}
+	_, err = fmt.Fprintln(t.term, rendered)
+	return err
+}
+
+func (t *sshTransport) Notify(msg response.Message) error {
+	_, err := fmt.Fprintln(t.term, t.options().Format(msg.Text))
+	return err
+}
+
+func (t *sshTransport) Sync(*player.Player, ...string) {}
+
+func (t *sshTransport) Protocol() string {
+	return config.ProtocolSSH
+}
+
+func (t *sshTransport) Close(reason string) {
+	if reason != "" {
+		fmt.Fprintln(t.term, reason)
+	}
+	_, _ = t.channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+	_ = t.channel.Close()
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"example.com/mud/account"
+)
+
+// sshConn is the client side of a handshake, as the server's callbacks see
+// it.
+type sshConn struct {
+	ssh.ConnMetadata
+	user string
+}
+
+func (c sshConn) User() string { return c.user }
+
+// newSSHKey returns a fresh client key.
+func newSSHKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(public)
+	require.NoError(t, err)
+	return key
+}
+
+func TestNewSSHConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	newAccounts := func(dir string) *account.Manager {
+		store, err := account.NewFileStore(dir)
+		require.NoError(t, err)
+		return account.NewManager(store)
+	}
+	accounts := newAccounts(filepath.Join(dir, "accounts"))
+	aliceKey, bobKey, strangerKey := newSSHKey(t), newSSHKey(t), newSSHKey(t)
+	for name, key := range map[string]ssh.PublicKey{"Alice": aliceKey, "Bob": bobKey} {
+		_, err := accounts.Create(name, "wonderland")
+		require.NoError(t, err)
+		require.NoError(t, accounts.AddPublicKey(name, string(ssh.MarshalAuthorizedKey(key))))
+	}
+	require.NoError(t, accounts.SetBanned("Bob", true))
+
+	// an account file outside the accounts directory, which a user name
+	// with a path in it would otherwise reach
+	outside := newAccounts(dir)
+	_, err := outside.Create("Mallory", "wonderland")
+	require.NoError(t, err)
+	require.NoError(t, outside.AddPublicKey("Mallory", string(ssh.MarshalAuthorizedKey(strangerKey))))
+
+	sshCfg, err := NewSSHConfig(filepath.Join(dir, "host_key"), accounts)
+	require.NoError(t, err)
+
+	type tc struct {
+		name     string
+		user     string
+		password string        // tried when set
+		key      ssh.PublicKey // tried otherwise
+		wantErr  error
+	}
+
+	cases := []tc{
+		{name: "password", user: "alice", password: "wonderland"},
+		{name: "key", user: "Alice", key: aliceKey},
+		{name: "wrong password", user: "Alice", password: "looking-glass", wantErr: account.ErrBadCredentials},
+		{name: "wrong key", user: "Alice", key: strangerKey, wantErr: account.ErrBadCredentials},
+		{name: "unknown account", user: "Carol", password: "wonderland", wantErr: account.ErrBadCredentials},
+		{name: "banned by password", user: "Bob", password: "wonderland", wantErr: account.ErrBanned},
+		{name: "banned by key", user: "Bob", key: bobKey, wantErr: account.ErrBanned},
+		{name: "path in the user name, by password", user: "../mallory", password: "wonderland", wantErr: account.ErrBadCredentials},
+		{name: "path in the user name, by key", user: "../mallory", key: strangerKey, wantErr: account.ErrBadCredentials},
+		{name: "no user name", user: "", password: "wonderland", wantErr: account.ErrBadCredentials},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := sshConn{user: c.user}
+			var perms *ssh.Permissions
+			var err error
+			if c.password != "" {
+				perms, err = sshCfg.PasswordCallback(conn, []byte(c.password))
+			} else {
+				perms, err = sshCfg.PublicKeyCallback(conn, c.key)
+			}
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				require.Nil(t, perms)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "Alice", perms.Extensions[accountExtension])
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"example.com/mud/config"
+	"example.com/mud/session"
+	"example.com/mud/telnet"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// telnetTransport renders responses as text fitted to the client, with GMCP
+// alongside for clients that ask for it.
+type telnetTransport struct {
+	conn     *telnet.Conn
+	lines    *bufio.Reader
+	protocol string
+
+	// echo is off while the player answers a secret prompt
+	hidden bool
+}
+
+// HandleTelnet negotiates with a telnet client and runs its session.
+func HandleTelnet(raw net.Conn, sessions *session.Manager) {
+	conn := telnet.NewConn(deadlineConn{raw})
+	if err := conn.Negotiate(); err != nil {
+		fmt.Println("telnet negotiation failed:", err)
+		_ = raw.Close()
+		return
+	}
+
+	t := &telnetTransport{conn: conn, lines: bufio.NewReader(conn), protocol: config.ProtocolTelnet}
+	if _, ok := raw.(*tls.Conn); ok {
+		t.protocol = config.ProtocolTelnetTLS
+	}
+	sessions.Run(t)
+}
+
+func (t *telnetTransport) Credentials() (*session.Credentials, error) {
+	return nil, nil
+}
+
+func (t *telnetTransport) Receive() (session.Input, error) {
+	line, err := t.lines.ReadString('\n')
+	if err != nil {
+		return session.Input{}, err
+	}
+
+	if t.hidden {
+		t.hidden = false
+		if err := t.conn.SetEcho(true); err != nil {
+			return session.Input{}, err
+		}
+		// the client didn't echo the newline either
+		if _, err := fmt.Fprint(t.conn, "\r\n"); err != nil {
+			return session.Input{}, err
+		}
+	}
+	return session.Input{Line: line}, nil
+}
+
+func (t *telnetTransport) Send(r response.Response) error {
+	if p, ok := r.(response.Prompt); ok {
+		if p.Secret && !t.hidden {
+			if err := t.conn.SetEcho(false); err != nil {
+				return err
+			}
+			t.hidden = true
+		}
+		_, err := fmt.Fprint(t.conn, p.Value)
+		return err
+	}
+
+	rendered, err := renderForTerminal(r, t.conn.Options())
+	if err != nil {
+		rendered = err.Error()
+	}
+	if rendered != "" {
+		if _, err := fmt.Fprintln(t.conn, rendered); err != nil {
+			return err
+		}
+	}
+	return t.conn.SendGMCP(GMCPForTelnet(r)...)
+}
+
+func (t *telnetTransport) Notify(msg response.Message) error {
+	if _, err := fmt.Fprint(t.conn, t.conn.Options().Format(msg.Text)+"\r\n"); err != nil {
+		return err
+	}
+	return t.conn.SendGMCP(GMCPForMessage(msg))
+}
+
+// Sync brings a GMCP client's view of the player up to date.
+func (t *telnetTransport) Sync(p *player.Player, panels ...string) {
+	if !t.conn.GMCPEnabled() {
+		return
+	}
+	msgs, err := GMCPState(p, panels...)
+	if err == nil {
+		err = t.conn.SendGMCP(msgs...)
+	}
+	if err != nil {
+		fmt.Println("gmcp send failed:", err)
+	}
+}
+
+func (t *telnetTransport) Protocol() string {
+	return t.protocol
+}
+
+func (t *telnetTransport) Close(reason string) {
+	if reason != "" {
+		fmt.Fprint(t.conn, reason+"\r\n")
+	}
+	_ = t.conn.Close()
+}
+
+// renderForTerminal renders r for a telnet or SSH client, fitted to its
+// negotiated width and color support.
+func renderForTerminal(r response.Response, opts telnet.Options) (string, error) {
+	rendered, err := player.RenderForTelnet(r)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := r.(response.MapView); ok {
+		// wrapping would break the grid apart
+		if !opts.Color {
+			rendered = telnet.StripANSI(rendered)
+		}
+		return rendered, nil
+	}
+	return opts.Format(rendered), nil
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeCert writes a self-signed certificate for name to dir, returning the
+// certificate's DER bytes.
+func writeCert(t *testing.T, dir, name string) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), certPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.pem"), keyPEM, 0o600))
+	return der
+}
+
+func TestCertReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	_, err := NewCertReloader(certFile, keyFile)
+	require.Error(t, err, "missing files")
+
+	first := writeCert(t, dir, "old.example.com")
+	r, err := NewCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, first, cert.Certificate[0])
+
+	renewed := writeCert(t, dir, "new.example.com")
+	require.NoError(t, r.Reload())
+	cert, err = r.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, renewed, cert.Certificate[0])
+
+	require.NoError(t, os.WriteFile(certFile, []byte("half written"), 0o600))
+	require.Error(t, r.Reload())
+	cert, err = r.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, renewed, cert.Certificate[0], "a failed reload keeps the old certificate")
+}
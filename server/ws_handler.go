@@ -2,19 +2,16 @@ package server
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
-	"strings"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 
 	"example.com/mud/config"
-	"example.com/mud/world"
-	"example.com/mud/world/entities"
+	"example.com/mud/session"
 	"example.com/mud/world/player"
 	"example.com/mud/world/response"
 )
@@ -24,11 +21,13 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
-// wsConn serialises writes to a *websocket.Conn.
-// gorilla/websocket does not allow concurrent writers.
+// wsConn is the session transport for WebSocket clients. It serialises writes
+// to the *websocket.Conn, since gorilla/websocket does not allow concurrent
+// writers.
 type wsConn struct {
-	mu   sync.Mutex
-	conn *websocket.Conn
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	protocol string
 }
 
 func (w *wsConn) writeResp(r response.Response) error {
@@ -38,7 +37,16 @@ func (w *wsConn) writeResp(r response.Response) error {
 	}
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	return w.conn.WriteJSON(response.WSMessage{Panel: r.Panel(), Content: content})
+	if err := w.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	if err := w.conn.WriteJSON(response.WSMessage{Panel: r.Panel(), Content: content}); err != nil {
+		// a failed write leaves the connection unusable, and closing it
+		// ends the session reading from it
+		_ = w.conn.Close()
+		return err
+	}
+	return nil
 }
 
 func (w *wsConn) writeText(text string) error {
@@ -50,6 +58,12 @@ type ClientMessage struct {
 	Type      string `json:"type"`
 	Text      string `json:"text,omitempty"`
 	Direction string `json:"direction,omitempty"`
+
+	// login
+	Name     string `json:"name,omitempty"`
+	Password string `json:"password,omitempty"`
+	Create   bool   `json:"create,omitempty"`
+	Token    string `json:"token,omitempty"` // from a "session" message, to resume after a dropped connection
 }
 
 func (w *wsConn) readMessage() (*ClientMessage, error) {
@@ -67,174 +81,93 @@ func (w *wsConn) readMessage() (*ClientMessage, error) {
 func (w *wsConn) closeWithError(msg string) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	_ = w.conn.WriteMessage(
+	_ = w.conn.WriteControl(
 		websocket.CloseMessage,
 		websocket.FormatCloseMessage(websocket.CloseNormalClosure, msg),
+		time.Now().Add(writeTimeout),
 	)
 }
 
 // HandleWS upgrades the HTTP connection to WebSocket and runs the full session.
-// The client must supply the player name as the "name" query parameter.
-func HandleWS(w http.ResponseWriter, r *http.Request, gameWorld *world.World, cfg *config.Config) {
-	name := strings.TrimSpace(r.URL.Query().Get("name"))
-
-	if vdn := player.NameValidation(name); vdn != "" {
-		http.Error(w, strings.TrimSpace(vdn), http.StatusBadRequest)
-		return
-	}
-
+// The client's first message must be a "login" message carrying the account
+// name and password, with create set to register a new account, or the token
+// from the last "session" message to resume after a dropped connection.
+func HandleWS(w http.ResponseWriter, r *http.Request, sessions *session.Manager) {
 	raw, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		return
 	}
-	conn := &wsConn{conn: raw}
-	defer raw.Close()
-
-	inbox := make(chan string, 64)
-	p, err := gameWorld.AddPlayer(name, inbox)
-	if err != nil {
-		msg := fmt.Sprintf("error adding player: %v", err)
-		fmt.Println(msg)
-		conn.closeWithError(msg)
-		return
+	protocol := config.ProtocolWS
+	if r.TLS != nil {
+		protocol = config.ProtocolWSS
 	}
+	sessions.Run(&wsConn{conn: raw, protocol: protocol})
+}
 
-	opening, err := p.OpeningMessage()
+func (w *wsConn) Credentials() (*session.Credentials, error) {
+	msg, err := w.readMessage()
 	if err != nil {
-		msg := fmt.Sprintf("error printing opening message: %v", err)
-		fmt.Println(msg)
-		conn.closeWithError(msg)
-		return
+		return nil, fmt.Errorf("expected login message")
 	}
-	if err := conn.writeResp(opening); err != nil {
-		return
+	if msg.Type != "login" {
+		return nil, fmt.Errorf("expected login message, got '%s'", msg.Type)
 	}
+	return &session.Credentials{Name: msg.Name, Password: msg.Password, Create: msg.Create, Token: msg.Token}, nil
+}
 
-	pushRoom := func() {
-		if room, err := p.GetRoomDescription(); err == nil {
-			_ = conn.writeResp(room)
+func (w *wsConn) Receive() (session.Input, error) {
+	for {
+		msg, err := w.readMessage()
+		if err != nil {
+			return session.Input{}, err
 		}
-		if mapView, err := p.Map(); err == nil {
-			_ = conn.writeResp(mapView)
+		switch msg.Type {
+		case "move":
+			return session.Input{Move: msg.Direction}, nil
+		case "text":
+			return session.Input{Line: msg.Text}, nil
 		}
 	}
+}
 
-	pushRoom()
-
-	// inbox goroutine: async room-broadcast messages always go to main panel.
-	// After each narrative message, push a fresh room description so the
-	// Items in Room panel stays in sync when other players pick up / drop items.
-	go func() {
-		for msg := range inbox {
-			_ = conn.writeText(msg)
-			pushRoom()
-		}
-	}()
-
-	handleWSOutgoing(conn, gameWorld, p, cfg, pushRoom)
+func (w *wsConn) Send(r response.Response) error {
+	return w.writeResp(r)
+}
 
-	gameWorld.DisconnectPlayer(p)
-	fmt.Println("WebSocket connection closed")
+// Notify sends messages to the main panel, keeping their kind and channel.
+func (w *wsConn) Notify(msg response.Message) error {
+	return w.writeResp(msg)
 }
 
-func handleWSOutgoing(conn *wsConn, gameWorld *world.World, p *player.Player, cfg *config.Config, pushRoom func()) {
-	for {
-		msg, err := conn.readMessage()
-		if err != nil {
-			break
+// Sync pushes a fresh room description and map for the room panel. The
+// inventory goes out wrapped in an inventory-changed message, which updates
+// the inventory drawer without opening it the way the inventory command does.
+func (w *wsConn) Sync(p *player.Player, panels ...string) {
+	all := len(panels) == 0
+	if all || slices.Contains(panels, response.PanelRoom) {
+		if room, err := p.GetRoomDescription(); err == nil {
+			_ = w.writeResp(room)
 		}
-
-		switch msg.Type {
-		case "move":
-			if cooldown := p.CooldownRemaining(); cooldown > 0 {
-				_ = conn.writeText(fmt.Sprintf("You need to catch your breath. Try again in %.1fs", cooldown.Seconds()))
-				continue
-			}
-			resp, moveErr := p.Move(msg.Direction)
-			if moveErr != nil {
-				_ = conn.writeText(fmt.Sprintf("error received: %v", moveErr))
-			} else if resp != nil {
-				pushRoom()
-			}
-			p.StartCooldown(time.Duration(cfg.PlayerRateLimit) * time.Millisecond)
-
-		case "text":
-			line := strings.TrimSpace(msg.Text)
-			if line == "" {
-				continue
-			}
-			if strings.ToLower(line) == "quit" {
-				return
-			}
-
-			// pending ambiguity resolution
-			if pending := p.Pending; pending != nil {
-				n, nerr := strconv.Atoi(line)
-				if nerr == nil {
-					slot := pending.Ambiguity.Slots[pending.StepIndex]
-					if n >= 1 && n <= len(slot.Matches) {
-						pending.Selected[slot.Role] = n - 1
-						pending.StepIndex++
-						if pending.StepIndex < len(pending.Ambiguity.Slots) {
-							promptWSSlot(conn, pending)
-						} else {
-							chosen := make(map[string]*entities.Entity, len(pending.Selected))
-							for _, s := range pending.Ambiguity.Slots {
-								idx := pending.Selected[s.Role]
-								chosen[s.Role] = s.Matches[idx].Entity
-							}
-							out, execErr := pending.Ambiguity.Execute(chosen)
-							p.Pending = nil
-							if execErr != nil {
-								_ = conn.writeText(execErr.Error())
-							} else if out != "" {
-								_ = conn.writeText(out)
-							}
-						}
-					}
-					continue
-				}
-				p.Pending = nil
-			}
-
-			if cooldown := p.CooldownRemaining(); cooldown > 0 {
-				_ = conn.writeText(fmt.Sprintf("You need to catch your breath. Try again in %.1fs", cooldown.Seconds()))
-				continue
-			}
-
-			resp, parseErr := gameWorld.Parse(p, line)
-			if parseErr != nil {
-				var amb *entities.AmbiguityError
-				if errors.As(parseErr, &amb) {
-					p.Pending = &entities.PendingAction{
-						Ambiguity: amb,
-						StepIndex: 0,
-						Selected:  map[string]int{},
-					}
-					promptWSSlot(conn, p.Pending)
-					continue
-				}
-				wrapped := fmt.Sprintf("error received: %v", parseErr)
-				fmt.Println(wrapped)
-				_ = conn.writeText(wrapped)
-			} else if resp != nil {
-				txt, isText := resp.(response.Text)
-				if !isText || txt.Value != "" {
-					_ = conn.writeResp(resp)
-					pushRoom()
-				}
-			}
-			p.StartCooldown(time.Duration(cfg.PlayerRateLimit) * time.Millisecond)
+		if mapView, err := p.Map(); err == nil {
+			_ = w.writeResp(mapView)
+		}
+	}
+	if slices.Contains(panels, response.PanelInventory) {
+		if inv, err := p.Inventory(); err == nil {
+			msg := response.InventoryChanged()
+			msg.Data = inv
+			_ = w.writeResp(msg)
 		}
 	}
 }
 
-func promptWSSlot(conn *wsConn, p *entities.PendingAction) {
-	slot := p.Ambiguity.Slots[p.StepIndex]
-	var sb strings.Builder
-	sb.WriteString(slot.Prompt)
-	for i, opt := range slot.Matches {
-		sb.WriteString(fmt.Sprintf("\n  %d) %s", i+1, opt.Text))
+func (w *wsConn) Protocol() string {
+	return w.protocol
+}
+
+func (w *wsConn) Close(reason string) {
+	if reason != "" {
+		w.closeWithError(reason)
 	}
-	_ = conn.writeText(sb.String())
+	_ = w.conn.Close()
 }
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/mud/models"
+	"example.com/mud/world"
+)
+
+const shutdownReason = "The server is shutting down."
+
+var _ world.Operator = (*Manager)(nil)
+
+func (m *Manager) AccountRole(name string) (models.Role, error) {
+	return m.accounts.Role(name)
+}
+
+func (m *Manager) SetRole(name string, role models.Role) error {
+	return m.accounts.SetRole(name, role)
+}
+
+func (m *Manager) SetBanned(name string, banned bool) error {
+	return m.accounts.SetBanned(name, banned)
+}
+
+func (m *Manager) PublicKeys(name string) ([]string, error) {
+	return m.accounts.PublicKeys(name)
+}
+
+func (m *Manager) AddPublicKey(name, key string) error {
+	return m.accounts.AddPublicKey(name, key)
+}
+
+func (m *Manager) RemovePublicKey(name string, i int) error {
+	return m.accounts.RemovePublicKey(name, i)
+}
+
+// Kick closes the player's connection, telling them why, and takes them out
+// of the world rather than leaving them link-dead.
+func (m *Manager) Kick(name, reason string) bool {
+	m.mu.Lock()
+	l, ok := m.links[strings.ToLower(name)]
+	if !ok {
+		m.mu.Unlock()
+		return false
+	}
+
+	s := l.session.Load()
+	if s == nil {
+		fmt.Printf("%s was kicked while link-dead\n", l.player.Name)
+		m.remove(l)
+	} else {
+		fmt.Printf("%s was kicked\n", l.player.Name)
+		s.kicked.Store(true)
+	}
+	m.mu.Unlock()
+
+	// closing ends the session's play loop, which removes the player
+	if s != nil {
+		s.t.Close(reason)
+	}
+	return true
+}
+
+// Force tells the player notice and runs line for them as though they'd
+// typed it. The line waits its turn behind what the player typed, and runs
+// in their own session, so it can't trip over their commands.
+func (m *Manager) Force(name, notice, line string) bool {
+	m.mu.Lock()
+	l, ok := m.links[strings.ToLower(name)]
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	s := l.session.Load()
+	if s == nil {
+		return false
+	}
+
+	select {
+	case s.inputs <- Input{Line: line, forced: notice}:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// Shutdown kicks everyone after delay and then reports that the server
+// should stop, through Stopping.
+func (m *Manager) Shutdown(delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.shutdown != nil {
+		m.shutdown.Stop()
+	}
+	fmt.Printf("Shutting down in %s\n", delay)
+	m.shutdown = time.AfterFunc(delay, m.stop)
+}
+
+func (m *Manager) CancelShutdown() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.shutdown == nil || !m.shutdown.Stop() {
+		return false
+	}
+	m.shutdown = nil
+	fmt.Println("Shutdown cancelled")
+	return true
+}
+
+// Stopping is closed when an admin's shutdown comes due.
+func (m *Manager) Stopping() <-chan struct{} {
+	return m.stopping
+}
+
+func (m *Manager) stop() {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.links))
+	for _, l := range m.links {
+		names = append(names, l.player.Name)
+	}
+	m.mu.Unlock()
+
+	for _, name := range names {
+		m.Kick(name, shutdownReason)
+	}
+	m.stopOnce.Do(func() { close(m.stopping) })
+}
@@ -0,0 +1,173 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/mud/account"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+const maxPasswordAttempts = 3
+
+const bannedReason = "You have been banned."
+
+// login authenticates the player, creating an account for names that haven't
+// been seen before. It returns the account's name as it was registered.
+func (s *Session) login() (string, error) {
+	creds, err := s.t.Credentials()
+	if err != nil {
+		return "", &refusal{reason: err.Error(), err: err}
+	}
+	if creds != nil {
+		return s.loginWith(creds)
+	}
+	return s.loginByPrompt()
+}
+
+// loginWith checks credentials sent in one go. There's no second chance, the
+// client shows the reason and lets the player try again.
+func (s *Session) loginWith(creds *Credentials) (string, error) {
+	if creds.Verified {
+		return creds.Name, nil
+	}
+	if creds.Token != "" {
+		if name, ok := s.m.nameForToken(creds.Token); ok {
+			// a player banned while link-dead mustn't slip back in
+			banned, err := s.m.accounts.Banned(name)
+			if err != nil {
+				return "", err
+			}
+			if banned {
+				return "", &refusal{reason: bannedReason, err: account.ErrBanned}
+			}
+			return name, nil
+		}
+		if creds.Password == "" {
+			return "", &refusal{reason: errNoSuchSession.Error(), err: errNoSuchSession}
+		}
+	}
+
+	name := strings.TrimSpace(creds.Name)
+	if vdn := player.NameValidation(name); vdn != "" {
+		return "", refuse("%s", strings.TrimSpace(vdn))
+	}
+
+	var a *account.Account
+	var err error
+	if creds.Create {
+		a, err = s.m.accounts.Create(name, creds.Password)
+	} else {
+		a, err = s.m.accounts.Authenticate(name, creds.Password)
+	}
+	switch {
+	case errors.Is(err, account.ErrExists):
+		return "", refuse("the name %s is already taken", name)
+	case errors.Is(err, account.ErrBadCredentials), errors.Is(err, account.ErrPasswordTooShort):
+		return "", &refusal{reason: err.Error(), err: err}
+	case errors.Is(err, account.ErrBanned):
+		return "", &refusal{reason: bannedReason, err: err}
+	case err != nil:
+		return "", err
+	}
+	return a.Name, nil
+}
+
+// loginByPrompt asks for a name and password until the player has
+// authenticated.
+func (s *Session) loginByPrompt() (string, error) {
+	for {
+		name, err := s.prompt("What is your name, weary adventurer? ", false)
+		if err != nil {
+			return "", err
+		}
+		if vdn := player.NameValidation(name); vdn != "" {
+			_ = s.t.Send(response.Text{Value: strings.TrimSpace(vdn)})
+			continue
+		}
+
+		exists, err := s.m.accounts.Exists(name)
+		if err != nil {
+			return "", err
+		}
+
+		if exists {
+			for range maxPasswordAttempts {
+				password, err := s.prompt("Password: ", true)
+				if err != nil {
+					return "", err
+				}
+				a, err := s.m.accounts.Authenticate(name, password)
+				if err == nil {
+					return a.Name, nil
+				}
+				if errors.Is(err, account.ErrBanned) {
+					return "", &refusal{reason: bannedReason, err: err}
+				}
+				if !errors.Is(err, account.ErrBadCredentials) {
+					return "", err
+				}
+				_ = s.t.Send(response.Text{Value: "That's not right."})
+			}
+			return "", &refusal{
+				reason: "Too many wrong passwords. Goodbye.",
+				err:    fmt.Errorf("too many failed password attempts for '%s'", name),
+			}
+		}
+
+		answer, err := s.prompt(fmt.Sprintf("I don't know anyone called %s. Are you new here? (y/n) ", name), false)
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(strings.ToLower(answer), "y") {
+			continue
+		}
+
+		a, err := s.createAccount(name)
+		if errors.Is(err, account.ErrExists) {
+			_ = s.t.Send(response.Text{Value: "Someone just took that name, pick another."})
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return a.Name, nil
+	}
+}
+
+func (s *Session) createAccount(name string) (*account.Account, error) {
+	for {
+		password, err := s.prompt("Choose a password: ", true)
+		if err != nil {
+			return nil, err
+		}
+		confirm, err := s.prompt("Repeat the password: ", true)
+		if err != nil {
+			return nil, err
+		}
+		if password != confirm {
+			_ = s.t.Send(response.Text{Value: "Those don't match."})
+			continue
+		}
+
+		a, err := s.m.accounts.Create(name, password)
+		if errors.Is(err, account.ErrPasswordTooShort) {
+			_ = s.t.Send(response.Text{Value: fmt.Sprintf("Your %s.", err)})
+			continue
+		}
+		return a, err
+	}
+}
+
+func (s *Session) prompt(text string, secret bool) (string, error) {
+	if err := s.t.Send(response.Prompt{Value: text, Secret: secret}); err != nil {
+		return "", fmt.Errorf("%w: %w", errHungUp, err)
+	}
+	in, err := s.receive()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(in.Line), nil
+}
@@ -0,0 +1,279 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"example.com/mud/account"
+	"example.com/mud/config"
+	"example.com/mud/world"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+const defaultReplayLimit = 100
+
+// Manager runs sessions and keeps track of the players they're attached to.
+// A player whose connection drops goes link-dead: they stay in the world for
+// a grace period, collecting what they'd have been told, and a new session
+// for the same account picks them back up.
+type Manager struct {
+	world       *world.World
+	accounts    *account.Manager
+	outbox      world.OutboxOptions
+	rateLimit   time.Duration
+	grace       time.Duration
+	replayLimit int
+
+	mu       sync.Mutex
+	links    map[string]*link // lower-case account name
+	shutdown *time.Timer      // pending admin shutdown, if any
+	stopping chan struct{}    // closed once the shutdown is carried out
+	stopOnce sync.Once
+}
+
+// link is a player in the world and the session currently playing them, if
+// any. It holds the account for as long as the player is in the world.
+type link struct {
+	player  *player.Player
+	outbox  *world.Outbox
+	release func()
+	done    chan struct{}
+
+	// session, token and expiry change under Manager.mu
+	session atomic.Pointer[Session] // nil while link-dead; readable without locks
+	token   string
+	expiry  *time.Timer
+
+	// mu keeps deliver from setting a message aside as missed while a new
+	// session is taking over. It's never held while writing to a connection.
+	mu     sync.Mutex
+	missed []response.Message
+}
+
+func NewManager(gameWorld *world.World, accounts *account.Manager, cfg *config.Config) *Manager {
+	replayLimit := cfg.Sessions.ReplayLimit
+	if replayLimit <= 0 {
+		replayLimit = defaultReplayLimit
+	}
+	outbox := world.OutboxOptions{
+		Policy:       cfg.Outbox.Policy,
+		Size:         cfg.Outbox.Size,
+		SlowAfter:    time.Duration(cfg.Outbox.SlowAfter) * time.Millisecond,
+		SpillSize:    cfg.Outbox.SpillSize,
+		DelayedAfter: time.Duration(cfg.Outbox.DelayedAfter) * time.Millisecond,
+	}
+	return &Manager{
+		world:       gameWorld,
+		accounts:    accounts,
+		outbox:      outbox,
+		rateLimit:   time.Duration(cfg.PlayerRateLimit) * time.Millisecond,
+		grace:       time.Duration(cfg.Sessions.LinkDeadGrace) * time.Second,
+		replayLimit: replayLimit,
+		links:       make(map[string]*link),
+		stopping:    make(chan struct{}),
+	}
+}
+
+// Run takes a connection from login to disconnect. It returns once the
+// connection has closed, which may be before the player leaves the world.
+func (m *Manager) Run(t Transport) {
+	(&Session{t: t, m: m}).run()
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// nameForToken finds the account whose player was given the resume token.
+func (m *Manager) nameForToken(token string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, l := range m.links {
+		if l.token == token {
+			return l.player.Name, true
+		}
+	}
+	return "", false
+}
+
+// attach gives the account's player to s, adding them to the world if they
+// aren't already there, and has s greet them. A player still attached to
+// another session is taken over, since that connection has usually died
+// without anyone noticing yet.
+func (m *Manager) attach(name string, s *Session) (*link, error) {
+	m.mu.Lock()
+	l, ok := m.links[strings.ToLower(name)]
+	if !ok {
+		var err error
+		if l, err = m.join(name, s); err != nil {
+			m.mu.Unlock()
+			return nil, err
+		}
+		token := l.token
+		m.mu.Unlock()
+
+		s.greet(l.player, token, nil, false)
+		return l, nil
+	}
+
+	if l.expiry != nil {
+		l.expiry.Stop()
+		l.expiry = nil
+	}
+	l.token = newToken()
+	token := l.token
+
+	l.mu.Lock()
+	old := l.session.Swap(s)
+	missed := l.missed
+	l.missed = nil
+	l.mu.Unlock()
+
+	if old == nil {
+		m.world.Publish(l.player.CurrentRoom(), fmt.Sprintf("%s's eyes clear as they return to this world.", l.player.Name), []*entities.Entity{l.player.Entity})
+	}
+	l.player.Connect(s.ctx, s.t.Protocol())
+	m.mu.Unlock()
+
+	if old != nil {
+		old.t.Close("You've connected from somewhere else.")
+	}
+	s.greet(l.player, token, missed, true)
+	return l, nil
+}
+
+// join adds the account's player to the world, played by s. Callers hold
+// m.mu.
+func (m *Manager) join(name string, s *Session) (*link, error) {
+	release, err := m.accounts.Acquire(name)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &link{
+		release: release,
+		done:    make(chan struct{}),
+		token:   newToken(),
+	}
+	l.outbox = world.NewOutbox(m.outbox, func() { m.dropSlow(l) })
+	p, err := m.world.AddPlayer(name, l.outbox)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("error adding player: %w", err)
+	}
+	l.player = p
+	l.session.Store(s)
+	p.Connect(s.ctx, s.t.Protocol())
+	if role, err := m.accounts.Role(name); err != nil {
+		fmt.Println(err)
+	} else {
+		p.SetRole(role)
+	}
+	m.links[strings.ToLower(name)] = l
+
+	go m.deliver(l)
+	return l, nil
+}
+
+// deliver hands the player's messages to their session, or keeps them for
+// later while they're link-dead. Writing takes as long as the connection
+// lets it, so it holds no locks meanwhile, and waits for the session's
+// greeting so new messages don't overtake it.
+func (m *Manager) deliver(l *link) {
+	for {
+		select {
+		case <-l.outbox.Ready():
+			l.mu.Lock()
+			msgs := l.outbox.Take()
+			s := l.session.Load()
+			if s == nil {
+				// changes needn't be kept, since the player is shown
+				// everything afresh when they come back
+				for _, msg := range msgs {
+					if msg.HasText() {
+						l.missed = append(l.missed, msg)
+					}
+				}
+				if over := len(l.missed) - m.replayLimit; over > 0 {
+					l.missed = l.missed[over:]
+				}
+			}
+			l.mu.Unlock()
+
+			if s != nil {
+				<-s.greeted
+				s.show(l.player, msgs)
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// dropSlow closes the connection of a player whose outbox has given up on
+// them, which also frees deliver if it's stuck writing to it. The player
+// goes link-dead and catches up on what they missed when they reconnect.
+func (m *Manager) dropSlow(l *link) {
+	s := l.session.Load()
+	if s == nil || !s.dropped.CompareAndSwap(false, true) {
+		return
+	}
+	fmt.Printf("%s can't keep up, dropping their connection\n", l.player.Name)
+	s.t.Close("")
+}
+
+// detach is called when s stops playing. Players who quit leave the world;
+// anyone else goes link-dead for the grace period. It reports whether s
+// still owned the player, rather than having been taken over.
+func (m *Manager) detach(l *link, s *Session, quit bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if l.session.Load() != s {
+		return false
+	}
+	l.session.Store(nil)
+
+	if quit || m.grace <= 0 {
+		m.remove(l)
+		return true
+	}
+
+	fmt.Printf("%s is link-dead\n", l.player.Name)
+	l.player.LinkDead()
+	m.world.Publish(l.player.CurrentRoom(), fmt.Sprintf("%s's eyes glaze over as they lose their link to this world.", l.player.Name), []*entities.Entity{l.player.Entity})
+	l.expiry = time.AfterFunc(m.grace, func() { m.expire(l) })
+	return true
+}
+
+// expire removes a player who stayed link-dead for the whole grace period.
+func (m *Manager) expire(l *link) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if l.session.Load() != nil || m.links[strings.ToLower(l.player.Name)] != l {
+		return
+	}
+	fmt.Printf("%s's link-dead grace period is over\n", l.player.Name)
+	m.remove(l)
+}
+
+// remove takes the player out of the world. Callers hold m.mu.
+func (m *Manager) remove(l *link) {
+	delete(m.links, strings.ToLower(l.player.Name))
+	close(l.done)
+	m.world.DisconnectPlayer(l.player)
+	l.release()
+}
+
+var errNoSuchSession = errors.New("that session has ended, log in again")
@@ -0,0 +1,320 @@
+// Package session runs a player's connection from login to disconnect, the
+// same way whichever transport the player connected with.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"example.com/mud/account"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// errHungUp wraps transport errors, which end the session without anything
+// left to tell the player.
+var errHungUp = errors.New("connection lost")
+
+// refusal is a login failure the player is told about.
+type refusal struct {
+	reason string
+	err    error
+}
+
+func (r *refusal) Error() string {
+	if r.err != nil {
+		return r.err.Error()
+	}
+	return r.reason
+}
+
+func refuse(format string, args ...any) error {
+	return &refusal{reason: fmt.Sprintf(format, args...)}
+}
+
+// Session is one connection's part in the game. Its Manager keeps the player
+// it's attached to, which can outlive it.
+type Session struct {
+	t Transport
+	m *Manager
+
+	// ctx is cancelled when the connection drops, so whatever the player's
+	// waiting on can be given up on
+	ctx    context.Context
+	hangUp context.CancelFunc
+
+	// greeted is closed once the player has been greeted, which messages from
+	// the world wait for
+	greeted chan struct{}
+
+	// inputs carries what the player sends, and lines admins force them to
+	// type, to the play loop
+	inputs chan Input
+
+	dropped atomic.Bool // closed for falling behind, see Manager.dropSlow
+	kicked  atomic.Bool // closed by Manager.Kick, the player leaves the world
+}
+
+func (s *Session) run() {
+	s.ctx, s.hangUp = context.WithCancel(context.Background())
+	defer s.hangUp()
+	s.greeted = make(chan struct{})
+	s.inputs = make(chan Input)
+
+	name, err := s.login()
+	if err != nil {
+		fmt.Println("login failed:", err)
+		s.close(err)
+		return
+	}
+
+	l, err := s.m.attach(name, s)
+	if errors.Is(err, account.ErrAlreadyLoggedIn) {
+		s.t.Close(fmt.Sprintf("%s is already playing.", name))
+		return
+	} else if err != nil {
+		fmt.Println("login failed:", err)
+		s.t.Close(err.Error())
+		return
+	}
+
+	quit := s.play(l.player)
+	kicked := s.kicked.Load()
+
+	if !s.m.detach(l, s, quit || kicked) {
+		// taken over by a newer session, which closed this one
+		return
+	}
+	fmt.Printf("Connection closed for %s\n", name)
+	if s.dropped.Load() || kicked {
+		return
+	}
+	if quit {
+		s.t.Close("Goodbye.")
+	} else {
+		s.t.Close("")
+	}
+}
+
+// close ends the session after a failed login.
+func (s *Session) close(err error) {
+	var r *refusal
+	switch {
+	case errors.As(err, &r):
+		s.t.Close(r.reason)
+	case errors.Is(err, errHungUp):
+		s.t.Close("")
+	default:
+		s.t.Close("login failed")
+	}
+}
+
+func (s *Session) receive() (Input, error) {
+	in, err := s.t.Receive()
+	if err != nil {
+		return Input{}, fmt.Errorf("%w: %w", errHungUp, err)
+	}
+	return in, nil
+}
+
+// greet shows the player where they are and gives the client a token it can
+// resume with. Players coming back from being link-dead also get everything
+// they missed.
+func (s *Session) greet(p *player.Player, token string, missed []response.Message, resumed bool) {
+	defer close(s.greeted)
+	_ = s.t.Send(response.Resume{Token: token})
+
+	if resumed {
+		_ = s.t.Send(response.Text{Value: "You find yourself back where you left off."})
+	}
+	opening, err := p.OpeningMessage()
+	if err != nil {
+		msg := fmt.Sprintf("error printing opening message: %v", err)
+		fmt.Println(msg)
+		_ = s.t.Send(response.Text{Value: msg})
+		return
+	}
+	_ = s.t.Send(opening)
+
+	if len(missed) > 0 {
+		_ = s.t.Send(response.Text{Value: "While you were away:"})
+		for _, msg := range missed {
+			_ = s.t.Notify(msg)
+		}
+	}
+	s.t.Sync(p)
+}
+
+// show passes messages from the world on to the transport, syncing each
+// changed panel once.
+func (s *Session) show(p *player.Player, msgs []response.Message) {
+	var panels []string
+	for _, msg := range msgs {
+		if msg.HasText() {
+			_ = s.t.Notify(msg)
+		} else if panel := msg.Panel(); !slices.Contains(panels, panel) {
+			panels = append(panels, panel)
+		}
+	}
+	if len(panels) > 0 {
+		s.t.Sync(p, panels...)
+	}
+}
+
+// play runs the player's commands until the connection drops, or they quit,
+// which it reports.
+func (s *Session) play(p *player.Player) bool {
+	go s.readInputs()
+	for {
+		var in Input
+		select {
+		case in = <-s.inputs:
+		case <-s.ctx.Done():
+			return false
+		}
+		p.Touch()
+
+		switch {
+		case in.forced != "":
+			_ = s.t.Send(response.Text{Value: in.forced})
+			s.command(p, strings.TrimSpace(in.Line))
+		case in.Move != "":
+			s.move(p, in.Move)
+		default:
+			line := strings.TrimSpace(in.Line)
+			if line == "" {
+				continue
+			}
+			if strings.ToLower(line) == "quit" {
+				return true
+			}
+			s.command(p, line)
+		}
+		s.t.Sync(p)
+	}
+}
+
+// readInputs receives from the transport in the background, so the session
+// hears about the connection dropping, and hangs up, while the player's
+// command is still running.
+func (s *Session) readInputs() {
+	defer s.hangUp()
+	for {
+		in, err := s.receive()
+		if err != nil {
+			return
+		}
+		select {
+		case s.inputs <- in:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Session) move(p *player.Player, direction string) {
+	if s.coolingDown(p) {
+		return
+	}
+	var resp response.Response
+	var err error
+	s.m.world.Act(func() { resp, err = p.Move(direction) })
+	if err != nil {
+		_ = s.t.Send(response.Text{Value: fmt.Sprintf("error received: %v", err)})
+	} else if resp != nil {
+		_ = s.t.Send(resp)
+	}
+	p.StartCooldown(s.m.rateLimit)
+}
+
+func (s *Session) command(p *player.Player, line string) {
+	if pending := p.Pending; pending != nil {
+		if n, err := strconv.Atoi(line); err == nil {
+			s.choose(p, pending, n)
+			return
+		}
+		// anything but a number drops the pending action
+		p.Pending = nil
+	}
+
+	if s.coolingDown(p) {
+		return
+	}
+
+	resp, err := s.m.world.Parse(p, line)
+	if err != nil {
+		var amb *entities.AmbiguityError
+		if errors.As(err, &amb) {
+			p.Pending = &entities.PendingAction{
+				Ambiguity: amb,
+				StepIndex: 0,
+				Selected:  map[string]int{},
+			}
+			s.promptSlot(p.Pending)
+			return
+		}
+		msg := fmt.Sprintf("error received: %v", err)
+		fmt.Println(msg)
+		_ = s.t.Send(response.Text{Value: msg})
+	} else if txt, isText := resp.(response.Text); resp != nil && (!isText || txt.Value != "") {
+		_ = s.t.Send(resp)
+	}
+
+	p.StartCooldown(s.m.rateLimit)
+}
+
+func (s *Session) coolingDown(p *player.Player) bool {
+	cooldown := p.CooldownRemaining()
+	if cooldown <= 0 {
+		return false
+	}
+	_ = s.t.Send(response.Text{Value: fmt.Sprintf("You need to catch your breath. Try again in %.1fs", cooldown.Seconds())})
+	return true
+}
+
+// choose answers the current ambiguity prompt with the player's nth option,
+// running the action once every slot has been answered. Numbers out of range
+// are ignored.
+func (s *Session) choose(p *player.Player, pending *entities.PendingAction, n int) {
+	slot := pending.Ambiguity.Slots[pending.StepIndex]
+	if n < 1 || n > len(slot.Matches) {
+		return
+	}
+	pending.Selected[slot.Role] = n - 1
+	pending.StepIndex++
+
+	if pending.StepIndex < len(pending.Ambiguity.Slots) {
+		s.promptSlot(pending)
+		return
+	}
+
+	chosen := make(map[string]*entities.Entity, len(pending.Selected))
+	for _, sl := range pending.Ambiguity.Slots {
+		chosen[sl.Role] = sl.Matches[pending.Selected[sl.Role]].Entity
+	}
+	var out string
+	var err error
+	s.m.world.Act(func() { out, err = pending.Ambiguity.Execute(chosen) })
+	p.Pending = nil
+	if err != nil {
+		_ = s.t.Send(response.Text{Value: err.Error()})
+	} else if out != "" {
+		_ = s.t.Send(response.Text{Value: out})
+	}
+}
+
+func (s *Session) promptSlot(pending *entities.PendingAction) {
+	slot := pending.Ambiguity.Slots[pending.StepIndex]
+	var b strings.Builder
+	b.WriteString(slot.Prompt)
+	for i, opt := range slot.Matches {
+		fmt.Fprintf(&b, "\n  %d) %s", i+1, opt.Text)
+	}
+	_ = s.t.Send(response.Text{Value: b.String()})
+}
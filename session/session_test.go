@@ -0,0 +1,406 @@
+package session
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/mud/account"
+	"example.com/mud/config"
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/world"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+	"example.com/mud/world/worldtest"
+	"github.com/stretchr/testify/require"
+)
+
+var registerOnce sync.Once
+
+// fakeTransport plays back what a player sent and records what they were
+// shown. Once the script runs out the connection drops.
+type fakeTransport struct {
+	creds    *Credentials
+	script   []string
+	sent     []response.Response
+	notified []string
+	closed   *string
+}
+
+func (f *fakeTransport) Credentials() (*Credentials, error) { return f.creds, nil }
+
+func (f *fakeTransport) Receive() (Input, error) {
+	if len(f.script) == 0 {
+		return Input{}, io.EOF
+	}
+	line := f.script[0]
+	f.script = f.script[1:]
+	return Input{Line: line}, nil
+}
+
+func (f *fakeTransport) Send(r response.Response) error {
+	f.sent = append(f.sent, r)
+	return nil
+}
+
+func (f *fakeTransport) Notify(msg response.Message) error {
+	f.notified = append(f.notified, msg.Text)
+	return nil
+}
+
+func (f *fakeTransport) Sync(*player.Player, ...string) {}
+
+func (f *fakeTransport) Protocol() string { return config.ProtocolTelnet }
+
+func (f *fakeTransport) Close(reason string) {
+	if f.closed != nil {
+		panic(errors.New("closed twice"))
+	}
+	f.closed = &reason
+}
+
+// prompts returns what the player was asked, in order.
+func (f *fakeTransport) prompts() []string {
+	var out []string
+	for _, r := range f.sent {
+		if p, ok := r.(response.Prompt); ok {
+			out = append(out, p.Value)
+		}
+	}
+	return out
+}
+
+// token returns the resume token the player was given.
+func (f *fakeTransport) token() string {
+	for _, r := range f.sent {
+		if resume, ok := r.(response.Resume); ok {
+			return resume.Token
+		}
+	}
+	return ""
+}
+
+func newTestWorld() *world.World {
+	return world.NewWorld(worldtest.Entities(), "Hall")
+}
+
+func TestSession_Login(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name        string
+		creds       *Credentials
+		script      []string
+		wantPrompts []string
+		wantClosed  string
+		wantPlaying bool
+	}
+
+	cases := []tc{
+		{
+			name:   "new account by prompt",
+			script: []string{"Bob", "yes", "hunter22", "hunter23", "hunter22", "hunter22", "quit"},
+			wantPrompts: []string{
+				"What is your name, weary adventurer? ",
+				"I don't know anyone called Bob. Are you new here? (y/n) ",
+				"Choose a password: ",
+				"Repeat the password: ",
+				"Choose a password: ",
+				"Repeat the password: ",
+			},
+			wantClosed:  "Goodbye.",
+			wantPlaying: true,
+		},
+		{
+			name:   "existing account by prompt",
+			script: []string{"alice", "wrong-one", "wonderland", "quit"},
+			wantPrompts: []string{
+				"What is your name, weary adventurer? ",
+				"Password: ",
+				"Password: ",
+			},
+			wantClosed:  "Goodbye.",
+			wantPlaying: true,
+		},
+		{
+			name:   "too many wrong passwords",
+			script: []string{"Alice", "a", "b", "c"},
+			wantPrompts: []string{
+				"What is your name, weary adventurer? ",
+				"Password: ",
+				"Password: ",
+				"Password: ",
+			},
+			wantClosed: "Too many wrong passwords. Goodbye.",
+		},
+		{
+			name:        "credentials",
+			creds:       &Credentials{Name: "Alice", Password: "wonderland"},
+			script:      []string{"quit"},
+			wantClosed:  "Goodbye.",
+			wantPlaying: true,
+		},
+		{
+			name:       "credentials for a taken name",
+			creds:      &Credentials{Name: "Alice", Password: "wonderland", Create: true},
+			wantClosed: "the name Alice is already taken",
+		},
+		{
+			name:   "banned account",
+			script: []string{"Mallory", "hunter22"},
+			wantPrompts: []string{
+				"What is your name, weary adventurer? ",
+				"Password: ",
+			},
+			wantClosed: bannedReason,
+		},
+		{
+			name:       "credentials with a bad name",
+			creds:      &Credentials{Name: "R2D2", Password: "wonderland"},
+			wantClosed: "I'm no good with numbers or spaces, and I only speak English!",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := account.NewFileStore(t.TempDir())
+			require.NoError(t, err)
+			accounts := account.NewManager(store)
+			_, err = accounts.Create("Alice", "wonderland")
+			require.NoError(t, err)
+			_, err = accounts.Create("Mallory", "hunter22")
+			require.NoError(t, err)
+			require.NoError(t, accounts.SetBanned("Mallory", true))
+
+			ft := &fakeTransport{creds: c.creds, script: c.script}
+			NewManager(newTestWorld(), accounts, &config.Config{}).Run(ft)
+
+			require.Equal(t, c.wantPrompts, ft.prompts())
+			require.NotNil(t, ft.closed)
+			require.Equal(t, c.wantClosed, *ft.closed)
+			require.Empty(t, ft.script, "the session reads everything it was sent")
+
+			var playing bool
+			for _, r := range ft.sent {
+				if _, ok := r.(response.RoomDescription); ok {
+					playing = true
+				}
+			}
+			require.Equal(t, c.wantPlaying, playing)
+		})
+	}
+}
+
+func TestManager_LinkDead(t *testing.T) {
+	t.Parallel()
+
+	store, err := account.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	accounts := account.NewManager(store)
+	_, err = accounts.Create("Alice", "wonderland")
+	require.NoError(t, err)
+
+	w := newTestWorld()
+	hall, ok := w.GetEntityById("Hall")
+	require.True(t, ok)
+	m := NewManager(w, accounts, &config.Config{Sessions: config.Sessions{LinkDeadGrace: 60}})
+	missed := func() int {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		l := m.links["alice"]
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.missed)
+	}
+
+	// the connection drops straight away, leaving Alice in the world
+	dropped := &fakeTransport{creds: &Credentials{Name: "Alice", Password: "wonderland"}}
+	m.Run(dropped)
+	_, ok = w.GetPlayerEntity("Alice")
+	require.True(t, ok, "link-dead players stay in the world")
+	_, err = accounts.Acquire("Alice")
+	require.ErrorIs(t, err, account.ErrAlreadyLoggedIn, "and keep their account")
+
+	w.Publish(hall, "A draft blows through the hall.", nil)
+	require.Eventually(t, func() bool { return missed() == 1 }, time.Second, time.Millisecond)
+
+	// not once she's been banned
+	require.NoError(t, accounts.SetBanned("Alice", true))
+	banned := &fakeTransport{creds: &Credentials{Token: dropped.token()}}
+	m.Run(banned)
+	require.Equal(t, bannedReason, *banned.closed)
+	require.NoError(t, accounts.SetBanned("Alice", false))
+
+	// the token picks her back up, with what she missed
+	resumed := &fakeTransport{creds: &Credentials{Token: dropped.token()}, script: []string{"quit"}}
+	m.Run(resumed)
+	require.Equal(t, []string{"A draft blows through the hall."}, resumed.notified)
+	require.Equal(t, "Goodbye.", *resumed.closed)
+	require.NotEqual(t, dropped.token(), resumed.token(), "tokens are single use")
+	_, ok = w.GetPlayerEntity("Alice")
+	require.False(t, ok, "quitting leaves the world")
+
+	// an old token doesn't log anyone in
+	stale := &fakeTransport{creds: &Credentials{Token: resumed.token()}}
+	m.Run(stale)
+	require.Equal(t, errNoSuchSession.Error(), *stale.closed)
+
+	// nobody comes back in time
+	m.grace = 10 * time.Millisecond
+	m.Run(&fakeTransport{creds: &Credentials{Name: "Alice", Password: "wonderland"}})
+	require.Eventually(t, func() bool {
+		_, ok := w.GetPlayerEntity("Alice")
+		return !ok
+	}, time.Second, time.Millisecond)
+	release, err := accounts.Acquire("Alice")
+	require.NoError(t, err, "the account is released with the player")
+	release()
+}
+
+// stalledTransport is a connection whose client has stopped reading: writing
+// a message to it blocks until the test ends.
+type stalledTransport struct {
+	fakeTransport
+	stalled chan struct{} // closed when a write gets stuck
+	release chan struct{}
+	once    sync.Once
+}
+
+func (s *stalledTransport) Receive() (Input, error) {
+	<-s.release
+	return Input{}, io.EOF
+}
+
+func (s *stalledTransport) Notify(response.Message) error {
+	s.once.Do(func() { close(s.stalled) })
+	<-s.release
+	return errors.New("write timed out")
+}
+
+func TestManager_StalledConnection(t *testing.T) {
+	t.Parallel()
+
+	store, err := account.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	accounts := account.NewManager(store)
+	_, err = accounts.Create("Alice", "wonderland")
+	require.NoError(t, err)
+	_, err = accounts.Create("Bob", "builder1")
+	require.NoError(t, err)
+
+	w := newTestWorld()
+	hall, ok := w.GetEntityById("Hall")
+	require.True(t, ok)
+	m := NewManager(w, accounts, &config.Config{})
+
+	alice := &stalledTransport{
+		fakeTransport: fakeTransport{creds: &Credentials{Name: "Alice", Password: "wonderland"}},
+		stalled:       make(chan struct{}),
+		release:       make(chan struct{}),
+	}
+	defer close(alice.release)
+	go m.Run(alice)
+	require.Eventually(t, func() bool {
+		_, ok := w.FindPlayer("Alice")
+		return ok
+	}, 5*time.Second, time.Millisecond)
+
+	w.Publish(hall, "A draft blows through the hall.", nil)
+	select {
+	case <-alice.stalled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the message never reached Alice's connection")
+	}
+
+	// resuming looks through every player's token, and logging in takes the
+	// same locks, so neither may wait on Alice's connection
+	bob := &fakeTransport{creds: &Credentials{Name: "Bob", Password: "builder1"}, script: []string{"quit"}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Run(&fakeTransport{creds: &Credentials{Token: "stale"}})
+		m.Run(bob)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("a stalled connection held up logging in")
+	}
+	require.Equal(t, "Goodbye.", *bob.closed)
+}
+
+// liveTransport is a connection the player types on until lines is closed.
+type liveTransport struct {
+	fakeTransport
+	lines chan string
+}
+
+func (l *liveTransport) Receive() (Input, error) {
+	line, ok := <-l.lines
+	if !ok {
+		return Input{}, io.EOF
+	}
+	return Input{Line: line}, nil
+}
+
+func TestManager_KickAndForce(t *testing.T) {
+	t.Parallel()
+	registerOnce.Do(func() { require.NoError(t, commands.RegisterBuiltInCommands()) })
+
+	store, err := account.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	accounts := account.NewManager(store)
+	_, err = accounts.Create("Alice", "wonderland")
+	require.NoError(t, err)
+	require.NoError(t, accounts.SetRole("Alice", models.RoleBuilder))
+
+	w := newTestWorld()
+	m := NewManager(w, accounts, &config.Config{Sessions: config.Sessions{LinkDeadGrace: 60}})
+	w.SetOperator(m)
+	m.Run(&fakeTransport{creds: &Credentials{Name: "Alice", Password: "wonderland"}})
+	alice, ok := w.FindPlayer("Alice")
+	require.True(t, ok, "link-dead players stay in the world")
+	require.Equal(t, models.RoleBuilder, alice.Role(), "players get their account's role")
+
+	require.False(t, m.Force("Alice", "Bob makes you: look", "look"), "link-dead players can't be forced")
+	require.True(t, m.Kick("Alice", "Bob has kicked you out."))
+	_, ok = w.FindPlayer("Alice")
+	require.False(t, ok, "kicked players leave the world")
+	require.False(t, m.Kick("Alice", ""), "only once")
+
+	release, err := accounts.Acquire("Alice")
+	require.NoError(t, err, "the account is released with the player")
+	release()
+
+	// a forced line runs in the player's own session, between what they type
+	live := &liveTransport{
+		fakeTransport: fakeTransport{creds: &Credentials{Name: "Alice", Password: "wonderland"}},
+		lines:         make(chan string),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Run(live)
+	}()
+	live.lines <- "say hello"
+	require.True(t, m.Force("Alice", "Bob makes you: look", "look"))
+	live.lines <- "sshkey list"
+	close(live.lines)
+	<-done
+
+	var forced int
+	for i, r := range live.sent {
+		if r == (response.Text{Value: "Bob makes you: look"}) {
+			forced = i
+		}
+	}
+	require.NotZero(t, forced, "the player is told what they were made to do")
+	require.Equal(t, response.Text{Value: "Hall: A room."}, live.sent[forced+1], "then it's done")
+	require.Contains(t, live.sent, response.Text{Value: "You have no SSH keys. Add one with: sshkey add <public key>"}, "account commands reach the account")
+	require.True(t, m.Kick("Alice", ""))
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// Credentials are a whole login sent in one message, by clients that have
+// their own login form. Verified credentials were already checked by the
+// transport, as SSH does during its handshake. A Token from an earlier
+// session's response.Resume picks that session's player back up without a
+// password.
+type Credentials struct {
+	Name     string
+	Password string
+	Create   bool
+	Verified bool
+	Token    string
+}
+
+// Input is one thing the player sent: a typed line, or a direction from a
+// movement control.
+type Input struct {
+	Line string
+	Move string
+
+	forced string // the notice for a line an admin made the player type
+}
+
+// Transport carries one connection's traffic. Implementations only translate
+// between their wire format and Inputs and Responses; the Session decides
+// what happens.
+type Transport interface {
+	// Credentials returns the player's login for clients that send it in one
+	// message. Transports that log in by prompting return nil, and are asked
+	// for the name and password one line at a time. Errors are shown to the
+	// player.
+	Credentials() (*Credentials, error)
+
+	// Receive blocks until the player sends something. Any error ends the
+	// session.
+	Receive() (Input, error)
+
+	// Send shows a response to the player.
+	Send(r response.Response) error
+
+	// Notify shows a message the world sent the player unprompted. It's only
+	// given messages with text; changes come through Sync.
+	Notify(msg response.Message) error
+
+	// Sync brings the given panels up to date, for transports that keep the
+	// player's surroundings on show outside the main text. It's called with
+	// no panels, meaning all of them, after every input, and with
+	// response.PanelRoom or response.PanelInventory when the world says
+	// they've changed.
+	Sync(p *player.Player, panels ...string)
+
+	// Protocol names what the player connected with, one of the
+	// config.Protocol values.
+	Protocol() string
+
+	// Close ends the connection, telling the player why if reason isn't empty.
+	Close(reason string)
+}
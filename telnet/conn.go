@@ -0,0 +1,388 @@
+package telnet
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxSubnegotiation caps how much of a subnegotiation is buffered, so a
+// client can't grow it without bound.
+const maxSubnegotiation = 256
+
+// maxTTypeRequests caps how many times the terminal type is asked for while
+// cycling through a client's MTTS answers.
+const maxTTypeRequests = 4
+
+type readState int
+
+const (
+	stateData readState = iota
+	stateCR
+	stateIAC
+	stateOption
+	stateSBOption
+	stateSBData
+	stateSBIAC
+)
+
+// Conn wraps a connection to a telnet client. Reads return only the data the
+// client typed, with negotiation handled on the side and line endings turned
+// into '\n'. Writes escape IAC and turn '\n' into "\r\n".
+type Conn struct {
+	net.Conn
+
+	// read side, only used by the goroutine calling Read
+	raw   []byte
+	state readState
+	cmd   byte
+	sbOpt byte
+	sb    []byte
+
+	writeMu sync.Mutex
+	lastOut byte
+
+	mu        sync.Mutex
+	requested map[byte]bool // options we asked the client to enable with DO
+	offered   map[byte]bool // options we offered to enable with WILL
+	him       map[byte]bool // options the client has enabled
+	us        map[byte]bool // options we have enabled
+	width     int
+	height    int
+	ttypes    []string
+	ttypeAsks int
+	mtts      int
+
+	gmcpSupports map[string]bool // lower-case GMCP packages, nil until the client lists them
+
+	// gmcpMu is held while GMCP is sent, so state packages reach the client
+	// in the order they changed
+	gmcpMu   sync.Mutex
+	gmcpLast map[string][]byte // last payload sent per package
+}
+
+// NewConn wraps conn. Call Negotiate to ask the client for its window size
+// and terminal type.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		Conn:      conn,
+		requested: make(map[byte]bool),
+		offered:   make(map[byte]bool),
+		him:       make(map[byte]bool),
+		us:        make(map[byte]bool),
+	}
+}
+
+// Negotiate asks the client to report its window size and terminal type, and
+// offers GMCP. Answers arrive while the connection is being read.
+func (c *Conn) Negotiate() error {
+	c.mu.Lock()
+	c.requested[OptNAWS] = true
+	c.requested[OptTType] = true
+	c.offered[OptGMCP] = true
+	c.mu.Unlock()
+
+	return c.sendRaw(IAC, DO, OptNAWS, IAC, DO, OptTType, IAC, WILL, OptGMCP)
+}
+
+// SetEcho turns the client's local echo on or off. Turn it off while reading
+// a password.
+func (c *Conn) SetEcho(on bool) error {
+	c.mu.Lock()
+	// the server "echoing" is how telnet asks the client not to, and since
+	// we never actually echo, the input stays hidden
+	var reply []byte
+	if !on && !c.us[OptEcho] {
+		c.us[OptEcho] = true
+		reply = []byte{IAC, WILL, OptEcho}
+	}
+	if on && c.us[OptEcho] {
+		c.us[OptEcho] = false
+		reply = []byte{IAC, WONT, OptEcho}
+	}
+	c.mu.Unlock()
+
+	if reply == nil {
+		return nil
+	}
+	return c.sendRaw(reply...)
+}
+
+// Options describes the client as negotiated so far.
+func (c *Conn) Options() Options {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	opts := Options{
+		Width:  c.width,
+		Height: c.height,
+		Color:  true,
+		MTTS:   c.mtts,
+	}
+	if len(c.ttypes) > 0 {
+		opts.ClientName = c.ttypes[0]
+		opts.TerminalType = c.ttypes[len(c.ttypes)-1]
+	}
+
+	switch {
+	case c.mtts != 0:
+		opts.Color = c.mtts&MTTSANSI != 0
+	case strings.EqualFold(opts.TerminalType, "dumb"):
+		opts.Color = false
+	}
+	return opts
+}
+
+func (c *Conn) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if c.raw == nil {
+		c.raw = make([]byte, 4096)
+	}
+
+	for {
+		n, err := c.Conn.Read(c.raw[:min(len(p), len(c.raw))])
+		out := c.decode(c.raw[:n], p)
+		if out > 0 || err != nil {
+			return out, err
+		}
+	}
+}
+
+// decode runs the read state machine over in, writing data bytes to out.
+// out is at least as long as in, since decoding never adds bytes.
+func (c *Conn) decode(in, out []byte) int {
+	n := 0
+	for _, b := range in {
+		switch c.state {
+		case stateCR:
+			c.state = stateData
+			// CR LF and CR NUL both end a line, which was already written
+			if b == '\n' || b == 0 {
+				continue
+			}
+			fallthrough
+
+		case stateData:
+			switch b {
+			case IAC:
+				c.state = stateIAC
+			case '\r':
+				out[n] = '\n'
+				n++
+				c.state = stateCR
+			default:
+				out[n] = b
+				n++
+			}
+
+		case stateIAC:
+			switch b {
+			case IAC:
+				out[n] = IAC
+				n++
+				c.state = stateData
+			case WILL, WONT, DO, DONT:
+				c.cmd = b
+				c.state = stateOption
+			case SB:
+				c.state = stateSBOption
+			default:
+				// NOP, GA, AYT and friends carry nothing we need
+				c.state = stateData
+			}
+
+		case stateOption:
+			c.negotiate(c.cmd, b)
+			c.state = stateData
+
+		case stateSBOption:
+			c.sbOpt = b
+			c.sb = c.sb[:0]
+			c.state = stateSBData
+
+		case stateSBData:
+			if b == IAC {
+				c.state = stateSBIAC
+			} else if len(c.sb) < maxSubnegotiation {
+				c.sb = append(c.sb, b)
+			}
+
+		case stateSBIAC:
+			switch b {
+			case SE:
+				c.subnegotiate(c.sbOpt, c.sb)
+				c.state = stateData
+			case IAC:
+				if len(c.sb) < maxSubnegotiation {
+					c.sb = append(c.sb, IAC)
+				}
+				c.state = stateSBData
+			default:
+				// malformed, drop the subnegotiation
+				c.state = stateData
+			}
+		}
+	}
+	return n
+}
+
+// negotiate answers one WILL/WONT/DO/DONT from the client. Options are only
+// answered when their state changes, so the two ends can't loop.
+func (c *Conn) negotiate(cmd, opt byte) {
+	if reply := c.negotiation(cmd, opt); len(reply) > 0 {
+		_ = c.sendRaw(reply...)
+	}
+}
+
+// negotiation records one WILL/WONT/DO/DONT and returns the answer, which is
+// written once c.mu is released so a slow client only holds up writes.
+func (c *Conn) negotiation(cmd, opt byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch cmd {
+	case WILL:
+		if opt != OptNAWS && opt != OptTType {
+			return []byte{IAC, DONT, opt}
+		}
+		if c.him[opt] {
+			return nil
+		}
+		c.him[opt] = true
+		var reply []byte
+		if !c.requested[opt] {
+			reply = append(reply, IAC, DO, opt)
+		}
+		c.requested[opt] = false
+		if opt == OptTType {
+			reply = append(reply, c.requestTType()...)
+		}
+		return reply
+
+	case WONT:
+		c.requested[opt] = false
+		if c.him[opt] {
+			c.him[opt] = false
+			return []byte{IAC, DONT, opt}
+		}
+
+	case DO:
+		switch opt {
+		case OptEcho:
+			if !c.us[OptEcho] {
+				// we never echo unless we offered to
+				return []byte{IAC, WONT, OptEcho}
+			}
+		case OptSGA, OptGMCP:
+			var reply []byte
+			if !c.us[opt] {
+				c.us[opt] = true
+				if !c.offered[opt] {
+					reply = []byte{IAC, WILL, opt}
+				}
+			}
+			c.offered[opt] = false
+			return reply
+		default:
+			return []byte{IAC, WONT, opt}
+		}
+
+	case DONT:
+		c.offered[opt] = false
+		if c.us[opt] {
+			c.us[opt] = false
+			return []byte{IAC, WONT, opt}
+		}
+	}
+	return nil
+}
+
+func (c *Conn) subnegotiate(opt byte, data []byte) {
+	if reply := c.subnegotiation(opt, data); len(reply) > 0 {
+		_ = c.sendRaw(reply...)
+	}
+}
+
+// subnegotiation records what the client reported and returns any follow-up
+// question, which is written once c.mu is released.
+func (c *Conn) subnegotiation(opt byte, data []byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch opt {
+	case OptNAWS:
+		if len(data) != 4 {
+			return nil
+		}
+		c.width = int(binary.BigEndian.Uint16(data[0:2]))
+		c.height = int(binary.BigEndian.Uint16(data[2:4]))
+
+	case OptTType:
+		if len(data) < 1 || data[0] != ttypeIs {
+			return nil
+		}
+		name := string(data[1:])
+
+		// MTTS clients answer with their name, then terminal type, then
+		// "MTTS <flags>"; anyone else repeats their last answer at the end
+		if flags, ok := strings.CutPrefix(name, "MTTS "); ok {
+			c.mtts, _ = strconv.Atoi(flags)
+			return nil
+		}
+		if len(c.ttypes) > 0 && c.ttypes[len(c.ttypes)-1] == name {
+			return nil
+		}
+		c.ttypes = append(c.ttypes, name)
+		return c.requestTType()
+
+	case OptGMCP:
+		c.receiveGMCP(data)
+	}
+	return nil
+}
+
+// requestTType returns the request for the next terminal type, or nil once
+// we've asked enough. Callers hold c.mu.
+func (c *Conn) requestTType() []byte {
+	if c.ttypeAsks >= maxTTypeRequests {
+		return nil
+	}
+	c.ttypeAsks++
+	return []byte{IAC, SB, OptTType, ttypeSend, IAC, SE}
+}
+
+func (c *Conn) sendRaw(b ...byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.Conn.Write(b)
+	return err
+}
+
+func (c *Conn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	out := make([]byte, 0, len(p)+len(p)/8)
+	last := c.lastOut
+	for _, b := range p {
+		switch {
+		case b == '\n' && last != '\r':
+			out = append(out, '\r', '\n')
+		case b == IAC:
+			out = append(out, IAC, IAC)
+		default:
+			out = append(out, b)
+		}
+		last = b
+	}
+
+	if _, err := c.Conn.Write(out); err != nil {
+		return 0, err
+	}
+	c.lastOut = last
+	return len(p), nil
+}
@@ -0,0 +1,214 @@
+package telnet
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn plays back what a client sent and records what the server wrote.
+type fakeConn struct {
+	net.Conn
+	in  io.Reader
+	out bytes.Buffer
+}
+
+func (f *fakeConn) Read(p []byte) (int, error)  { return f.in.Read(p) }
+func (f *fakeConn) Write(p []byte) (int, error) { return f.out.Write(p) }
+
+func newTestConn(clientSent ...byte) (*Conn, *fakeConn) {
+	fc := &fakeConn{in: bytes.NewReader(clientSent)}
+	return NewConn(fc), fc
+}
+
+func cat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestConn_Read(t *testing.T) {
+	t.Parallel()
+
+	type tc struct {
+		name       string
+		clientSent []byte
+		wantData   string
+		wantSent   []byte
+		wantOpts   Options
+	}
+
+	cases := []tc{
+		{
+			name:       "plain line",
+			clientSent: []byte("look\r\n"),
+			wantData:   "look\n",
+			wantOpts:   Options{Color: true},
+		},
+		{
+			name:       "CR NUL and bare CR end lines",
+			clientSent: []byte("n\r\x00s\re\n"),
+			wantData:   "n\ns\ne\n",
+			wantOpts:   Options{Color: true},
+		},
+		{
+			name:       "escaped IAC is data",
+			clientSent: []byte{'a', IAC, IAC, 'b'},
+			wantData:   "a\xffb",
+			wantOpts:   Options{Color: true},
+		},
+		{
+			name:       "commands are stripped from data",
+			clientSent: cat([]byte("lo"), []byte{IAC, NOP}, []byte("ok"), []byte{IAC, GA}),
+			wantData:   "look",
+			wantOpts:   Options{Color: true},
+		},
+		{
+			name:       "unsupported options are refused",
+			clientSent: cat([]byte{IAC, WILL, 42, IAC, DO, 42}, []byte("x")),
+			wantData:   "x",
+			wantSent:   []byte{IAC, DONT, 42, IAC, WONT, 42},
+			wantOpts:   Options{Color: true},
+		},
+		{
+			name: "window size",
+			clientSent: cat(
+				[]byte{IAC, WILL, OptNAWS},
+				[]byte{IAC, SB, OptNAWS, 0, 100, 0, 40, IAC, SE},
+				[]byte("x"),
+			),
+			wantData: "x",
+			wantSent: []byte{IAC, DO, OptNAWS},
+			wantOpts: Options{Width: 100, Height: 40, Color: true},
+		},
+		{
+			name: "window size containing IAC",
+			clientSent: cat(
+				[]byte{IAC, SB, OptNAWS, 0, IAC, IAC, 0, 24, IAC, SE},
+				[]byte("x"),
+			),
+			wantData: "x",
+			wantOpts: Options{Width: 255, Height: 24, Color: true},
+		},
+		{
+			name: "terminal type cycle with MTTS",
+			clientSent: cat(
+				[]byte{IAC, WILL, OptTType},
+				[]byte{IAC, SB, OptTType, ttypeIs}, []byte("MUDLET"), []byte{IAC, SE},
+				[]byte{IAC, SB, OptTType, ttypeIs}, []byte("XTERM"), []byte{IAC, SE},
+				[]byte{IAC, SB, OptTType, ttypeIs}, []byte("MTTS 4"), []byte{IAC, SE},
+				[]byte("x"),
+			),
+			wantData: "x",
+			wantSent: cat(
+				[]byte{IAC, DO, OptTType},
+				bytes.Repeat([]byte{IAC, SB, OptTType, ttypeSend, IAC, SE}, 3),
+			),
+			wantOpts: Options{Color: false, ClientName: "MUDLET", TerminalType: "XTERM", MTTS: MTTSUTF8},
+		},
+		{
+			name: "terminal type repeated ends the cycle",
+			clientSent: cat(
+				[]byte{IAC, WILL, OptTType},
+				[]byte{IAC, SB, OptTType, ttypeIs}, []byte("DUMB"), []byte{IAC, SE},
+				[]byte{IAC, SB, OptTType, ttypeIs}, []byte("DUMB"), []byte{IAC, SE},
+				[]byte("x"),
+			),
+			wantData: "x",
+			wantSent: cat(
+				[]byte{IAC, DO, OptTType},
+				bytes.Repeat([]byte{IAC, SB, OptTType, ttypeSend, IAC, SE}, 2),
+			),
+			wantOpts: Options{Color: false, ClientName: "DUMB", TerminalType: "DUMB"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn, fc := newTestConn(c.clientSent...)
+			data, err := io.ReadAll(conn)
+			require.NoError(t, err)
+			require.Equal(t, c.wantData, string(data))
+			require.Equal(t, c.wantSent, fc.out.Bytes())
+			require.Equal(t, c.wantOpts, conn.Options())
+		})
+	}
+}
+
+func TestConn_NegotiatedOptionsAreNotRepeated(t *testing.T) {
+	t.Parallel()
+
+	// a client agreeing to what Negotiate asked for gets no reply, so
+	// neither side keeps answering the other
+	conn, fc := newTestConn(IAC, WILL, OptNAWS, IAC, WILL, OptNAWS)
+	require.NoError(t, conn.Negotiate())
+	_, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, []byte{IAC, DO, OptNAWS, IAC, DO, OptTType, IAC, WILL, OptGMCP}, fc.out.Bytes())
+}
+
+func TestConn_SetEcho(t *testing.T) {
+	t.Parallel()
+
+	conn, fc := newTestConn()
+	require.NoError(t, conn.SetEcho(false))
+	require.NoError(t, conn.SetEcho(false))
+	require.NoError(t, conn.SetEcho(true))
+	require.Equal(t, []byte{IAC, WILL, OptEcho, IAC, WONT, OptEcho}, fc.out.Bytes())
+}
+
+// stuckConn is a client that has stopped reading: writes block until
+// release is closed.
+type stuckConn struct {
+	net.Conn
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (s *stuckConn) Write(p []byte) (int, error) {
+	s.writing <- struct{}{}
+	<-s.release
+	return len(p), nil
+}
+
+func TestConn_SlowClientDoesNotHoldOptions(t *testing.T) {
+	t.Parallel()
+
+	for _, write := range []func(c *Conn){
+		func(c *Conn) { _ = c.SetEcho(false) },
+		func(c *Conn) { c.negotiate(DO, OptGMCP) },
+		func(c *Conn) { c.subnegotiate(OptTType, append([]byte{ttypeIs}, "xterm"...)) },
+	} {
+		sc := &stuckConn{writing: make(chan struct{}), release: make(chan struct{})}
+		conn := NewConn(sc)
+		go write(conn)
+		<-sc.writing
+
+		done := make(chan struct{})
+		go func() {
+			conn.Options()
+			conn.GMCPEnabled()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("a blocked write held up reading the options")
+		}
+		close(sc.release)
+	}
+}
+
+func TestConn_Write(t *testing.T) {
+	t.Parallel()
+
+	conn, fc := newTestConn()
+	n, err := conn.Write([]byte("one\ntwo\r\n\xff"))
+	require.NoError(t, err)
+	require.Equal(t, 10, n)
+	require.Equal(t, []byte("one\r\ntwo\r\n\xff\xff"), fc.out.Bytes())
+}
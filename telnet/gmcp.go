@@ -0,0 +1,114 @@
+package telnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GMCPMessage is one GMCP package and its data, which is sent as JSON.
+type GMCPMessage struct {
+	Package string
+	Data    any
+}
+
+// GMCPEnabled reports whether the client accepted GMCP.
+func (c *Conn) GMCPEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.us[OptGMCP]
+}
+
+// SendGMCP sends messages the client has asked for. State packages (anything
+// but Comm.*) are skipped when their data hasn't changed since they were last
+// sent, so callers can send after every command without flooding the client.
+func (c *Conn) SendGMCP(msgs ...GMCPMessage) error {
+	c.gmcpMu.Lock()
+	defer c.gmcpMu.Unlock()
+
+	c.mu.Lock()
+	var send []GMCPMessage
+	if c.us[OptGMCP] {
+		for _, m := range msgs {
+			if c.gmcpSupported(m.Package) {
+				send = append(send, m)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	for _, m := range send {
+		data, err := json.Marshal(m.Data)
+		if err != nil {
+			return fmt.Errorf("gmcp %s: %w", m.Package, err)
+		}
+
+		if !strings.HasPrefix(m.Package, "Comm.") {
+			if bytes.Equal(c.gmcpLast[m.Package], data) {
+				continue
+			}
+			if c.gmcpLast == nil {
+				c.gmcpLast = make(map[string][]byte)
+			}
+			c.gmcpLast[m.Package] = data
+		}
+
+		frame := make([]byte, 0, len(m.Package)+len(data)+6)
+		frame = append(frame, IAC, SB, OptGMCP)
+		frame = append(frame, m.Package...)
+		frame = append(frame, ' ')
+		frame = append(frame, bytes.ReplaceAll(data, []byte{IAC}, []byte{IAC, IAC})...)
+		frame = append(frame, IAC, SE)
+		if err := c.sendRaw(frame...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gmcpSupported checks a package against the client's Core.Supports list.
+// Clients that never sent one get everything. Callers hold c.mu.
+func (c *Conn) gmcpSupported(pkg string) bool {
+	if c.gmcpSupports == nil {
+		return true
+	}
+
+	// "Char.Items.Inv" is covered by "Char" or "Char.Items"
+	parts := strings.Split(strings.ToLower(pkg), ".")
+	for i := 1; i <= len(parts); i++ {
+		if c.gmcpSupports[strings.Join(parts[:i], ".")] {
+			return true
+		}
+	}
+	return false
+}
+
+// receiveGMCP handles a message from the client. Only Core.Supports matters
+// to us so far. Callers hold c.mu.
+func (c *Conn) receiveGMCP(data []byte) {
+	pkg, payload, _ := strings.Cut(string(data), " ")
+
+	var modules []string
+	switch strings.ToLower(pkg) {
+	case "core.supports.set":
+		c.gmcpSupports = make(map[string]bool)
+		fallthrough
+	case "core.supports.add", "core.supports.remove":
+		if err := json.Unmarshal([]byte(payload), &modules); err != nil {
+			return
+		}
+	default:
+		return
+	}
+
+	if c.gmcpSupports == nil {
+		c.gmcpSupports = make(map[string]bool)
+	}
+	remove := strings.EqualFold(pkg, "core.supports.remove")
+	for _, m := range modules {
+		// entries look like "Char.Items 1"
+		name, _, _ := strings.Cut(m, " ")
+		c.gmcpSupports[strings.ToLower(name)] = !remove
+	}
+}
@@ -0,0 +1,86 @@
+package telnet
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func gmcpFrame(s string) []byte {
+	return cat([]byte{IAC, SB, OptGMCP}, []byte(s), []byte{IAC, SE})
+}
+
+func TestConn_SendGMCP(t *testing.T) {
+	t.Parallel()
+
+	room := GMCPMessage{Package: "Room.Info", Data: map[string]string{"name": "Hall"}}
+	inv := GMCPMessage{Package: "Char.Items.Inv", Data: []string{"sword"}}
+	say := GMCPMessage{Package: "Comm.Channel.Text", Data: "hi"}
+
+	type tc struct {
+		name       string
+		clientSent []byte
+		send       [][]GMCPMessage
+		wantSent   []byte
+	}
+
+	cases := []tc{
+		{
+			name:     "nothing before the client agrees",
+			send:     [][]GMCPMessage{{room}},
+			wantSent: nil,
+		},
+		{
+			name:       "everything when the client lists no packages",
+			clientSent: []byte{IAC, DO, OptGMCP},
+			send:       [][]GMCPMessage{{room, inv}},
+			wantSent: cat(
+				gmcpFrame(`Room.Info {"name":"Hall"}`),
+				gmcpFrame(`Char.Items.Inv ["sword"]`),
+			),
+		},
+		{
+			name: "only packages the client supports",
+			clientSent: cat(
+				[]byte{IAC, DO, OptGMCP},
+				gmcpFrame(`Core.Supports.Set ["Room 1", "Char 1"]`),
+				gmcpFrame(`Core.Supports.Remove ["Char"]`),
+				gmcpFrame(`Core.Supports.Add ["Comm.Channel 1"]`),
+			),
+			send: [][]GMCPMessage{{room, inv, say}},
+			wantSent: cat(
+				gmcpFrame(`Room.Info {"name":"Hall"}`),
+				gmcpFrame(`Comm.Channel.Text "hi"`),
+			),
+		},
+		{
+			name:       "unchanged state is not resent",
+			clientSent: []byte{IAC, DO, OptGMCP},
+			send:       [][]GMCPMessage{{room, say}, {room, say}},
+			wantSent: cat(
+				gmcpFrame(`Room.Info {"name":"Hall"}`),
+				gmcpFrame(`Comm.Channel.Text "hi"`),
+				gmcpFrame(`Comm.Channel.Text "hi"`),
+			),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn, fc := newTestConn(c.clientSent...)
+			require.NoError(t, conn.Negotiate())
+			_, err := io.ReadAll(conn)
+			require.NoError(t, err)
+
+			// only what SendGMCP wrote, not the negotiation
+			fc.out.Reset()
+			for _, msgs := range c.send {
+				require.NoError(t, conn.SendGMCP(msgs...))
+			}
+			require.Equal(t, string(c.wantSent), fc.out.String())
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package telnet speaks enough of the telnet protocol (RFC 854) for MUD
+// clients: option negotiation, window size (NAWS), terminal type (TTYPE and
+// MTTS), hiding input with ECHO, and out-of-band data with GMCP.
+package telnet
+
+// commands
+const (
+	SE   byte = 240
+	NOP  byte = 241
+	GA   byte = 249
+	SB   byte = 250
+	WILL byte = 251
+	WONT byte = 252
+	DO   byte = 253
+	DONT byte = 254
+	IAC  byte = 255
+)
+
+// options
+const (
+	OptEcho  byte = 1
+	OptSGA   byte = 3
+	OptTType byte = 24
+	OptNAWS  byte = 31
+	OptGMCP  byte = 201
+)
+
+// TTYPE subnegotiation
+const (
+	ttypeIs   byte = 0
+	ttypeSend byte = 1
+)
+
+// MTTS flags sent by clients as "MTTS <n>" in the terminal type cycle, see
+// https://tintin.mudhalla.net/protocols/mtts/
+const (
+	MTTSANSI          = 1
+	MTTSVT100         = 2
+	MTTSUTF8          = 4
+	MTTS256Colors     = 8
+	MTTSMouseTracking = 16
+	MTTSOSCColor      = 32
+	MTTSScreenReader  = 64
+	MTTSProxy         = 128
+	MTTSTrueColor     = 256
+)
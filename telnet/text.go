@@ -0,0 +1,92 @@
+package telnet
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Options is what a client has told us about itself. Width and Height are 0
+// until the client reports its window size.
+type Options struct {
+	Width        int
+	Height       int
+	Color        bool
+	ClientName   string
+	TerminalType string
+	MTTS         int
+}
+
+// Format fits text to the client: styles are stripped if it has no color,
+// and lines are wrapped to its width.
+func (o Options) Format(s string) string {
+	if !o.Color {
+		s = StripANSI(s)
+	}
+	if o.Width > 0 {
+		s = Wrap(s, o.Width)
+	}
+	return s
+}
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// StripANSI removes ANSI color and style codes.
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// visibleLen counts the runes in s that take up space on screen.
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(StripANSI(s))
+}
+
+// Wrap breaks lines longer than width at spaces. Continuation lines keep the
+// indentation of the line they came from, and words longer than width are
+// left whole.
+func Wrap(s string, width int) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if visibleLen(line) > width {
+			lines[i] = wrapLine(line, width)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func wrapLine(line string, width int) string {
+	body := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(body)]
+	if len(indent) >= width/2 {
+		// deep indentation would leave no room for words
+		indent = ""
+	}
+
+	var b strings.Builder
+	b.WriteString(indent)
+	col := len(indent)
+	atLineStart := true
+
+	for _, word := range strings.Split(body, " ") {
+		if word == "" {
+			continue
+		}
+		wl := visibleLen(word)
+
+		if !atLineStart && col+1+wl > width {
+			b.WriteByte('\n')
+			b.WriteString(indent)
+			col = len(indent)
+			atLineStart = true
+		}
+		if !atLineStart {
+			b.WriteByte(' ')
+			col++
+		}
+		b.WriteString(word)
+		col += wl
+		atLineStart = false
+	}
+
+	return b.String()
+}
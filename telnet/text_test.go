@@ -0,0 +1,66 @@
+package telnet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Format(t *testing.T) {
+	t.Parallel()
+
+	const red, reset = "\x1b[31m", "\x1b[0m"
+
+	type tc struct {
+		name string
+		opts Options
+		in   string
+		want string
+	}
+
+	cases := []tc{
+		{
+			name: "unknown width leaves lines alone",
+			opts: Options{Color: true},
+			in:   "a fairly long line of text",
+			want: "a fairly long line of text",
+		},
+		{
+			name: "wraps at spaces",
+			opts: Options{Width: 10, Color: true},
+			in:   "a fairly long line of text",
+			want: "a fairly\nlong line\nof text",
+		},
+		{
+			name: "keeps indentation and existing newlines",
+			opts: Options{Width: 12, Color: true},
+			in:   "Title\n  a couch rests here\nExits: north",
+			want: "Title\n  a couch\n  rests here\nExits: north",
+		},
+		{
+			name: "styles take no width",
+			opts: Options{Width: 12, Color: true},
+			in:   "a " + red + "red" + reset + " nickel here",
+			want: "a " + red + "red" + reset + " nickel\nhere",
+		},
+		{
+			name: "no color strips styles",
+			opts: Options{Color: false},
+			in:   "a " + red + "red" + reset + " nickel",
+			want: "a red nickel",
+		},
+		{
+			name: "long words are not broken",
+			opts: Options{Width: 5, Color: true},
+			in:   "a supercalifragilistic word",
+			want: "a\nsupercalifragilistic\nword",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.want, c.opts.Format(c.in))
+		})
+	}
+}
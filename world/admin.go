@@ -0,0 +1,462 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/mud/account"
+	"example.com/mud/models"
+	"example.com/mud/parser"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// Operator carries out the admin commands that reach past the world, to
+// connections, accounts and the server itself. The session manager is the
+// world's operator.
+type Operator interface {
+	// AccountRole returns the role of an account, whether or not it's online.
+	AccountRole(name string) (models.Role, error)
+	SetRole(name string, role models.Role) error
+	SetBanned(name string, banned bool) error
+
+	// PublicKeys, AddPublicKey and RemovePublicKey manage the keys an account
+	// can log in over SSH with. RemovePublicKey counts from zero.
+	PublicKeys(name string) ([]string, error)
+	AddPublicKey(name, key string) error
+	RemovePublicKey(name string, i int) error
+
+	// Kick disconnects a player and takes them out of the world, telling them
+	// why. It reports whether they were in the world.
+	Kick(name, reason string) bool
+
+	// Force tells the player notice, then runs line as though they'd typed
+	// it. It reports whether they have a connection to run it for.
+	Force(name, notice, line string) bool
+
+	// Shutdown stops the server after delay, replacing any shutdown already
+	// coming. CancelShutdown reports whether there was one to cancel.
+	Shutdown(delay time.Duration)
+	CancelShutdown() bool
+}
+
+func (w *World) SetOperator(op Operator) {
+	w.operator = op
+}
+
+// SetAdmins sets the accounts that are admins whatever role their account
+// has, so a new server has someone to hand out roles.
+func (w *World) SetAdmins(names []string) {
+	admins := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		admins[strings.ToLower(name)] = struct{}{}
+	}
+	w.admins = admins
+}
+
+// RoleOf returns what the player is allowed to do.
+func (w *World) RoleOf(p *player.Player) models.Role {
+	if _, ok := w.admins[strings.ToLower(p.Name)]; ok {
+		return models.RoleAdmin
+	}
+	return p.Role()
+}
+
+// accountRole returns the role of an account, whether or not it's online.
+func (w *World) accountRole(name string) (models.Role, error) {
+	if p, ok := w.FindPlayer(name); ok {
+		return w.RoleOf(p), nil
+	}
+	if _, ok := w.admins[strings.ToLower(name)]; ok {
+		return models.RoleAdmin, nil
+	}
+	return w.operator.AccountRole(name)
+}
+
+func (w *World) adminCommand(p *player.Player, cmd *models.Command, line string) (response.Response, error) {
+	switch cmd.Kind {
+	case "goto":
+		return w.gotoCommand(p, restOfLine(line, 1))
+	case "transfer":
+		return w.transferCommand(p, cmd.Params["player"]), nil
+	case "broadcast":
+		w.broadcast(fmt.Sprintf("%s announces: %s", p.Name, restOfLine(line, 1)))
+		return response.Text{}, nil
+	case "stat":
+		return w.statCommand(p, restOfLine(line, 1)), nil
+	case "purge":
+		return w.purgeCommand(p, restOfLine(line, 1)), nil
+	}
+
+	if w.operator == nil {
+		return response.Text{Value: "There's no server here to do that."}, nil
+	}
+	switch cmd.Kind {
+	case "kick":
+		return w.kickCommand(p, cmd.Params["player"], restOfLine(line, 2)), nil
+	case "ban":
+		return w.banCommand(p, cmd.Params["player"], true), nil
+	case "unban":
+		return w.banCommand(p, cmd.Params["player"], false), nil
+	case "role":
+		return w.roleCommand(p, cmd.Params["player"], cmd.Params["role"]), nil
+	case "force":
+		return w.forceCommand(p, cmd.Params["player"], restOfLine(line, 2)), nil
+	case "shutdown":
+		return w.shutdownCommand(cmd.Params["delay"]), nil
+	}
+	return nil, fmt.Errorf("unknown admin command '%s'", cmd.Kind)
+}
+
+// broadcast tells every player online something from the server.
+func (w *World) broadcast(text string) {
+	for _, p := range w.OnlinePlayers() {
+		w.PublishMessageTo(p.CurrentRoom(), p.Entity, response.System(text))
+	}
+}
+
+// TellAdmins tells the admins online something from the server.
+func (w *World) TellAdmins(text string) {
+	for _, p := range w.OnlinePlayers() {
+		if w.RoleOf(p).Allows(models.RoleAdmin) {
+			w.PublishMessageTo(p.CurrentRoom(), p.Entity, response.System(text))
+		}
+	}
+}
+
+func noOneCalled(name string) response.Text {
+	return response.Text{Value: fmt.Sprintf("There's no one called %s online.", name)}
+}
+
+func (w *World) gotoCommand(p *player.Player, name string) (response.Response, error) {
+	e, ok := w.findEntity(p, name)
+	if ok {
+		e, ok = roomOf(e)
+	}
+	if !ok {
+		return response.Text{Value: fmt.Sprintf("There's no room, player or thing called %s.", name)}, nil
+	}
+	if e == p.CurrentRoom() {
+		return response.Text{Value: "You're already there."}, nil
+	}
+
+	w.relocate(p, e, "%s vanishes in a puff of smoke.", "%s appears in a puff of smoke.")
+	return p.GetRoomDescription()
+}
+
+func (w *World) transferCommand(p *player.Player, name string) response.Response {
+	target, ok := w.FindPlayer(name)
+	if !ok {
+		return noOneCalled(name)
+	}
+	if target.CurrentRoom() == p.CurrentRoom() {
+		return response.Text{Value: fmt.Sprintf("%s is already here.", target.Name)}
+	}
+
+	w.relocate(target, p.CurrentRoom(), "%s is whisked away.", "%s appears, looking bewildered.")
+	w.PublishTo(target.CurrentRoom(), target.Entity, fmt.Sprintf("%s has summoned you.", p.Name))
+	if room, err := target.GetRoomDescription(); err == nil {
+		w.PublishTo(target.CurrentRoom(), target.Entity, room.String())
+	}
+	return response.Text{Value: fmt.Sprintf("You bring %s here.", target.Name)}
+}
+
+func (w *World) kickCommand(p *player.Player, name, reason string) response.Response {
+	target, ok := w.FindPlayer(name)
+	if !ok {
+		return noOneCalled(name)
+	}
+	if !w.RoleOf(p).Outranks(w.RoleOf(target)) {
+		return response.Text{Value: fmt.Sprintf("You can't kick %s.", target.Name)}
+	}
+
+	why := fmt.Sprintf("%s has kicked you out.", p.Name)
+	if reason != "" {
+		why = fmt.Sprintf("%s has kicked you out: %s", p.Name, reason)
+	}
+	w.operator.Kick(target.Name, why)
+	return response.Text{Value: fmt.Sprintf("You kick %s out.", target.Name)}
+}
+
+func (w *World) banCommand(p *player.Player, name string, banned bool) response.Response {
+	target, online := w.FindPlayer(name)
+	if online {
+		name = target.Name
+	}
+	role, err := w.accountRole(name)
+	if errors.Is(err, account.ErrNotFound) {
+		return response.Text{Value: fmt.Sprintf("There's no account called %s.", name)}
+	} else if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+	if banned && !w.RoleOf(p).Outranks(role) {
+		return response.Text{Value: fmt.Sprintf("You can't ban %s.", name)}
+	}
+
+	if err := w.operator.SetBanned(name, banned); err != nil {
+		return response.Text{Value: err.Error()}
+	}
+	if !banned {
+		return response.Text{Value: fmt.Sprintf("%s can log in again.", name)}
+	}
+	if online {
+		w.operator.Kick(target.Name, fmt.Sprintf("%s has banned you.", p.Name))
+	}
+	return response.Text{Value: fmt.Sprintf("You ban %s.", name)}
+}
+
+func (w *World) roleCommand(p *player.Player, name, roleName string) response.Response {
+	role, err := models.ParseRole(roleName)
+	if err != nil {
+		return response.Text{Value: fmt.Sprintf("Roles are %s, %s and %s.", models.RolePlayer, models.RoleBuilder, models.RoleAdmin)}
+	}
+	target, online := w.FindPlayer(name)
+	if online {
+		name = target.Name
+	}
+	current, err := w.accountRole(name)
+	if errors.Is(err, account.ErrNotFound) {
+		return response.Text{Value: fmt.Sprintf("There's no account called %s.", name)}
+	} else if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+	if !strings.EqualFold(name, p.Name) && !w.RoleOf(p).Outranks(current) {
+		return response.Text{Value: fmt.Sprintf("You can't change %s's role.", name)}
+	}
+
+	if err := w.operator.SetRole(name, role); err != nil {
+		return response.Text{Value: err.Error()}
+	}
+	if online {
+		target.SetRole(role)
+		if target != p {
+			w.PublishTo(target.CurrentRoom(), target.Entity, fmt.Sprintf("%s has made you %s.", p.Name, role))
+		}
+	}
+	return response.Text{Value: fmt.Sprintf("%s is now %s.", name, role)}
+}
+
+func (w *World) forceCommand(p *player.Player, name, command string) response.Response {
+	target, ok := w.FindPlayer(name)
+	if !ok {
+		return noOneCalled(name)
+	}
+	if !w.RoleOf(p).Outranks(w.RoleOf(target)) {
+		return response.Text{Value: fmt.Sprintf("You can't force %s.", target.Name)}
+	}
+	// a key added for them would let whoever forced it log in as them
+	if forced := parser.Parse(command); forced != nil && forced.Kind == "sshkey" {
+		return response.Text{Value: "You can't make anyone manage their SSH keys."}
+	}
+
+	if !w.operator.Force(target.Name, fmt.Sprintf("%s makes you: %s", p.Name, command), command) {
+		return response.Text{Value: fmt.Sprintf("%s is link-dead.", target.Name)}
+	}
+	return response.Text{Value: fmt.Sprintf("You make %s: %s", target.Name, command)}
+}
+
+func (w *World) shutdownCommand(delay string) response.Response {
+	if delay == "cancel" {
+		if !w.operator.CancelShutdown() {
+			return response.Text{Value: "There's no shutdown coming."}
+		}
+		w.broadcast("The shutdown has been called off.")
+		return response.Text{}
+	}
+
+	seconds := 0
+	if delay != "" {
+		n, err := strconv.Atoi(delay)
+		if err != nil || n < 0 {
+			return response.Text{Value: `Give the delay in seconds, or "cancel".`}
+		}
+		seconds = n
+	}
+
+	d := time.Duration(seconds) * time.Second
+	if d == 0 {
+		w.broadcast("The server is shutting down now.")
+	} else {
+		w.broadcast(fmt.Sprintf("The server will shut down in %s.", d))
+	}
+	w.operator.Shutdown(d)
+	return response.Text{}
+}
+
+func (w *World) statCommand(p *player.Player, name string) response.Response {
+	e, ok := w.findEntity(p, name)
+	if !ok {
+		return response.Text{Value: fmt.Sprintf("There's nothing called %s.", name)}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", e.Name)
+	fmt.Fprintf(&b, "  ID: %s (template %s)\n", e.ID, e.TemplateID)
+	if e.Parent != nil && e.Parent.Owner() != nil {
+		owner := e.Parent.Owner()
+		fmt.Fprintf(&b, "  In: %s (%s)\n", owner.Name, owner.ID)
+	}
+	fmt.Fprintf(&b, "  Description: %s\n", strings.TrimSpace(e.Description))
+	fmt.Fprintf(&b, "  Aliases: %s\n", strings.Join(e.Aliases, ", "))
+	fmt.Fprintf(&b, "  Tags: %s\n", strings.Join(e.Tags, ", "))
+
+	b.WriteString("  Fields:\n")
+	for _, field := range slices.Sorted(maps.Keys(e.Fields)) {
+		fmt.Fprintf(&b, "    %s = %s\n", field, formatValue(e.Fields[field]))
+	}
+	b.WriteString("  Components:\n")
+	for _, c := range e.Components() {
+		fmt.Fprintf(&b, "    %s\n", describeComponent(c))
+	}
+	return response.Text{Value: strings.TrimRight(b.String(), "\n")}
+}
+
+func describeComponent(c entities.Component) string {
+	var details []string
+	switch v := c.(type) {
+	case *components.Room:
+		exits := v.Exits()
+		for _, dir := range slices.Sorted(maps.Keys(exits)) {
+			details = append(details, fmt.Sprintf("%s to %s", dir, exits[dir]))
+		}
+	case *components.Eventful:
+		details = append(details, "handles "+strings.Join(slices.Sorted(maps.Keys(v.Rules)), ", "))
+	}
+	if cwc, ok := c.(entities.ComponentWithChildren); ok {
+		var names []string
+		for _, child := range cwc.GetChildren().GetChildren() {
+			names = append(names, child.Name)
+		}
+		details = append(details, fmt.Sprintf("holds [%s]", strings.Join(names, ", ")))
+	}
+
+	if len(details) == 0 {
+		return c.Id().String()
+	}
+	return fmt.Sprintf("%s: %s", c.Id(), strings.Join(details, "; "))
+}
+
+func formatValue(v models.Value) string {
+	switch v.K {
+	case models.KindInt:
+		return strconv.Itoa(v.I)
+	case models.KindString:
+		return strconv.Quote(v.S)
+	case models.KindBool:
+		return strconv.FormatBool(v.B)
+	case models.KindIntList:
+		return fmt.Sprint(v.IL)
+	case models.KindStringList:
+		return fmt.Sprintf("%q", v.SL)
+	case models.KindBoolList:
+		return fmt.Sprint(v.BL)
+	}
+	return "nil"
+}
+
+func (w *World) purgeCommand(p *player.Player, name string) response.Response {
+	if name == "" {
+		room, err := entities.RequireComponent[*components.Room](p.CurrentRoom())
+		if err != nil {
+			return response.Text{Value: err.Error()}
+		}
+		purged := 0
+		for _, e := range slices.Clone(room.GetChildren().GetChildren()) {
+			if !w.isPlayer(e) {
+				w.destroy(e)
+				purged++
+			}
+		}
+		if purged == 0 {
+			return response.Text{Value: "There's nothing here to purge."}
+		}
+		w.Publish(p.CurrentRoom(), fmt.Sprintf("%s purges the room.", p.Name), []*entities.Entity{p.Entity})
+		if purged == 1 {
+			return response.Text{Value: "You purge 1 thing."}
+		}
+		return response.Text{Value: fmt.Sprintf("You purge %d things.", purged)}
+	}
+
+	e, ok := w.findEntity(p, name)
+	switch {
+	case !ok:
+		return response.Text{Value: fmt.Sprintf("There's nothing called %s.", name)}
+	case w.isPlayer(e):
+		return response.Text{Value: "You can't purge players, kick them instead."}
+	}
+	if _, ok := entities.GetComponent[*components.Room](e); ok {
+		return response.Text{Value: "You can't purge a room."}
+	}
+	if e.Parent == nil {
+		// templates aren't in the world, only copies of them are
+		return response.Text{Value: fmt.Sprintf("There's no %s here.", name)}
+	}
+
+	w.destroy(e)
+	w.Publish(p.CurrentRoom(), fmt.Sprintf("%s purges %s.", p.Name, e.Name), []*entities.Entity{p.Entity})
+	return response.Text{Value: fmt.Sprintf("You purge %s.", e.Name)}
+}
+
+// destroy takes e, and everything it holds, out of the world.
+func (w *World) destroy(e *entities.Entity) {
+	e.Parent.RemoveChild(e)
+	w.Unregister(e)
+}
+
+func (w *World) isPlayer(e *entities.Entity) bool {
+	return slices.ContainsFunc(w.OnlinePlayers(), func(p *player.Player) bool { return p.Entity == e })
+}
+
+// findEntity finds what a builder means by name: "here", "me", something in
+// their room or inventory by alias, or anything by ID or player name. IDs
+// match in any case, since the parser lower-cases what it's given.
+func (w *World) findEntity(p *player.Player, name string) (*entities.Entity, bool) {
+	alias := strings.ToLower(name)
+	switch alias {
+	case "":
+		return nil, false
+	case "here":
+		return p.CurrentRoom(), true
+	case "me", "self":
+		return p.Entity, true
+	}
+
+	var nearby []entities.ComponentWithChildren
+	if room, ok := entities.GetComponent[*components.Room](p.CurrentRoom()); ok {
+		nearby = append(nearby, room)
+	}
+	if inventory, ok := entities.GetComponent[*components.Inventory](p.Entity); ok {
+		nearby = append(nearby, inventory)
+	}
+	for _, cwc := range nearby {
+		if matches := cwc.GetChildren().GetChildrenByAlias(alias); len(matches) > 0 {
+			return matches[0].Entity, true
+		}
+	}
+
+	if e, ok := w.ResolveEntity(name); ok {
+		return e, true
+	}
+	return w.templateNamed(name)
+}
+
+// roomOf returns the room e is in, or e itself if it's a room.
+func roomOf(e *entities.Entity) (*entities.Entity, bool) {
+	for e != nil {
+		if _, ok := entities.GetComponent[*components.Room](e); ok {
+			return e, true
+		}
+		if e.Parent == nil {
+			return nil, false
+		}
+		e = e.Parent.Owner()
+	}
+	return nil, false
+}
@@ -0,0 +1,172 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/account"
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/response"
+	"example.com/mud/world/worldtest"
+)
+
+var registerOnce sync.Once
+
+// fakeOperator records what the admin commands asked of it.
+type fakeOperator struct {
+	roles  map[string]models.Role // lower-case account name
+	banned map[string]bool
+	kicked []string
+	forced []string
+	keys   map[string][]string
+}
+
+func (o *fakeOperator) AccountRole(name string) (models.Role, error) {
+	role, ok := o.roles[strings.ToLower(name)]
+	if !ok {
+		return "", account.ErrNotFound
+	}
+	return role, nil
+}
+
+func (o *fakeOperator) SetRole(name string, role models.Role) error {
+	o.roles[strings.ToLower(name)] = role
+	return nil
+}
+
+func (o *fakeOperator) SetBanned(name string, banned bool) error {
+	o.banned[strings.ToLower(name)] = banned
+	return nil
+}
+
+func (o *fakeOperator) PublicKeys(name string) ([]string, error) {
+	return o.keys[strings.ToLower(name)], nil
+}
+
+func (o *fakeOperator) AddPublicKey(name, key string) error {
+	if !strings.HasPrefix(key, "ssh-") {
+		return account.ErrBadPublicKey
+	}
+	o.keys[strings.ToLower(name)] = append(o.keys[strings.ToLower(name)], key)
+	return nil
+}
+
+func (o *fakeOperator) RemovePublicKey(name string, i int) error {
+	keys := o.keys[strings.ToLower(name)]
+	if i < 0 || i >= len(keys) {
+		return fmt.Errorf("no key %d", i+1)
+	}
+	o.keys[strings.ToLower(name)] = slices.Delete(keys, i, i+1)
+	return nil
+}
+
+func (o *fakeOperator) Kick(name, reason string) bool {
+	o.kicked = append(o.kicked, name+": "+reason)
+	return true
+}
+
+func (o *fakeOperator) Force(name, notice, line string) bool {
+	o.forced = append(o.forced, name+": "+line)
+	return true
+}
+
+func (o *fakeOperator) Shutdown(time.Duration) {}
+func (o *fakeOperator) CancelShutdown() bool   { return false }
+
+func TestWorld_AdminCommands(t *testing.T) {
+	t.Parallel()
+	registerOnce.Do(func() { require.NoError(t, commands.RegisterBuiltInCommands()) })
+
+	hall := worldtest.Room("Hall", map[string]string{"north": "Tower"})
+	tower := worldtest.Room("Tower", map[string]string{"south": "Hall"})
+	w := NewWorld(worldtest.Entities(hall, tower), "Hall")
+	w.SetAdmins([]string{"Alice"})
+	op := &fakeOperator{
+		roles:  map[string]models.Role{"bob": models.RoleBuilder, "carol": models.RolePlayer, "zed": models.RolePlayer},
+		banned: map[string]bool{},
+		keys:   map[string][]string{},
+	}
+	w.SetOperator(op)
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		p, err := w.AddPlayer(name, NewOutbox(OutboxOptions{}, nil))
+		require.NoError(t, err)
+		if role, ok := op.roles[strings.ToLower(name)]; ok {
+			p.SetRole(role)
+		}
+	}
+	lamp := entities.NewEntity("Lamp", "A lamp.", []string{"lamp"}, nil, map[string]models.Value{"lit": models.VBool(true)}, nil)
+	lamp.TemplateID = "Lamp"
+	room, err := tower.RequireComponentWithChildren(entities.ComponentRoom)
+	require.NoError(t, err)
+	require.NoError(t, room.AddChild(lamp))
+	w.Register(lamp)
+
+	steps := []struct {
+		name   string
+		player string
+		line   string
+		want   string
+	}{
+		{name: "players can't use admin commands", player: "Carol", line: "kick bob", want: "What in the nine hells?"},
+		{name: "builders can't use admin-only commands", player: "Bob", line: "kick carol", want: "What in the nine hells?"},
+		{name: "help leaves out what you can't use", player: "Carol", line: "help kick", want: "Unrecognized command: kick"},
+		{name: "goto a room in any case", player: "Bob", line: "goto TOWER", want: "Tower"},
+		{name: "goto a player", player: "Bob", line: "goto alice", want: "Hall"},
+		{name: "stat by ID", player: "Bob", line: "stat tower", want: "Tower\n  ID: " + tower.ID + " (template Tower)"},
+		{name: "stat fields and components", player: "Alice", line: "stat " + lamp.ID, want: "  In: Tower (" + tower.ID + ")"},
+		{name: "goto another room", player: "Alice", line: "goto tower", want: "Tower"},
+		{name: "transfer", player: "Alice", line: "transfer carol", want: "You bring Carol here."},
+		{name: "no transferring someone already here", player: "Alice", line: "transfer carol", want: "Carol is already here."},
+		{name: "kick", player: "Alice", line: "kick bob Being Rude", want: "You kick Bob out."},
+		{name: "make an admin", player: "Alice", line: "role bob admin", want: "Bob is now admin."},
+		{name: "no kicking an equal", player: "Alice", line: "kick bob", want: "You can't kick Bob."},
+		{name: "ban an offline account", player: "Alice", line: "ban zed", want: "You ban zed."},
+		{name: "ban an unknown account", player: "Alice", line: "ban nobody", want: "There's no account called nobody."},
+		{name: "force", player: "Alice", line: "force carol look", want: "You make Carol: look"},
+		{name: "no forcing key changes", player: "Alice", line: "force carol sshkey add ssh-ed25519 AAAA", want: "You can't make anyone manage their SSH keys."},
+		{name: "no keys yet", player: "Carol", line: "sshkey", want: "You have no SSH keys."},
+		{name: "add a key", player: "Carol", line: "sshkey add ssh-ed25519 AAAAC3Nz carol@home", want: "Key added."},
+		{name: "add a bad key", player: "Carol", line: "sshkey add hunter2", want: "That doesn't look like a public key."},
+		{name: "list keys", player: "Carol", line: "sshkey list", want: "Your SSH keys:\n  1) ssh-ed25519 AAAAC3Nz carol@home"},
+		{name: "remove a key that isn't there", player: "Carol", line: "sshkey remove 2", want: "error received: no key 2"},
+		{name: "remove a key", player: "Carol", line: "sshkey remove 1", want: "Key removed."},
+		{name: "purge by ID", player: "Alice", line: "purge " + lamp.ID, want: "You purge Lamp."},
+		{name: "nothing left to purge", player: "Alice", line: "purge", want: "There's nothing here to purge."},
+		{name: "no purging players", player: "Alice", line: "purge carol", want: "You can't purge players, kick them instead."},
+	}
+
+	for _, step := range steps {
+		p, ok := w.FindPlayer(step.player)
+		require.True(t, ok)
+		resp, err := w.Parse(p, step.line)
+		require.NoError(t, err, step.name)
+
+		var text string
+		switch r := resp.(type) {
+		case response.Text:
+			text = r.Value
+		case response.RoomDescription:
+			text = r.ID
+		}
+		require.Contains(t, text, step.want, step.name)
+	}
+
+	require.Equal(t, []string{"Bob: Alice has kicked you out: Being Rude"}, op.kicked)
+	require.Equal(t, []string{"Carol: look"}, op.forced)
+	require.Equal(t, map[string]bool{"zed": true}, op.banned)
+	require.Empty(t, op.keys["carol"])
+	bob, _ := w.FindPlayer("Bob")
+	require.Equal(t, models.RoleAdmin, bob.Role())
+	require.Nil(t, lamp.Parent, "purged things leave the world")
+	_, ok := w.GetEntityByInstanceID(lamp.ID)
+	require.False(t, ok)
+}
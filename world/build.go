@@ -0,0 +1,400 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// SourceWriter writes builders' changes back to where the world was loaded
+// from, so they're still there after a restart. Entities are named by the
+// template IDs they're declared under. The DSL writer is the only one.
+type SourceWriter interface {
+	// AddEntity declares a new entity, built in game.
+	AddEntity(e *entities.Entity) error
+	// SetField sets one of an entity's fields, including its name,
+	// description, aliases and tags.
+	SetField(templateID, key string, value models.Value) error
+	SetComponentField(templateID string, component entities.ComponentType, key string, value models.Value) error
+	SetExits(templateID string, exits map[string]string) error
+	// AddChild adds childID to the children an entity's component starts
+	// out with.
+	AddChild(templateID string, component entities.ComponentType, childID string) error
+}
+
+var errNoSource = errors.New("no source to write to")
+
+var identifierPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func (w *World) SetSourceWriter(source SourceWriter) {
+	w.source = source
+}
+
+func (w *World) buildCommand(p *player.Player, cmd *models.Command, line string) response.Response {
+	switch cmd.Kind {
+	case "dig":
+		return w.digCommand(p, cmd.Params["direction"], restOfLine(line, 2))
+	case "redit":
+		return w.reditCommand(p, cmd.Params["field"], restOfLine(line, 2))
+	case "ocreate":
+		return w.ocreateCommand(p, cmd.Params["template"])
+	}
+
+	// the -template patterns call what they edit "template", not "entity"
+	name, skip, toTemplate := cmd.Params["entity"], 2, false
+	if template, ok := cmd.Params["template"]; ok {
+		name, skip, toTemplate = template, 3, true
+	}
+	switch cmd.Kind {
+	case "oset":
+		return w.osetCommand(p, name, cmd.Params["field"], restOfLine(line, skip+1), toTemplate)
+	case "tag", "untag":
+		return w.tagCommand(p, name, restOfLine(line, skip), cmd.Kind == "tag", toTemplate)
+	case "alias", "unalias":
+		return w.aliasCommand(p, name, strings.ToLower(restOfLine(line, skip)), cmd.Kind == "alias", toTemplate)
+	}
+	return response.Text{Value: fmt.Sprintf("unknown build command '%s'", cmd.Kind)}
+}
+
+// save writes a change back to the world's source.
+func (w *World) save(change func(s SourceWriter) error) error {
+	if w.source == nil {
+		return errNoSource
+	}
+	return change(w.source)
+}
+
+// built tells a builder what they did, and whether it was saved.
+func built(done string, err error) response.Response {
+	switch {
+	case errors.Is(err, errNoSource):
+		done += " This world isn't loaded from DSL files, so it won't outlast a restart."
+	case err != nil:
+		fmt.Println(err)
+		done += fmt.Sprintf(" It couldn't be saved: %v", err)
+	}
+	return response.Text{Value: done}
+}
+
+// addTemplate adds a new entity to the entity map. The map is replaced rather
+// than changed, since it's read without holding the lock.
+func (w *World) addTemplate(e *entities.Entity) {
+	w.entityMu.Lock()
+	defer w.entityMu.Unlock()
+	entityMap := maps.Clone(w.entityMap)
+	entityMap[e.TemplateID] = e
+	w.entityMap = entityMap
+}
+
+// setExit points one of a room's exits at the room with the given ID.
+func setExit(room *components.Room, direction, id string) map[string]string {
+	exits := maps.Clone(room.Exits())
+	if exits == nil {
+		exits = map[string]string{}
+	}
+	exits[direction] = id
+	room.SetExits(exits)
+	return exits
+}
+
+func (w *World) digCommand(p *player.Player, direction, id string) response.Response {
+	direction, ok := commands.DirectionAliases[direction]
+	if !ok {
+		return response.Text{Value: "Dig north, east, south, west, up or down."}
+	}
+	back := models.OppositeDirections[direction]
+	here, err := entities.RequireComponent[*components.Room](p.CurrentRoom())
+	if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+	if _, taken := here.Exits()[direction]; taken {
+		return response.Text{Value: fmt.Sprintf("There's already an exit %s.", direction)}
+	}
+
+	var saveErrs []error
+	target, exists := w.templateNamed(id)
+	if exists {
+		id = target.TemplateID
+		if target == p.CurrentRoom() {
+			return response.Text{Value: "You can't dig to the room you're in."}
+		}
+		room, ok := entities.GetComponent[*components.Room](target)
+		if !ok {
+			return response.Text{Value: fmt.Sprintf("%s isn't a room.", id)}
+		}
+		if _, taken := room.Exits()[back]; taken {
+			return response.Text{Value: fmt.Sprintf("%s already has an exit %s.", target.Name, back)}
+		}
+		exits := setExit(room, back, p.CurrentRoom().TemplateID)
+		saveErrs = append(saveErrs, w.save(func(s SourceWriter) error { return s.SetExits(id, exits) }))
+	} else {
+		if !identifierPattern.MatchString(id) {
+			return response.Text{Value: "Room IDs are letters, digits and underscores, and don't start with a digit."}
+		}
+		target = entities.NewEntity(id, "An unfinished room.", []string{"room"}, []string{"room"}, map[string]models.Value{}, nil)
+		target.TemplateID = id
+		room := components.NewRoom()
+		room.GetChildren().SetPrefix("In the room")
+		room.SetExits(map[string]string{back: p.CurrentRoom().TemplateID})
+		target.Add(room)
+
+		w.addTemplate(target)
+		w.Register(target)
+		saveErrs = append(saveErrs, w.save(func(s SourceWriter) error { return s.AddEntity(target) }))
+	}
+
+	exits := setExit(here, direction, id)
+	saveErrs = append(saveErrs, w.save(func(s SourceWriter) error { return s.SetExits(p.CurrentRoom().TemplateID, exits) }))
+	w.Publish(p.CurrentRoom(), fmt.Sprintf("%s opens up a way %s.", p.Name, direction), []*entities.Entity{p.Entity})
+
+	done := fmt.Sprintf("You dig %s to %s.", direction, id)
+	if !exists {
+		done = fmt.Sprintf("You dig %s, making %s.", direction, id)
+	}
+	return built(done, errors.Join(saveErrs...))
+}
+
+func (w *World) reditCommand(p *player.Player, field, value string) response.Response {
+	e := p.CurrentRoom()
+	room, err := entities.RequireComponent[*components.Room](e)
+	if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+
+	var save func(s SourceWriter) error
+	switch field {
+	case "name", "description":
+		if err := e.SetField(field, models.VStr(value)); err != nil {
+			return response.Text{Value: err.Error()}
+		}
+		save = func(s SourceWriter) error { return s.SetField(e.TemplateID, field, models.VStr(value)) }
+	case "icon", "color":
+		if field == "icon" {
+			room.MapIcon = value
+		} else {
+			room.MapColor = value
+		}
+		save = func(s SourceWriter) error {
+			return s.SetComponentField(e.TemplateID, entities.ComponentRoom, field, models.VStr(value))
+		}
+	default:
+		return response.Text{Value: "You can change a room's name, description, icon or color."}
+	}
+
+	done := fmt.Sprintf("The room's %s is now %s.", field, strconv.Quote(value))
+	if field == "description" {
+		done = "You rewrite the room's description."
+	}
+	return built(done, w.save(save))
+}
+
+func (w *World) osetCommand(p *player.Player, name, field, raw string, toTemplate bool) response.Response {
+	e, msg, ok := w.findEditable(p, name)
+	if !ok {
+		return msg
+	}
+	if !identifierPattern.MatchString(field) {
+		return response.Text{Value: "Field names are letters, digits and underscores, and don't start with a digit."}
+	}
+
+	value := parseFieldValue(field, raw)
+	if field == "aliases" && len(value.SL) == 0 {
+		return response.Text{Value: fmt.Sprintf("%s needs at least one alias.", e.Name)}
+	}
+	templated, err := w.editTemplate(e, toTemplate, func(e *entities.Entity) error { return e.SetField(field, value) })
+	if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+
+	done := fmt.Sprintf("%s's %s is now %s.", e.Name, field, formatValue(value))
+	if !templated {
+		return justThisOne(done, e)
+	}
+	return built(done, w.save(func(s SourceWriter) error { return s.SetField(e.TemplateID, field, value) }))
+}
+
+// parseFieldValue reads what a builder typed as a field's value. Names and
+// descriptions are always text, aliases and tags lists, and anything else
+// whatever it looks like.
+func parseFieldValue(field, raw string) models.Value {
+	switch field {
+	case "name", "description":
+		return models.VStr(raw)
+	case "aliases", "tags":
+		var list []string
+		for item := range strings.SplitSeq(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				if field == "aliases" {
+					item = strings.ToLower(item)
+				}
+				list = append(list, item)
+			}
+		}
+		return models.Value{K: models.KindStringList, SL: list}
+	}
+
+	if raw == "true" || raw == "false" {
+		return models.VBool(raw == "true")
+	}
+	if n, err := strconv.Atoi(raw); err == nil {
+		return models.VInt(n)
+	}
+	return models.VStr(raw)
+}
+
+func (w *World) ocreateCommand(p *player.Player, id string) response.Response {
+	template, ok := w.templateNamed(id)
+	if !ok || template.TemplateID == "Player" {
+		return response.Text{Value: fmt.Sprintf("There's no template called %s.", id)}
+	}
+	if _, ok := entities.GetComponent[*components.Room](template); ok {
+		return response.Text{Value: fmt.Sprintf("%s is a room. Dig to it instead.", template.TemplateID)}
+	}
+	room, err := entities.RequireComponent[*components.Room](p.CurrentRoom())
+	if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+
+	created := template.Copy(room)
+	if err := room.AddChild(created); err != nil {
+		return response.Text{Value: err.Error()}
+	}
+	w.Register(created)
+	w.Publish(p.CurrentRoom(), fmt.Sprintf("%s conjures %s.", p.Name, created.Name), []*entities.Entity{p.Entity})
+
+	err = w.save(func(s SourceWriter) error {
+		return s.AddChild(p.CurrentRoom().TemplateID, entities.ComponentRoom, created.TemplateID)
+	})
+	return built(fmt.Sprintf("You make %s.", created.Name), err)
+}
+
+func (w *World) tagCommand(p *player.Player, name, tag string, add, toTemplate bool) response.Response {
+	e, msg, ok := w.findEditable(p, name)
+	if !ok {
+		return msg
+	}
+
+	tagged := slices.Contains(e.Tags, tag)
+	switch {
+	case add && tagged:
+		return response.Text{Value: fmt.Sprintf("%s is already tagged %s.", e.Name, tag)}
+	case !add && !tagged:
+		return response.Text{Value: fmt.Sprintf("%s isn't tagged %s.", e.Name, tag)}
+	}
+
+	tags := slices.DeleteFunc(slices.Clone(e.Tags), func(t string) bool { return t == tag })
+	done := fmt.Sprintf("%s is no longer tagged %s.", e.Name, tag)
+	if add {
+		tags = append(tags, tag)
+		done = fmt.Sprintf("%s is now tagged %s.", e.Name, tag)
+	}
+	value := models.Value{K: models.KindStringList, SL: tags}
+	templated, err := w.editTemplate(e, toTemplate, func(e *entities.Entity) error { return e.SetField("tags", value) })
+	if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+
+	if !templated {
+		return justThisOne(done, e)
+	}
+	return built(done, w.save(func(s SourceWriter) error { return s.SetField(e.TemplateID, "tags", value) }))
+}
+
+func (w *World) aliasCommand(p *player.Player, name, alias string, add, toTemplate bool) response.Response {
+	e, msg, ok := w.findEditable(p, name)
+	if !ok {
+		return msg
+	}
+
+	has := slices.Contains(e.Aliases, alias)
+	switch {
+	case add && has:
+		return response.Text{Value: fmt.Sprintf("%s already answers to %s.", e.Name, alias)}
+	case !add && !has:
+		return response.Text{Value: fmt.Sprintf("%s doesn't answer to %s.", e.Name, alias)}
+	case !add && len(e.Aliases) == 1:
+		return response.Text{Value: fmt.Sprintf("%s needs at least one alias.", e.Name)}
+	}
+
+	aliases := slices.DeleteFunc(slices.Clone(e.Aliases), func(a string) bool { return a == alias })
+	done := fmt.Sprintf("%s no longer answers to %s.", e.Name, alias)
+	if add {
+		aliases = append(aliases, alias)
+		done = fmt.Sprintf("%s now answers to %s.", e.Name, alias)
+	}
+	value := models.Value{K: models.KindStringList, SL: aliases}
+	templated, err := w.editTemplate(e, toTemplate, func(e *entities.Entity) error { return e.SetField("aliases", value) })
+	if err != nil {
+		return response.Text{Value: err.Error()}
+	}
+
+	if !templated {
+		return justThisOne(done, e)
+	}
+	return built(done, w.save(func(s SourceWriter) error { return s.SetField(e.TemplateID, "aliases", value) }))
+}
+
+// templateNamed looks up an entity map entry by ID, in any case since the
+// parser lower-cases what it's given.
+func (w *World) templateNamed(id string) (*entities.Entity, bool) {
+	if e, ok := w.GetEntityById(id); ok {
+		return e, true
+	}
+	for templateID, e := range w.EntitiesById() {
+		if strings.EqualFold(templateID, id) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// findEditable finds something a builder can change, which is anything
+// findEntity can but a player.
+func (w *World) findEditable(p *player.Player, name string) (*entities.Entity, response.Response, bool) {
+	e, ok := w.findEntity(p, name)
+	switch {
+	case !ok:
+		return nil, response.Text{Value: fmt.Sprintf("There's nothing called %s.", name)}, false
+	case w.isPlayer(e):
+		return nil, response.Text{Value: "You can't edit players."}, false
+	}
+	return e, nil, true
+}
+
+// editTemplate applies change to e and, if toTemplate, to the template it was
+// made from as well, so that new copies of it match. It reports whether a
+// template changed, which is when the change belongs in the world's source:
+// editing a template itself always changes one.
+func (w *World) editTemplate(e *entities.Entity, toTemplate bool, change func(e *entities.Entity) error) (bool, error) {
+	template, ok := w.GetEntityById(e.TemplateID)
+	if ok && template == e {
+		return true, change(e)
+	}
+	if toTemplate && !ok {
+		return false, fmt.Errorf("%s wasn't made from a template", e.Name)
+	}
+
+	if err := change(e); err != nil {
+		return false, err
+	}
+	if !toTemplate {
+		return false, nil
+	}
+	return true, change(template)
+}
+
+// justThisOne tells a builder what they did to e alone, which isn't saved.
+func justThisOne(done string, e *entities.Entity) response.Response {
+	return response.Text{Value: fmt.Sprintf("%s Only this one changed; use -template to change %s's template too.", done, e.Name)}
+}
@@ -0,0 +1,159 @@
+package world
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/response"
+	"example.com/mud/world/worldtest"
+)
+
+// fakeSource records what the build commands wrote back.
+type fakeSource struct {
+	writes []string
+}
+
+func (s *fakeSource) AddEntity(e *entities.Entity) error {
+	s.writes = append(s.writes, "add "+e.TemplateID)
+	return nil
+}
+
+func (s *fakeSource) SetField(templateID, key string, value models.Value) error {
+	s.writes = append(s.writes, fmt.Sprintf("%s.%s = %s", templateID, key, formatValue(value)))
+	return nil
+}
+
+func (s *fakeSource) SetComponentField(templateID string, component entities.ComponentType, key string, value models.Value) error {
+	s.writes = append(s.writes, fmt.Sprintf("%s.%s.%s = %s", templateID, component, key, formatValue(value)))
+	return nil
+}
+
+func (s *fakeSource) SetExits(templateID string, exits map[string]string) error {
+	s.writes = append(s.writes, fmt.Sprintf("%s exits %v", templateID, exits))
+	return nil
+}
+
+func (s *fakeSource) AddChild(templateID string, component entities.ComponentType, childID string) error {
+	s.writes = append(s.writes, fmt.Sprintf("%s.%s += %s", templateID, component, childID))
+	return nil
+}
+
+func TestWorld_BuildCommands(t *testing.T) {
+	t.Parallel()
+	registerOnce.Do(func() { require.NoError(t, commands.RegisterBuiltInCommands()) })
+
+	hall := worldtest.Room("Hall", map[string]string{"north": "Tower"})
+	lamp := worldtest.Thing("Lamp")
+	w := NewWorld(worldtest.Entities(hall, worldtest.Room("Tower", nil), lamp), "Hall")
+	w.SetAdmins([]string{"Alice"})
+	source := &fakeSource{}
+	w.SetSourceWriter(source)
+
+	p, err := w.AddPlayer("Alice", NewOutbox(OutboxOptions{}, nil))
+	require.NoError(t, err)
+
+	steps := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "dig a new room", line: "dig e Garden_1", want: "You dig east, making Garden_1."},
+		{name: "no digging over an exit", line: "dig east Pond", want: "There's already an exit east."},
+		{name: "dig to a room that's there", line: "dig south tower", want: "You dig south to Tower."},
+		{name: "no digging to where you are", line: "dig up hall", want: "You can't dig to the room you're in."},
+		{name: "room IDs are identifiers", line: "dig up 9lives", want: "Room IDs are letters"},
+		{name: "rename the room", line: "redit name The Great Hall", want: `The room's name is now "The Great Hall".`},
+		{name: "change the room's icon", line: "redit icon H", want: `The room's icon is now "H".`},
+		{name: "only some room fields", line: "redit exits none", want: "You can change a room's name"},
+		{name: "make something", line: "ocreate LAMP", want: "You make Lamp."},
+		{name: "no making rooms", line: "ocreate tower", want: "Tower is a room. Dig to it instead."},
+		{name: "set a field on the template too", line: "oset -template lamp brightness 4", want: "Lamp's brightness is now 4."},
+		{name: "set a field on just this one", line: "oset lamp brightness 3", want: "Lamp's brightness is now 3. Only this one changed; use -template to change Lamp's template too."},
+		{name: "set a name", line: "oset -template lamp name Old Lamp", want: `Old Lamp's name is now "Old Lamp".`},
+		{name: "set a field on a template", line: "oset tower dark true", want: "Tower's dark is now true."},
+		{name: "tag", line: "tag -template lamp Light", want: "Old Lamp is now tagged Light."},
+		{name: "no tagging twice", line: "tag lamp Light", want: "Old Lamp is already tagged Light."},
+		{name: "untag just this one", line: "untag lamp Light", want: "Old Lamp is no longer tagged Light. Only this one changed"},
+		{name: "alias", line: "alias -template lamp lantern", want: "Old Lamp now answers to lantern."},
+		{name: "found by its new alias", line: "unalias -template lantern lamp", want: "Old Lamp no longer answers to lamp."},
+		{name: "things keep one alias", line: "unalias lantern lantern", want: "Old Lamp needs at least one alias."},
+		{name: "no editing players", line: "tag me builder", want: "You can't edit players."},
+		{name: "players have no template to edit", line: "oset -template me score 1", want: "You can't edit players."},
+	}
+
+	for _, step := range steps {
+		resp, err := w.Parse(p, step.line)
+		require.NoError(t, err, step.name)
+		require.Contains(t, resp.(response.Text).Value, step.want, step.name)
+	}
+
+	require.Equal(t, []string{
+		"add Garden_1",
+		"Hall exits map[east:Garden_1 north:Tower]",
+		"Tower exits map[north:Hall]",
+		"Hall exits map[east:Garden_1 north:Tower south:Tower]",
+		`Hall.name = "The Great Hall"`,
+		`Hall.Room.icon = "H"`,
+		"Hall.Room += Lamp",
+		"Lamp.brightness = 4",
+		`Lamp.name = "Old Lamp"`,
+		"Tower.dark = true",
+		`Lamp.tags = ["Light"]`,
+		`Lamp.aliases = ["lamp" "lantern"]`,
+		`Lamp.aliases = ["lantern"]`,
+	}, source.writes)
+
+	garden, ok := w.GetEntityById("Garden_1")
+	require.True(t, ok)
+	gardenRoom, _ := entities.GetComponent[*components.Room](garden)
+	require.Equal(t, map[string]string{"west": "Hall"}, gardenRoom.Exits())
+	require.Equal(t, "Old Lamp", lamp.Name, "the template changes with the thing made from it, if asked")
+	require.Equal(t, []string{"lantern"}, lamp.Aliases)
+	require.Equal(t, []string{"Light"}, lamp.Tags)
+	require.Equal(t, models.VInt(4), lamp.Fields["brightness"])
+
+	hallRoom, _ := entities.GetComponent[*components.Room](hall)
+	made := hallRoom.GetChildren().GetChildrenByAlias("lantern")
+	require.Len(t, made, 1)
+	require.Empty(t, made[0].Entity.Tags, "and only the thing otherwise")
+	require.Equal(t, models.VInt(3), made[0].Entity.Fields["brightness"])
+}
+
+func TestWorld_DigWhileLooking(t *testing.T) {
+	t.Parallel()
+	registerOnce.Do(func() { require.NoError(t, commands.RegisterBuiltInCommands()) })
+
+	w := NewWorld(worldtest.Entities(worldtest.Room("Hall", nil)), "Hall")
+	w.SetAdmins([]string{"Alice"})
+	alice, err := w.AddPlayer("Alice", NewOutbox(OutboxOptions{}, nil))
+	require.NoError(t, err)
+	bob, err := w.AddPlayer("Bob", NewOutbox(OutboxOptions{}, nil))
+	require.NoError(t, err)
+
+	// the race detector catches exits changing under a player looking
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			if _, err := w.Parse(bob, "look"); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for _, dir := range []string{"north", "east", "south", "west", "up", "down"} {
+		resp, err := w.Parse(alice, "dig "+dir+" Room_"+dir)
+		require.NoError(t, err)
+		require.Contains(t, resp.(response.Text).Value, "You dig "+dir)
+	}
+	<-done
+
+	room, _ := entities.GetComponent[*components.Room](alice.CurrentRoom())
+	require.Len(t, room.Exits(), 6)
+}
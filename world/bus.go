@@ -4,22 +4,23 @@ import (
 	"sync"
 
 	"example.com/mud/world/entities"
+	"example.com/mud/world/response"
 )
 
 type Bus struct {
 	mu              sync.RWMutex
-	roomSubscribers map[*entities.Entity]map[*entities.Entity]chan string // room -> (player -> inbox channel)
-	playerRooms     map[*entities.Entity]*entities.Entity                 // player -> room
+	roomSubscribers map[*entities.Entity]map[*entities.Entity]*Outbox // room -> (player -> outbox)
+	playerRooms     map[*entities.Entity]*entities.Entity             // player -> room
 }
 
 func NewBus() *Bus {
 	return &Bus{
-		roomSubscribers: make(map[*entities.Entity]map[*entities.Entity]chan string),
+		roomSubscribers: make(map[*entities.Entity]map[*entities.Entity]*Outbox),
 		playerRooms:     make(map[*entities.Entity]*entities.Entity),
 	}
 }
 
-func (b *Bus) Subscribe(newRoom *entities.Entity, player *entities.Entity, inbox chan string) {
+func (b *Bus) Subscribe(newRoom *entities.Entity, player *entities.Entity, outbox *Outbox) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -36,10 +37,10 @@ func (b *Bus) Subscribe(newRoom *entities.Entity, player *entities.Entity, inbox
 	// subscribe to new room
 	subscribers := b.roomSubscribers[newRoom]
 	if subscribers == nil {
-		subscribers = make(map[*entities.Entity]chan string)
+		subscribers = make(map[*entities.Entity]*Outbox)
 		b.roomSubscribers[newRoom] = subscribers
 	}
-	subscribers[player] = inbox
+	subscribers[player] = outbox
 
 	// update index
 	b.playerRooms[player] = newRoom
@@ -62,20 +63,21 @@ func (b *Bus) Unsubscribe(room *entities.Entity, player *entities.Entity) {
 func (b *Bus) Move(toRoom *entities.Entity, player *entities.Entity) {
 	b.mu.RLock()
 	oldRoom := b.playerRooms[player]
-	var inbox chan string
+	var outbox *Outbox
 	if oldRoom != nil {
 		if subs := b.roomSubscribers[oldRoom]; subs != nil {
-			inbox = subs[player]
+			outbox = subs[player]
 		}
 	}
 	b.mu.RUnlock()
 
-	if inbox != nil {
-		b.Subscribe(toRoom, player, inbox)
+	if outbox != nil {
+		b.Subscribe(toRoom, player, outbox)
 	}
 }
 
-func (b *Bus) Publish(room *entities.Entity, text string, exclude []*entities.Entity) {
+// Publish sends msg to everyone in room but the excluded.
+func (b *Bus) Publish(room *entities.Entity, msg response.Message, exclude []*entities.Entity) {
 	excludeSet := make(map[*entities.Entity]struct{}, len(exclude))
 	for _, ex := range exclude {
 		excludeSet[ex] = struct{}{}
@@ -83,33 +85,36 @@ func (b *Bus) Publish(room *entities.Entity, text string, exclude []*entities.En
 
 	b.mu.RLock()
 	subscribers := b.roomSubscribers[room]
-	var targets []chan string
-	for p, inbox := range subscribers {
+	var targets []*Outbox
+	for p, outbox := range subscribers {
 		if _, excluded := excludeSet[p]; excluded {
 			continue
 		}
-		targets = append(targets, inbox)
+		targets = append(targets, outbox)
 	}
 	b.mu.RUnlock()
 
-	for _, inbox := range targets {
-		select {
-		case inbox <- text:
-		default:
-			// drop if receiver is slow
-		}
+	for _, outbox := range targets {
+		outbox.Push(msg)
 	}
 }
 
-func (b *Bus) PublishTo(room *entities.Entity, recipient *entities.Entity, text string) {
+// PublishTo sends msg to recipient, if they're in room.
+func (b *Bus) PublishTo(room *entities.Entity, recipient *entities.Entity, msg response.Message) {
 	b.mu.RLock()
 	subscribers := b.roomSubscribers[room]
-	inbox := subscribers[recipient]
+	outbox := subscribers[recipient]
 	b.mu.RUnlock()
 
-	select {
-	case inbox <- text:
-	default:
-		// drop if receiver is slow
+	if outbox != nil {
+		outbox.Push(msg)
 	}
 }
+
+// Outbox returns the outbox a player subscribed with.
+func (b *Bus) Outbox(player *entities.Entity) (*Outbox, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	outbox, ok := b.roomSubscribers[b.playerRooms[player]][player]
+	return outbox, ok
+}
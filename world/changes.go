@@ -0,0 +1,40 @@
+package world
+
+import (
+	"strings"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/response"
+)
+
+// childrenChanged tells players when something they can see gains or loses a
+// child: everyone in a room when the room or a container in it changes, and
+// a player when their inventory or a container they carry changes.
+func (w *World) childrenChanged(c entities.ComponentWithChildren) {
+	for c != nil {
+		owner := c.Owner()
+		if owner == nil {
+			return
+		}
+
+		switch c.(type) {
+		case *components.Room:
+			if e, ok := w.GetEntityByInstanceID(owner.ID); ok && e == owner {
+				w.PublishMessage(owner, response.RoomChanged(), nil)
+			}
+			return
+		case *components.Inventory:
+			w.playersMu.RLock()
+			p, ok := w.players[strings.ToLower(owner.Name)]
+			w.playersMu.RUnlock()
+			if ok && p.Entity == owner {
+				w.PublishMessageTo(p.CurrentRoom(), owner, response.InventoryChanged())
+			}
+			return
+		}
+
+		// a container: look for whatever it's in
+		c = owner.Parent
+	}
+}
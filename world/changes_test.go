@@ -0,0 +1,74 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/response"
+	"example.com/mud/world/worldtest"
+)
+
+func TestWorld_ChildrenChanged(t *testing.T) {
+	t.Parallel()
+
+	hall := worldtest.Room("Hall", nil)
+	box := worldtest.Thing("Box")
+	box.Add(components.NewContainer())
+	w := NewWorld(worldtest.Entities(hall, box), "Hall")
+
+	outbox := NewOutbox(OutboxOptions{}, nil)
+	p, err := w.AddPlayer("Alice", outbox)
+	require.NoError(t, err)
+	require.Empty(t, outbox.Take())
+
+	room, _ := entities.GetComponent[*components.Room](hall)
+	inventory, _ := entities.GetComponent[*components.Inventory](p.Entity)
+	container, _ := entities.GetComponent[*components.Container](box)
+	nickel := entities.NewEntity("Nickel", "A nickel.", []string{"nickel"}, nil, map[string]models.Value{}, nil)
+
+	tests := []struct {
+		name   string
+		change func()
+		want   []response.Message
+	}{
+		{
+			name:   "something appears in the room",
+			change: func() { require.NoError(t, room.AddChild(nickel)) },
+			want:   []response.Message{response.RoomChanged()},
+		},
+		{
+			name: "the player picks it up",
+			change: func() {
+				room.RemoveChild(nickel)
+				require.NoError(t, inventory.AddChild(nickel))
+			},
+			want: []response.Message{response.RoomChanged(), response.InventoryChanged()},
+		},
+		{
+			name: "it goes in a box the player carries",
+			change: func() {
+				require.NoError(t, inventory.AddChild(box))
+				inventory.RemoveChild(nickel)
+				require.NoError(t, container.AddChild(nickel))
+			},
+			want: []response.Message{response.InventoryChanged()},
+		},
+		{
+			name:   "it comes back out of the box",
+			change: func() { container.RemoveChild(nickel) },
+			want:   []response.Message{response.InventoryChanged()},
+		},
+	}
+
+	// steps build on each other, so they run in order
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+			require.Equal(t, tt.want, outbox.Take())
+		})
+	}
+}
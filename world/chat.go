@@ -0,0 +1,397 @@
+package world
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// Channels that aren't global chat channels, for clients that show each
+// channel in its own tab.
+const (
+	ChannelSay   = "say"
+	ChannelTell  = "tell"
+	ChannelShout = "shout"
+)
+
+const (
+	defaultChatHistory = 20
+	defaultChatLimit   = time.Second
+	defaultShoutRange  = 3
+)
+
+// ChatOptions configures talking between players. Zero values get defaults.
+type ChatOptions struct {
+	Channels    []string      // global channels every player starts out in
+	HistorySize int           // lines kept per global channel
+	RateLimit   time.Duration // time a player must wait between lines
+	ShoutRange  int           // exits a shout carries through
+}
+
+func (o ChatOptions) withDefaults() ChatOptions {
+	if o.Channels == nil {
+		o.Channels = []string{"ooc"}
+	}
+	if o.HistorySize <= 0 {
+		o.HistorySize = defaultChatHistory
+	}
+	if o.RateLimit <= 0 {
+		o.RateLimit = defaultChatLimit
+	}
+	if o.ShoutRange <= 0 {
+		o.ShoutRange = defaultShoutRange
+	}
+	return o
+}
+
+// chat keeps the global channels and what each player has asked to hear.
+// Players are keyed by lower-case name, so joins and ignores last until the
+// server restarts, however often they log in.
+type chat struct {
+	opts ChatOptions
+
+	mu        sync.Mutex
+	channels  map[string]*channel
+	seen      map[string]struct{}            // players who've been given the default channels
+	ignoring  map[string]map[string]struct{} // player -> players they ignore
+	lastSpoke map[string]time.Time
+}
+
+type channel struct {
+	members map[string]struct{}
+	history []string
+}
+
+func newChat(opts ChatOptions) *chat {
+	opts = opts.withDefaults()
+	c := &chat{
+		opts:      opts,
+		channels:  make(map[string]*channel),
+		seen:      make(map[string]struct{}),
+		ignoring:  make(map[string]map[string]struct{}),
+		lastSpoke: make(map[string]time.Time),
+	}
+	for _, name := range opts.Channels {
+		c.channels[strings.ToLower(name)] = &channel{members: make(map[string]struct{})}
+	}
+	return c
+}
+
+// SetChat configures the chat channels. Call it before players log in.
+func (w *World) SetChat(opts ChatOptions) {
+	w.chat = newChat(opts)
+}
+
+// arrive puts a player in the default channels the first time they're seen.
+func (c *chat) arrive(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := strings.ToLower(name)
+	if _, ok := c.seen[key]; ok {
+		return
+	}
+	c.seen[key] = struct{}{}
+	for _, ch := range c.channels {
+		ch.members[key] = struct{}{}
+	}
+}
+
+// speak checks the player's rate limit, and records that they spoke if
+// they're allowed to.
+func (c *chat) speak(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := strings.ToLower(name)
+	now := time.Now()
+	if now.Sub(c.lastSpoke[key]) < c.opts.RateLimit {
+		return false
+	}
+	c.lastSpoke[key] = now
+	return true
+}
+
+// ignores reports whether listener has ignored speaker.
+func (c *chat) ignores(listener, speaker string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.ignoring[strings.ToLower(listener)][strings.ToLower(speaker)]
+	return ok
+}
+
+func (w *World) chatCommand(p *player.Player, cmd *models.Command, line string) response.Response {
+	switch cmd.Kind {
+	case "channels":
+		return w.channelsCommand(p)
+	case "join":
+		return w.joinCommand(p, cmd.Params["channel"])
+	case "leave":
+		return w.leaveCommand(p, cmd.Params["channel"])
+	case "history":
+		return w.historyCommand(p, cmd.Params["channel"])
+	case "ignore":
+		return w.ignoreCommand(p, cmd.Params["player"])
+	case "unignore":
+		return w.unignoreCommand(p, cmd.Params["player"])
+	}
+
+	// the parser lower-cases everything, so messages come from the line itself
+	var message string
+	switch cmd.Kind {
+	case "tell", "chat":
+		message = restOfLine(line, 2)
+	default:
+		message = restOfLine(line, 1)
+	}
+	if !w.chat.speak(p.Name) {
+		return response.Text{Value: "Slow down, you're talking too fast."}
+	}
+
+	switch cmd.Kind {
+	case "say":
+		return w.say(p, message)
+	case "tell":
+		return w.tell(p, cmd.Params["player"], message)
+	case "shout":
+		return w.shout(p, message)
+	default:
+		return w.chatOn(p, cmd.Params["channel"], message)
+	}
+}
+
+// restOfLine returns line without its first n words, keeping the case and
+// spacing that the parser throws away.
+func restOfLine(line string, n int) string {
+	rest := strings.TrimSpace(line)
+	for range n {
+		i := strings.IndexFunc(rest, unicode.IsSpace)
+		if i < 0 {
+			return ""
+		}
+		rest = strings.TrimLeftFunc(rest[i:], unicode.IsSpace)
+	}
+	return rest
+}
+
+// tellPlayers sends a channel line to each listener who isn't ignoring the
+// speaker, and the speaker's own version of it to them.
+func (w *World) tellPlayers(speaker *player.Player, listeners []*player.Player, channel, heard, said string) {
+	for _, l := range listeners {
+		if l == speaker || w.chat.ignores(l.Name, speaker.Name) {
+			continue
+		}
+		w.PublishMessageTo(l.CurrentRoom(), l.Entity, response.ChannelLine(channel, heard))
+	}
+	w.PublishMessageTo(speaker.CurrentRoom(), speaker.Entity, response.ChannelLine(channel, said))
+}
+
+// playersIn returns the online players in any of rooms.
+func (w *World) playersIn(rooms map[*entities.Entity]struct{}) []*player.Player {
+	var in []*player.Player
+	for _, p := range w.OnlinePlayers() {
+		if _, ok := rooms[p.CurrentRoom()]; ok {
+			in = append(in, p)
+		}
+	}
+	return in
+}
+
+func (w *World) say(p *player.Player, message string) response.Response {
+	room := map[*entities.Entity]struct{}{p.CurrentRoom(): {}}
+	w.tellPlayers(p, w.playersIn(room), ChannelSay,
+		fmt.Sprintf("%s says, \"%s\"", p.Name, message),
+		fmt.Sprintf("You say, \"%s\"", message))
+	return response.Text{}
+}
+
+func (w *World) tell(p *player.Player, name, message string) response.Response {
+	to, ok := w.FindPlayer(name)
+	if !ok {
+		return response.Text{Value: fmt.Sprintf("Nobody called %s is playing.", name)}
+	}
+	if to == p {
+		return response.Text{Value: "You mutter to yourself."}
+	}
+	if w.chat.ignores(to.Name, p.Name) {
+		return response.Text{Value: fmt.Sprintf("%s is ignoring you.", to.Name)}
+	}
+	w.tellPlayers(p, []*player.Player{to}, ChannelTell,
+		fmt.Sprintf("%s tells you, \"%s\"", p.Name, message),
+		fmt.Sprintf("You tell %s, \"%s\"", to.Name, message))
+	return response.Text{}
+}
+
+func (w *World) shout(p *player.Player, message string) response.Response {
+	w.tellPlayers(p, w.playersIn(w.roomsNear(p.CurrentRoom(), w.chat.opts.ShoutRange)), ChannelShout,
+		fmt.Sprintf("%s shouts, \"%s\"", p.Name, message),
+		fmt.Sprintf("You shout, \"%s\"", message))
+	return response.Text{}
+}
+
+// roomsNear returns room and every room within distance exits of it.
+func (w *World) roomsNear(room *entities.Entity, distance int) map[*entities.Entity]struct{} {
+	near := map[*entities.Entity]struct{}{room: {}}
+	frontier := []*entities.Entity{room}
+	for range distance {
+		var next []*entities.Entity
+		for _, r := range frontier {
+			rc, ok := entities.GetComponent[*components.Room](r)
+			if !ok {
+				continue
+			}
+			for _, id := range rc.Exits() {
+				neighbor, ok := w.GetEntityById(id)
+				if _, seen := near[neighbor]; !ok || seen {
+					continue
+				}
+				near[neighbor] = struct{}{}
+				next = append(next, neighbor)
+			}
+		}
+		frontier = next
+	}
+	return near
+}
+
+func (w *World) chatOn(p *player.Player, name, message string) response.Response {
+	name = strings.ToLower(name)
+	line := fmt.Sprintf("[%s] %s: %s", name, p.Name, message)
+
+	w.chat.mu.Lock()
+	ch, joined := w.chat.channels[name].membership(p.Name)
+	if !joined {
+		w.chat.mu.Unlock()
+		return response.Text{Value: fmt.Sprintf("You aren't on a channel called %s.", name)}
+	}
+	ch.history = append(ch.history, line)
+	if over := len(ch.history) - w.chat.opts.HistorySize; over > 0 {
+		ch.history = ch.history[over:]
+	}
+	members := maps.Clone(ch.members)
+	w.chat.mu.Unlock()
+
+	var listeners []*player.Player
+	for _, l := range w.OnlinePlayers() {
+		if _, ok := members[strings.ToLower(l.Name)]; ok {
+			listeners = append(listeners, l)
+		}
+	}
+	w.tellPlayers(p, listeners, name, line, line)
+	return response.Text{}
+}
+
+// membership reports whether name is on the channel. It's safe to call on a
+// nil channel.
+func (ch *channel) membership(name string) (*channel, bool) {
+	if ch == nil {
+		return nil, false
+	}
+	_, ok := ch.members[strings.ToLower(name)]
+	return ch, ok
+}
+
+func (w *World) channelsCommand(p *player.Player) response.Response {
+	w.chat.mu.Lock()
+	defer w.chat.mu.Unlock()
+
+	if len(w.chat.channels) == 0 {
+		return response.Text{Value: "There are no chat channels."}
+	}
+	var b strings.Builder
+	b.WriteString("Channels:")
+	for _, name := range slices.Sorted(maps.Keys(w.chat.channels)) {
+		b.WriteString("\n- ")
+		b.WriteString(name)
+		if _, joined := w.chat.channels[name].membership(p.Name); joined {
+			b.WriteString(" (joined)")
+		}
+	}
+	return response.Text{Value: b.String()}
+}
+
+func (w *World) joinCommand(p *player.Player, name string) response.Response {
+	w.chat.mu.Lock()
+	defer w.chat.mu.Unlock()
+
+	name = strings.ToLower(name)
+	ch, ok := w.chat.channels[name]
+	if !ok {
+		return response.Text{Value: fmt.Sprintf("There's no channel called %s.", name)}
+	}
+	ch.members[strings.ToLower(p.Name)] = struct{}{}
+	return response.Text{Value: fmt.Sprintf("You join %s.", name)}
+}
+
+func (w *World) leaveCommand(p *player.Player, name string) response.Response {
+	w.chat.mu.Lock()
+	defer w.chat.mu.Unlock()
+
+	name = strings.ToLower(name)
+	ch, joined := w.chat.channels[name].membership(p.Name)
+	if !joined {
+		return response.Text{Value: fmt.Sprintf("You aren't on a channel called %s.", name)}
+	}
+	delete(ch.members, strings.ToLower(p.Name))
+	return response.Text{Value: fmt.Sprintf("You leave %s.", name)}
+}
+
+func (w *World) historyCommand(p *player.Player, name string) response.Response {
+	w.chat.mu.Lock()
+	defer w.chat.mu.Unlock()
+
+	name = strings.ToLower(name)
+	ch, joined := w.chat.channels[name].membership(p.Name)
+	if !joined {
+		return response.Text{Value: fmt.Sprintf("You aren't on a channel called %s.", name)}
+	}
+	if len(ch.history) == 0 {
+		return response.Text{Value: fmt.Sprintf("Nothing's been said on %s lately.", name)}
+	}
+	return response.Text{Value: strings.Join(ch.history, "\n")}
+}
+
+func (w *World) ignoreCommand(p *player.Player, name string) response.Response {
+	w.chat.mu.Lock()
+	defer w.chat.mu.Unlock()
+
+	key := strings.ToLower(p.Name)
+	if name == "" {
+		ignored := w.chat.ignoring[key]
+		if len(ignored) == 0 {
+			return response.Text{Value: "You aren't ignoring anyone."}
+		}
+		return response.Text{Value: "You're ignoring: " + strings.Join(slices.Sorted(maps.Keys(ignored)), ", ")}
+	}
+
+	name = strings.ToLower(name)
+	if name == key {
+		return response.Text{Value: "You can't ignore yourself, much as you might like to."}
+	}
+	if w.chat.ignoring[key] == nil {
+		w.chat.ignoring[key] = make(map[string]struct{})
+	}
+	w.chat.ignoring[key][name] = struct{}{}
+	return response.Text{Value: fmt.Sprintf("You're ignoring %s.", name)}
+}
+
+func (w *World) unignoreCommand(p *player.Player, name string) response.Response {
+	w.chat.mu.Lock()
+	defer w.chat.mu.Unlock()
+
+	key := strings.ToLower(p.Name)
+	name = strings.ToLower(name)
+	if _, ok := w.chat.ignoring[key][name]; !ok {
+		return response.Text{Value: fmt.Sprintf("You weren't ignoring %s.", name)}
+	}
+	delete(w.chat.ignoring[key], name)
+	return response.Text{Value: fmt.Sprintf("You can hear %s again.", name)}
+}
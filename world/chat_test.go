@@ -0,0 +1,166 @@
+package world
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/models"
+	"example.com/mud/world/response"
+	"example.com/mud/world/worldtest"
+)
+
+func TestWorld_Chat(t *testing.T) {
+	t.Parallel()
+
+	w := NewWorld(worldtest.Entities(
+		worldtest.Room("Hall", map[string]string{"north": "Tower"}),
+		worldtest.Room("Tower", map[string]string{"south": "Hall"}),
+	), "Hall")
+	w.SetChat(ChatOptions{Channels: []string{"ooc"}, RateLimit: time.Nanosecond, ShoutRange: 1})
+
+	outboxes := map[string]*Outbox{}
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		outboxes[name] = NewOutbox(OutboxOptions{}, nil)
+		_, err := w.AddPlayer(name, outboxes[name])
+		require.NoError(t, err)
+	}
+	carol, _ := w.FindPlayer("Carol")
+	_, err := w.MovePlayer(carol, "north")
+	require.NoError(t, err)
+	for _, o := range outboxes {
+		o.Take()
+	}
+
+	// heard returns the channel lines each player got
+	heard := func() map[string][]response.Message {
+		out := map[string][]response.Message{}
+		for name, o := range outboxes {
+			for _, msg := range o.Take() {
+				if msg.Kind == response.MessageChannel {
+					out[name] = append(out[name], msg)
+				}
+			}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		speaker string
+		cmd     models.Command
+		line    string
+		reply   string
+		heard   map[string][]response.Message
+	}{
+		{
+			name:    "say keeps its case and reaches the room",
+			speaker: "Alice",
+			cmd:     models.Command{Kind: "say", Params: map[string]string{"message": "hello there"}},
+			line:    "say  Hello there",
+			heard: map[string][]response.Message{
+				"Alice": {response.ChannelLine(ChannelSay, `You say, "Hello there"`)},
+				"Bob":   {response.ChannelLine(ChannelSay, `Alice says, "Hello there"`)},
+			},
+		},
+		{
+			name:    "shout carries to the next room",
+			speaker: "Bob",
+			cmd:     models.Command{Kind: "shout", Params: map[string]string{"message": "hey"}},
+			line:    "shout HEY",
+			heard: map[string][]response.Message{
+				"Alice": {response.ChannelLine(ChannelShout, `Bob shouts, "HEY"`)},
+				"Bob":   {response.ChannelLine(ChannelShout, `You shout, "HEY"`)},
+				"Carol": {response.ChannelLine(ChannelShout, `Bob shouts, "HEY"`)},
+			},
+		},
+		{
+			name:    "tell reaches one player",
+			speaker: "Carol",
+			cmd:     models.Command{Kind: "tell", Params: map[string]string{"player": "alice", "message": "psst"}},
+			line:    "tell alice Psst",
+			heard: map[string][]response.Message{
+				"Alice": {response.ChannelLine(ChannelTell, `Carol tells you, "Psst"`)},
+				"Carol": {response.ChannelLine(ChannelTell, `You tell Alice, "Psst"`)},
+			},
+		},
+		{
+			name:    "tell to nobody",
+			speaker: "Carol",
+			cmd:     models.Command{Kind: "tell", Params: map[string]string{"player": "dave", "message": "hi"}},
+			line:    "tell dave hi",
+			reply:   "Nobody called dave is playing.",
+		},
+		{
+			name:    "ignore",
+			speaker: "Bob",
+			cmd:     models.Command{Kind: "ignore", Params: map[string]string{"player": "carol"}},
+			line:    "ignore carol",
+			reply:   "You're ignoring carol.",
+		},
+		{
+			name:    "channels skip whoever ignores the speaker",
+			speaker: "Carol",
+			cmd:     models.Command{Kind: "chat", Params: map[string]string{"channel": "ooc", "message": "anyone?"}},
+			line:    "chat ooc Anyone?",
+			heard: map[string][]response.Message{
+				"Alice": {response.ChannelLine("ooc", "[ooc] Carol: Anyone?")},
+				"Carol": {response.ChannelLine("ooc", "[ooc] Carol: Anyone?")},
+			},
+		},
+		{
+			name:    "tell to someone ignoring you",
+			speaker: "Carol",
+			cmd:     models.Command{Kind: "tell", Params: map[string]string{"player": "bob", "message": "hi"}},
+			line:    "tell bob hi",
+			reply:   "Bob is ignoring you.",
+		},
+		{
+			name:    "leave",
+			speaker: "Alice",
+			cmd:     models.Command{Kind: "leave", Params: map[string]string{"channel": "ooc"}},
+			line:    "leave ooc",
+			reply:   "You leave ooc.",
+		},
+		{
+			name:    "can't talk on a channel you left",
+			speaker: "Alice",
+			cmd:     models.Command{Kind: "chat", Params: map[string]string{"channel": "ooc", "message": "hi"}},
+			line:    "chat ooc hi",
+			reply:   "You aren't on a channel called ooc.",
+		},
+		{
+			name:    "history",
+			speaker: "Bob",
+			cmd:     models.Command{Kind: "history", Params: map[string]string{"channel": "ooc"}},
+			line:    "history ooc",
+			reply:   "[ooc] Carol: Anyone?",
+		},
+	}
+
+	// steps build on each other, so they run in order
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			speaker, ok := w.FindPlayer(tt.speaker)
+			require.True(t, ok)
+			resp := w.chatCommand(speaker, &tt.cmd, tt.line)
+			require.Equal(t, response.Text{Value: tt.reply}, resp)
+
+			want := tt.heard
+			if want == nil {
+				want = map[string][]response.Message{}
+			}
+			require.Equal(t, want, heard())
+		})
+	}
+}
+
+func TestWorld_ChatRateLimit(t *testing.T) {
+	t.Parallel()
+
+	c := newChat(ChatOptions{RateLimit: time.Hour})
+	require.True(t, c.speak("Alice"))
+	require.False(t, c.speak("alice"))
+	require.True(t, c.speak("Bob"))
+}
@@ -35,9 +35,11 @@ func (c *Copy) Execute(ev *entities.Event) error {
 		return fmt.Errorf("Copy execute: entity '%s' doesn't exist", c.EntityId)
 	}
 
-	component.AddChild(
-		entityToCopy.Copy(component),
-	)
+	copied := entityToCopy.Copy(component)
+	component.AddChild(copied)
+	if ev.Registry != nil {
+		ev.Registry.Register(copied)
+	}
 
 	return nil
 }
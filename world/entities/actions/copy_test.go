@@ -123,7 +123,8 @@ func TestCopy_Execute(t *testing.T) {
 					EntitiesById: map[string]*entities.Entity{
 						toCopyName: src,
 					},
-					Target: recipient,
+					Registry: &recordingRegistry{},
+					Target:   recipient,
 				}
 				return ev, container, src
 			},
@@ -151,10 +152,24 @@ func TestCopy_Execute(t *testing.T) {
 
 			require.Equal(t, toCopyName, children[0].Name)
 			require.NotSame(t, src, children[0], "expected a copy, not the original pointer")
+			require.NotEqual(t, src.ID, children[0].ID, "copies get their own instance ID")
+			require.Equal(t, src.TemplateID, children[0].TemplateID)
+
+			if registry, ok := ev.Registry.(*recordingRegistry); ok {
+				require.Equal(t, []*entities.Entity{children[0]}, registry.registered)
+			}
 		})
 	}
 }
 
+type recordingRegistry struct {
+	registered []*entities.Entity
+}
+
+func (r *recordingRegistry) Register(e *entities.Entity)        { r.registered = append(r.registered, e) }
+func (r *recordingRegistry) Unregister(e *entities.Entity)      {}
+func (r *recordingRegistry) Moved(e, from, to *entities.Entity) {}
+
 // makeContainerRecipient creates an entity with a Container component and returns both.
 func makeContainerRecipient(name string) (*entities.Entity, *components.Container) {
 	tags := []string{"sailor"}
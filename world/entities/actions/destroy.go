@@ -34,6 +34,9 @@ func (d *Destroy) Execute(ev *entities.Event) error {
 
 	// remove role from parent (is this enough for garbage collection to kick in?)
 	role.Parent.RemoveChild(role)
+	if ev.Registry != nil {
+		ev.Registry.Unregister(role)
+	}
 
 	return nil
 }
@@ -36,6 +36,9 @@ func (m *Move) Execute(ev *entities.Event) error {
 
 	// add entity to new parent
 	component.AddChild(origin)
+	if ev.Registry != nil {
+		ev.Registry.Moved(origin, oldParent.Owner(), destination)
+	}
 
 	return nil
 }
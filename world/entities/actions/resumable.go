@@ -0,0 +1,47 @@
+package actions
+
+import (
+	"sync"
+	"time"
+
+	"example.com/mud/world/entities"
+)
+
+// Resumable is implemented by actions that leave jobs on the scheduler.
+// Resume schedules the action's next run for the given event, which is how a
+// saved job is picked up again after a restart.
+type Resumable interface {
+	entities.Action
+	Resume(ev *entities.Event, next time.Time)
+}
+
+// PendingJob is attached to the scheduler jobs of keyed Resumable actions, so
+// the job can be saved as the action's key and the event it runs against.
+type PendingJob struct {
+	Key   string
+	Event *entities.Event
+}
+
+func pendingMeta(key string, ev *entities.Event) any {
+	if key == "" {
+		return nil
+	}
+	return &PendingJob{Key: key, Event: ev}
+}
+
+var resumables sync.Map // key -> Resumable
+
+// RegisterResumable makes a keyed action available to LookupResumable.
+// Registering a key again replaces the previous action.
+func RegisterResumable(key string, a Resumable) {
+	resumables.Store(key, a)
+}
+
+// LookupResumable finds the action a saved job belongs to.
+func LookupResumable(key string) (Resumable, bool) {
+	a, ok := resumables.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return a.(Resumable), true
+}
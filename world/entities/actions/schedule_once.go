@@ -10,13 +10,23 @@ import (
 type ScheduleOnce struct {
 	Nanoseconds time.Duration
 	Actions     []entities.Action
+
+	// Key identifies this action across restarts so its pending job can be
+	// saved; empty means the job is not saved.
+	Key string
 }
 
 var _ entities.Action = &ScheduleOnce{}
+var _ Resumable = &ScheduleOnce{}
 
 func (c *ScheduleOnce) Execute(ev *entities.Event) error {
+	c.Resume(ev, time.Now().Add(c.Nanoseconds))
+	return nil
+}
+
+func (c *ScheduleOnce) Resume(ev *entities.Event, next time.Time) {
 	ev.Scheduler.Add(&scheduler.Job{
-		NextRun: time.Now().Add(c.Nanoseconds),
+		NextRun: next,
 		RunFunc: func() {
 			for _, a := range c.Actions {
 				err := a.Execute(ev)
@@ -25,7 +35,6 @@ func (c *ScheduleOnce) Execute(ev *entities.Event) error {
 				}
 			}
 		},
+		Meta: pendingMeta(c.Key, ev),
 	})
-
-	return nil
 }
@@ -10,11 +10,22 @@ import (
 type ScheduleRepeating struct {
 	Nanoseconds time.Duration
 	Rule        *entities.Rule
+
+	// Key identifies this action across restarts so its pending job can be
+	// saved; empty means the job is not saved.
+	Key string
 }
 
 var _ entities.Action = &ScheduleRepeating{}
+var _ Resumable = &ScheduleRepeating{}
 
 func (sr *ScheduleRepeating) Execute(ev *entities.Event) error {
+	// kick off the first run
+	sr.Resume(ev, time.Now().Add(sr.Nanoseconds))
+	return nil
+}
+
+func (sr *ScheduleRepeating) Resume(ev *entities.Event, next time.Time) {
 	// defining a schedule function before instantiating it allows recursion inside the schedule function
 	var schedule func(next time.Time)
 
@@ -40,10 +51,9 @@ func (sr *ScheduleRepeating) Execute(ev *entities.Event) error {
 				// if we reach this far, reschedule again.
 				schedule(next.Add(sr.Nanoseconds))
 			},
+			Meta: pendingMeta(sr.Key, ev),
 		})
 	}
 
-	// kick off the first run
-	schedule(time.Now().Add(sr.Nanoseconds))
-	return nil
+	schedule(next)
 }
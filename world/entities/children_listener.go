@@ -0,0 +1,28 @@
+package entities
+
+// ChildrenListener is told when a component gains or loses a child.
+type ChildrenListener func(c ComponentWithChildren)
+
+// ListenForChildren has l told about changes to the children of every
+// component in or below e, for as long as e is at the top of its parent
+// chain, usually as a room. Worlds set it on what they register, to tell
+// players when what they can see changes.
+func (e *Entity) ListenForChildren(l ChildrenListener) {
+	e.childrenListener.Store(&l)
+}
+
+// ChildrenChanged tells the listener at the top of c's parent chain that c's
+// children changed. Components with children call it from AddChild and
+// RemoveChild, once they have an owner.
+func ChildrenChanged(c ComponentWithChildren) {
+	top := c.Owner()
+	if top == nil {
+		return
+	}
+	for top.Parent != nil && top.Parent.Owner() != nil {
+		top = top.Parent.Owner()
+	}
+	if l := top.childrenListener.Load(); l != nil {
+		(*l)(c)
+	}
+}
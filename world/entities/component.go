@@ -68,6 +68,10 @@ type ComponentWithChildren interface {
 	RemoveChild(child *Entity)
 
 	GetChildren() IChildren
+
+	// Owner is the entity the component was added to, nil until then.
+	Owner() *Entity
+	SetOwner(e *Entity)
 }
 
 type IChildren interface {
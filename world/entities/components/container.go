@@ -8,6 +8,7 @@ import (
 
 type Container struct {
 	children entities.IChildren
+	owner    *entities.Entity
 }
 
 var _ entities.Component = &Container{}
@@ -42,6 +43,7 @@ func (c *Container) AddChild(child *entities.Entity) error {
 	}
 
 	child.Parent = c
+	entities.ChildrenChanged(c)
 
 	return nil
 }
@@ -49,8 +51,17 @@ func (c *Container) AddChild(child *entities.Entity) error {
 func (c *Container) RemoveChild(child *entities.Entity) {
 	child.Parent = nil
 	c.GetChildren().RemoveChild(child)
+	entities.ChildrenChanged(c)
 }
 
 func (c *Container) GetChildren() entities.IChildren {
 	return c.children
 }
+
+func (c *Container) Owner() *entities.Entity {
+	return c.owner
+}
+
+func (c *Container) SetOwner(e *entities.Entity) {
+	c.owner = e
+}
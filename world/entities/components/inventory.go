@@ -9,6 +9,7 @@ import (
 
 type Inventory struct {
 	children entities.IChildren
+	owner    *entities.Entity
 }
 
 func NewInventory() *Inventory {
@@ -43,6 +44,7 @@ func (i *Inventory) AddChild(child *entities.Entity) error {
 	}
 
 	child.Parent = i
+	entities.ChildrenChanged(i)
 
 	return nil
 }
@@ -50,12 +52,21 @@ func (i *Inventory) AddChild(child *entities.Entity) error {
 func (i *Inventory) RemoveChild(child *entities.Entity) {
 	child.Parent = nil
 	i.GetChildren().RemoveChild(child)
+	entities.ChildrenChanged(i)
 }
 
 func (i *Inventory) GetChildren() entities.IChildren {
 	return i.children
 }
 
+func (i *Inventory) Owner() *entities.Entity {
+	return i.owner
+}
+
+func (i *Inventory) SetOwner(e *entities.Entity) {
+	i.owner = e
+}
+
 func (i *Inventory) Print() (string, error) {
 	var b strings.Builder
 
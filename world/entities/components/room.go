@@ -3,6 +3,7 @@ package components
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"example.com/mud/world/entities"
 )
@@ -10,9 +11,13 @@ import (
 type Room struct {
 	MapIcon  string
 	MapColor string
-	Exits    map[string]string
+
+	// exits is replaced, never changed, since builders dig while players
+	// walk through the room, and copies of the room share it.
+	exits atomic.Pointer[map[string]string]
 
 	children entities.IChildren
+	owner    *entities.Entity
 }
 
 var _ entities.Component = &Room{}
@@ -30,12 +35,27 @@ func (r *Room) Id() entities.ComponentType {
 }
 
 func (r *Room) Copy() entities.Component {
-	return &Room{
+	c := &Room{
 		MapIcon:  r.MapIcon,
 		MapColor: r.MapColor,
-		Exits:    r.Exits,
 		children: r.children.Copy(),
 	}
+	c.exits.Store(r.exits.Load())
+	return c
+}
+
+// Exits returns the IDs of the rooms the exits lead to, by direction. The map
+// mustn't be changed; SetExits a new one instead.
+func (r *Room) Exits() map[string]string {
+	if exits := r.exits.Load(); exits != nil {
+		return *exits
+	}
+	return nil
+}
+
+// SetExits replaces the room's exits. exits mustn't be changed afterwards.
+func (r *Room) SetExits(exits map[string]string) {
+	r.exits.Store(&exits)
 }
 
 func (r *Room) AddChild(child *entities.Entity) error {
@@ -45,6 +65,7 @@ func (r *Room) AddChild(child *entities.Entity) error {
 	}
 
 	child.Parent = r
+	entities.ChildrenChanged(r)
 
 	return nil
 }
@@ -52,14 +73,23 @@ func (r *Room) AddChild(child *entities.Entity) error {
 func (r *Room) RemoveChild(child *entities.Entity) {
 	child.Parent = nil
 	r.GetChildren().RemoveChild(child)
+	entities.ChildrenChanged(r)
 }
 
 func (r *Room) GetChildren() entities.IChildren {
 	return r.children
 }
 
+func (r *Room) Owner() *entities.Entity {
+	return r.owner
+}
+
+func (r *Room) SetOwner(e *entities.Entity) {
+	r.owner = e
+}
+
 func (r *Room) GetNeighboringRoomId(direction string) (string, bool) {
-	roomId, ok := r.Exits[direction]
+	roomId, ok := r.Exits()[direction]
 	return roomId, ok
 }
 
@@ -67,7 +97,7 @@ func (r *Room) GetExitText() string {
 	var b strings.Builder
 	b.WriteString("Exits: ")
 
-	for exit := range r.Exits {
+	for exit := range r.Exits() {
 		b.WriteString(exit)
 		b.WriteString(", ")
 	}
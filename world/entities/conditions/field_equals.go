@@ -0,0 +1,42 @@
+package conditions
+
+import (
+	"fmt"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+)
+
+type FieldEquals struct {
+	EventRole entities.EventRole
+	Field     string
+	Value     models.Value
+}
+
+var _ entities.Condition = &FieldEquals{}
+
+func (fe *FieldEquals) Id() entities.ConditionType {
+	return entities.ConditionFieldEquals
+}
+
+func (fe *FieldEquals) Check(ev *entities.Event) (bool, error) {
+	var e *entities.Entity
+	switch fe.EventRole {
+	case entities.EventRoleSource:
+		e = ev.Source
+	case entities.EventRoleInstrument:
+		e = ev.Instrument
+	case entities.EventRoleTarget:
+		e = ev.Target
+	case entities.EventRoleRoom:
+		e = ev.Room
+	default:
+		return false, fmt.Errorf("invalid role '%s' for field equals condition", fe.EventRole.String())
+	}
+
+	if e == nil {
+		return false, nil
+	}
+
+	return e.GetField(fe.Field).Equals(fe.Value), nil
+}
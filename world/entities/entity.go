@@ -1,10 +1,13 @@
 package entities
 
 import (
+	"cmp"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"example.com/mud/models"
 	"example.com/mud/utils"
@@ -14,17 +17,26 @@ type Entity struct {
 	mu         sync.RWMutex
 	components map[reflect.Type]Component
 
+	// ID is unique to this instance and never changes, even across saves.
+	// TemplateID is the ID of the definition this entity was built or copied
+	// from. Copies share their template's ID.
+	ID         string
+	TemplateID string
+
 	Name        string
 	Description string
 	Aliases     []string
 	Tags        []string
 	Fields      map[string]models.Value
 	Parent      ComponentWithChildren
+
+	childrenListener atomic.Pointer[ChildrenListener] // see ListenForChildren
 }
 
 func NewEntity(name, description string, aliases []string, tags []string, fields map[string]models.Value, parent ComponentWithChildren) *Entity {
 	return &Entity{
 		components:  map[reflect.Type]Component{},
+		ID:          NewID(),
 		Name:        name,
 		Description: description,
 		Aliases:     aliases,
@@ -51,6 +63,7 @@ func (e *Entity) Copy(parent ComponentWithChildren) *Entity {
 		fieldsCopy,
 		parent,
 	)
+	newEntity.TemplateID = e.TemplateID
 
 	for _, c := range e.components {
 		newEntity.Add(c.Copy())
@@ -103,6 +116,9 @@ func (e *Entity) SetField(fieldName string, v models.Value) error {
 
 func (e *Entity) setAliases(aliases []string) error {
 	e.Aliases = aliases
+	if e.Parent == nil {
+		return nil
+	}
 
 	// entities are indexed by aliases for performance reasons, so we need to reindex
 	err := e.Parent.GetChildren().ReindexAliasesForEntity(e)
@@ -117,9 +133,27 @@ func (e *Entity) Add(c Component) *Entity {
 	e.mu.Lock()
 	e.components[reflect.TypeOf(c)] = c
 	e.mu.Unlock()
+	if cwc, ok := c.(ComponentWithChildren); ok {
+		cwc.SetOwner(e)
+	}
 	return e
 }
 
+// Components returns the entity's components, ordered by type.
+func (e *Entity) Components() []Component {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	components := make([]Component, 0, len(e.components))
+	for _, c := range e.components {
+		components = append(components, c)
+	}
+	slices.SortFunc(components, func(a, b Component) int {
+		return cmp.Compare(a.Id(), b.Id())
+	})
+	return components
+}
+
 func (e *Entity) GetComponentWithChildren(ct ComponentType) (ComponentWithChildren, bool) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -24,12 +25,22 @@ type Event struct {
 	Type         string
 	Publisher    Publisher
 	Scheduler    Scheduler
+	Registry     Registry // nil when nothing needs to know about new entities
 	EntitiesById map[string]*Entity
 	Room         *Entity
 	Source       *Entity
 	Instrument   *Entity
 	Target       *Entity
 	Message      string
+	Ctx          context.Context // cancelled when whoever caused the event stops waiting on it, nil for never
+}
+
+// Context returns the event's context, for anything slow in handling it.
+func (e *Event) Context() context.Context {
+	if e.Ctx == nil {
+		return context.Background()
+	}
+	return e.Ctx
 }
 
 func (e *Event) GetRole(role EventRole) (*Entity, error) {
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Registry keeps track of every live entity by its instance ID. Actions that
+// create, move or destroy entities report them to the event's registry.
+type Registry interface {
+	Register(e *Entity)
+	Unregister(e *Entity)
+
+	// Moved reports that e has moved out of from and into to.
+	Moved(e, from, to *Entity)
+}
+
+// NewID returns a fresh instance ID. IDs are random rather than counted so
+// that saved entities keep theirs without colliding with new ones.
+func NewID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Walk calls fn for e and every entity below it in any component's children.
+func (e *Entity) Walk(fn func(e *Entity)) {
+	fn(e)
+	for _, cwc := range e.GetComponentsWithChildren() {
+		for _, child := range cwc.GetChildren().GetChildren() {
+			child.Walk(fn)
+		}
+	}
+}
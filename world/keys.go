@@ -0,0 +1,54 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"example.com/mud/account"
+	"example.com/mud/models"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// sshKeyCommand lets players manage the public keys they can log in over SSH
+// with. The keys belong to the account, so the operator keeps them.
+func (w *World) sshKeyCommand(p *player.Player, cmd *models.Command, line string) response.Response {
+	switch {
+	case cmd.Params["key"] != "":
+		// the parser lower-cases what it matches, and keys are case-sensitive
+		err := w.operator.AddPublicKey(p.Name, restOfLine(line, 2))
+		switch {
+		case errors.Is(err, account.ErrBadPublicKey):
+			return response.Text{Value: "That doesn't look like a public key. Paste a line like the ones in ~/.ssh/id_ed25519.pub."}
+		case err != nil:
+			return response.Text{Value: fmt.Sprintf("error received: %v", err)}
+		}
+		return response.Text{Value: "Key added. You can now log in over SSH with it."}
+
+	case cmd.Params["number"] != "":
+		n, err := strconv.Atoi(cmd.Params["number"])
+		if err != nil {
+			return response.Text{Value: "Give the number of the key, as sshkey list shows it."}
+		}
+		if err := w.operator.RemovePublicKey(p.Name, n-1); err != nil {
+			return response.Text{Value: fmt.Sprintf("error received: %v", err)}
+		}
+		return response.Text{Value: "Key removed."}
+	}
+
+	keys, err := w.operator.PublicKeys(p.Name)
+	switch {
+	case err != nil:
+		return response.Text{Value: fmt.Sprintf("error received: %v", err)}
+	case len(keys) == 0:
+		return response.Text{Value: "You have no SSH keys. Add one with: sshkey add <public key>"}
+	}
+	var b strings.Builder
+	b.WriteString("Your SSH keys:")
+	for i, k := range keys {
+		fmt.Fprintf(&b, "\n  %d) %s", i+1, k)
+	}
+	return response.Text{Value: b.String()}
+}
@@ -0,0 +1,276 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/mud/world/response"
+)
+
+// Outbox policies, for when a player's messages arrive faster than their
+// connection takes them.
+const (
+	// OutboxCoalesce merges repeats of the last waiting message into one
+	// ("... (x3)" for text), and drops the oldest message once the queue is full.
+	OutboxCoalesce = "coalesce"
+	// OutboxDisconnect keeps everything, but disconnects the player once
+	// their queue has been full for SlowAfter. A link-dead player catches up
+	// when they log back in.
+	OutboxDisconnect = "disconnect"
+	// OutboxSpill moves overflow to a ring buffer of SpillSize messages,
+	// which overwrites its oldest message when it fills up.
+	OutboxSpill = "spill"
+)
+
+const (
+	defaultOutboxSize   = 64
+	defaultSlowAfter    = 5 * time.Second
+	defaultSpillSize    = 1024
+	defaultDelayedAfter = time.Second
+)
+
+// OutboxOptions configures a player's outbox. Zero values get defaults.
+type OutboxOptions struct {
+	Policy       string
+	Size         int           // messages waiting before the policy applies
+	SlowAfter    time.Duration // disconnect: how long the queue may stay full
+	SpillSize    int           // spill: capacity of the ring buffer
+	DelayedAfter time.Duration // messages taken later than this count as delayed
+}
+
+func (o OutboxOptions) withDefaults() OutboxOptions {
+	if o.Policy == "" {
+		o.Policy = OutboxCoalesce
+	}
+	if o.Size <= 0 {
+		o.Size = defaultOutboxSize
+	}
+	if o.SlowAfter <= 0 {
+		o.SlowAfter = defaultSlowAfter
+	}
+	if o.SpillSize <= 0 {
+		o.SpillSize = defaultSpillSize
+	}
+	if o.DelayedAfter <= 0 {
+		o.DelayedAfter = defaultDelayedAfter
+	}
+	return o
+}
+
+// OutboxStats counts what happened to the messages sent to one player.
+type OutboxStats struct {
+	Queued          int // waiting right now
+	Delivered       uint64
+	Coalesced       uint64
+	Spilled         uint64
+	Dropped         uint64
+	Delayed         uint64
+	MaxDelay        time.Duration
+	SlowDisconnects uint64
+}
+
+func (s OutboxStats) String() string {
+	return fmt.Sprintf("%d queued, %d delivered, %d coalesced, %d spilled, %d dropped, %d delayed (max %s), %d slow disconnects",
+		s.Queued, s.Delivered, s.Coalesced, s.Spilled, s.Dropped, s.Delayed, s.MaxDelay.Round(time.Millisecond), s.SlowDisconnects)
+}
+
+type queued struct {
+	msg    response.Message
+	repeat int // further copies merged in by coalescing
+	at     time.Time
+}
+
+// repeats reports whether msg can be merged into q.
+func (q queued) repeats(msg response.Message) bool {
+	return q.msg.Data == nil && msg.Data == nil &&
+		q.msg.Kind == msg.Kind && q.msg.Channel == msg.Channel && q.msg.Text == msg.Text
+}
+
+// Outbox holds the messages on their way to one player. The bus pushes to it
+// without ever blocking; the player's session waits on Ready and then Takes
+// everything waiting.
+type Outbox struct {
+	opts   OutboxOptions
+	onSlow func()
+	ready  chan struct{}
+
+	mu        sync.Mutex
+	queue     []queued
+	spill     ring
+	fullSince time.Time
+	stats     OutboxStats
+}
+
+// NewOutbox creates an outbox. onSlow is called, on its own goroutine, when
+// the disconnect policy gives up on the player.
+func NewOutbox(opts OutboxOptions, onSlow func()) *Outbox {
+	opts = opts.withDefaults()
+	o := &Outbox{
+		opts:   opts,
+		onSlow: onSlow,
+		ready:  make(chan struct{}, 1),
+	}
+	if opts.Policy == OutboxSpill {
+		o.spill.buf = make([]queued, opts.SpillSize)
+	}
+	return o
+}
+
+// Ready receives a value whenever messages are waiting to be taken.
+func (o *Outbox) Ready() <-chan struct{} {
+	return o.ready
+}
+
+// Push queues a message for the player.
+func (o *Outbox) Push(msg response.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	o.enqueue(queued{msg: msg, at: now}, now)
+
+	select {
+	case o.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) enqueue(q queued, now time.Time) {
+	full := len(o.queue) >= o.opts.Size
+
+	switch o.opts.Policy {
+	case OutboxCoalesce:
+		if n := len(o.queue); n > 0 && o.queue[n-1].repeats(q.msg) {
+			o.queue[n-1].repeat++
+			o.stats.Coalesced++
+			return
+		}
+		if full {
+			o.queue = o.queue[1:]
+			o.stats.Dropped++
+		}
+	case OutboxSpill:
+		// once anything has spilled, later messages follow it so order holds
+		if full || o.spill.n > 0 {
+			if o.spill.push(q) {
+				o.stats.Dropped++
+			}
+			o.stats.Spilled++
+			return
+		}
+	case OutboxDisconnect:
+		if full {
+			if o.fullSince.IsZero() {
+				o.fullSince = now
+			} else if now.Sub(o.fullSince) >= o.opts.SlowAfter {
+				o.fullSince = time.Time{}
+				o.stats.SlowDisconnects++
+				if o.onSlow != nil {
+					go o.onSlow()
+				}
+			}
+		}
+	}
+
+	o.queue = append(o.queue, q)
+}
+
+// Take removes and returns every waiting message, oldest first.
+func (o *Outbox) Take() []response.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	items := o.queue
+	o.queue = nil
+	o.fullSince = time.Time{}
+	for o.spill.n > 0 {
+		items = append(items, o.spill.pop())
+	}
+
+	msgs := make([]response.Message, len(items))
+	for i, q := range items {
+		msgs[i] = q.msg
+		if q.repeat > 0 && q.msg.HasText() {
+			msgs[i].Text = fmt.Sprintf("%s (x%d)", q.msg.Text, q.repeat+1)
+		}
+
+		delay := now.Sub(q.at)
+		if delay > o.opts.DelayedAfter {
+			o.stats.Delayed++
+		}
+		if delay > o.stats.MaxDelay {
+			o.stats.MaxDelay = delay
+		}
+	}
+	o.stats.Delivered += uint64(len(items))
+	return msgs
+}
+
+// Stats returns the outbox's counters so far.
+func (o *Outbox) Stats() OutboxStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	stats := o.stats
+	stats.Queued = len(o.queue) + o.spill.n
+	return stats
+}
+
+// OutboxStats returns the outbox counters of every player in the world, by
+// name.
+func (w *World) OutboxStats() map[string]OutboxStats {
+	stats := make(map[string]OutboxStats)
+	for _, p := range w.OnlinePlayers() {
+		if outbox, ok := w.bus.Outbox(p.Entity); ok {
+			stats[p.Name] = outbox.Stats()
+		}
+	}
+	return stats
+}
+
+func (w *World) outboxesCommand() response.Response {
+	stats := w.OutboxStats()
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s: %s\n", name, stats[name])
+	}
+	return response.Text{Value: b.String()}
+}
+
+// ring is a fixed-size FIFO that overwrites its oldest entry when full.
+type ring struct {
+	buf     []queued
+	head, n int
+}
+
+// push adds q, reporting whether the oldest entry was overwritten to make room.
+func (r *ring) push(q queued) bool {
+	if len(r.buf) == 0 {
+		return true
+	}
+	if r.n == len(r.buf) {
+		r.buf[r.head] = q
+		r.head = (r.head + 1) % len(r.buf)
+		return true
+	}
+	r.buf[(r.head+r.n)%len(r.buf)] = q
+	r.n++
+	return false
+}
+
+func (r *ring) pop() queued {
+	q := r.buf[r.head]
+	r.buf[r.head] = queued{}
+	r.head = (r.head + 1) % len(r.buf)
+	r.n--
+	return q
+}
@@ -0,0 +1,138 @@
+package world
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/world/response"
+)
+
+func texts(msgs []response.Message) []string {
+	out := make([]string, len(msgs))
+	for i, msg := range msgs {
+		out[i] = msg.Text
+	}
+	return out
+}
+
+func TestOutbox_Policies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		opts   OutboxOptions
+		pushed []string
+		want   []string
+		stats  OutboxStats
+	}{
+		{
+			name:   "under capacity",
+			opts:   OutboxOptions{Policy: OutboxCoalesce, Size: 4},
+			pushed: []string{"a", "b", "c"},
+			want:   []string{"a", "b", "c"},
+			stats:  OutboxStats{Delivered: 3},
+		},
+		{
+			name:   "coalesce merges repeats",
+			opts:   OutboxOptions{Policy: OutboxCoalesce, Size: 4},
+			pushed: []string{"a", "b", "b", "b", "a"},
+			want:   []string{"a", "b (x3)", "a"},
+			stats:  OutboxStats{Delivered: 3, Coalesced: 2},
+		},
+		{
+			name:   "coalesce drops the oldest when full",
+			opts:   OutboxOptions{Policy: OutboxCoalesce, Size: 2},
+			pushed: []string{"a", "b", "c", "d"},
+			want:   []string{"c", "d"},
+			stats:  OutboxStats{Delivered: 2, Dropped: 2},
+		},
+		{
+			name:   "spill keeps order",
+			opts:   OutboxOptions{Policy: OutboxSpill, Size: 2, SpillSize: 4},
+			pushed: []string{"a", "b", "c", "d"},
+			want:   []string{"a", "b", "c", "d"},
+			stats:  OutboxStats{Delivered: 4, Spilled: 2},
+		},
+		{
+			name:   "spill overwrites its oldest",
+			opts:   OutboxOptions{Policy: OutboxSpill, Size: 1, SpillSize: 2},
+			pushed: []string{"a", "b", "c", "d"},
+			want:   []string{"a", "c", "d"},
+			stats:  OutboxStats{Delivered: 3, Spilled: 3, Dropped: 1},
+		},
+		{
+			name:   "disconnect keeps everything",
+			opts:   OutboxOptions{Policy: OutboxDisconnect, Size: 2, SlowAfter: time.Hour},
+			pushed: []string{"a", "b", "c", "d"},
+			want:   []string{"a", "b", "c", "d"},
+			stats:  OutboxStats{Delivered: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := NewOutbox(tt.opts, nil)
+			for _, text := range tt.pushed {
+				o.Push(response.Narrative(text))
+			}
+			require.Equal(t, len(tt.pushed)-int(tt.stats.Coalesced)-int(tt.stats.Dropped), o.Stats().Queued)
+
+			select {
+			case <-o.Ready():
+			default:
+				t.Fatal("outbox not ready")
+			}
+			require.Equal(t, tt.want, texts(o.Take()))
+
+			stats := o.Stats()
+			stats.MaxDelay = 0
+			require.Equal(t, tt.stats, stats)
+		})
+	}
+}
+
+func TestOutbox_DisconnectsSlowConsumer(t *testing.T) {
+	t.Parallel()
+
+	slow := make(chan struct{}, 1)
+	opts := OutboxOptions{Policy: OutboxDisconnect, Size: 1, SlowAfter: 10 * time.Millisecond, DelayedAfter: 5 * time.Millisecond}
+	o := NewOutbox(opts, func() { slow <- struct{}{} })
+
+	o.Push(response.Narrative("a"))
+	o.Push(response.Narrative("b")) // full from here
+	time.Sleep(20 * time.Millisecond)
+	o.Push(response.Narrative("c"))
+
+	select {
+	case <-slow:
+	case <-time.After(time.Second):
+		t.Fatal("slow consumer not reported")
+	}
+	require.Equal(t, uint64(1), o.Stats().SlowDisconnects)
+	require.Equal(t, []string{"a", "b", "c"}, texts(o.Take()))
+
+	stats := o.Stats()
+	require.Equal(t, uint64(3), stats.Delivered)
+	require.Equal(t, uint64(2), stats.Delayed, "a and b waited out the slow period")
+	require.GreaterOrEqual(t, stats.MaxDelay, 20*time.Millisecond)
+}
+
+func TestOutbox_CoalescesByKind(t *testing.T) {
+	t.Parallel()
+
+	o := NewOutbox(OutboxOptions{}, nil)
+	o.Push(response.RoomChanged())
+	o.Push(response.RoomChanged())
+	o.Push(response.ChannelLine("ooc", "hi"))
+	o.Push(response.Narrative("hi"))
+
+	require.Equal(t, []response.Message{
+		response.RoomChanged(),
+		response.ChannelLine("ooc", "hi"),
+		response.Narrative("hi"),
+	}, o.Take())
+}
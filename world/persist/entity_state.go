@@ -0,0 +1,169 @@
+// Package persist saves and restores entity trees, such as a player and the
+// items they carry.
+package persist
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+)
+
+// EntityState is the saved form of an entity instance. It names the template
+// the entity was copied from and records only what differs from it, plus the
+// entity's children.
+type EntityState struct {
+	ID          string                    `json:"id"`
+	TemplateID  string                    `json:"templateId"`
+	Name        string                    `json:"name,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Aliases     []string                  `json:"aliases,omitempty"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Fields      map[string]models.Value   `json:"fields,omitempty"`
+	Children    map[string]*ChildrenState `json:"children,omitempty"` // by component, e.g. "Inventory"
+}
+
+// ChildrenState is the saved contents of one child-holding component.
+type ChildrenState struct {
+	Revealed bool           `json:"revealed"`
+	Entities []*EntityState `json:"entities"`
+}
+
+// Capture records an entity and its children as overrides of their templates.
+func Capture(e *entities.Entity, templates map[string]*entities.Entity) *EntityState {
+	c := &capturer{templates: templates}
+	return c.capture(e)
+}
+
+type capturer struct {
+	templates map[string]*entities.Entity // nil records every entity in full
+	skip      func(e *entities.Entity) bool
+}
+
+func (c *capturer) capture(e *entities.Entity) *EntityState {
+	s := &EntityState{ID: e.ID, TemplateID: e.TemplateID}
+
+	template, ok := c.templates[e.TemplateID]
+	if !ok {
+		template = &entities.Entity{}
+	}
+
+	if e.Name != template.Name {
+		s.Name = e.Name
+	}
+	if e.Description != template.Description {
+		s.Description = e.Description
+	}
+	if !slices.Equal(e.Aliases, template.Aliases) {
+		s.Aliases = e.Aliases
+	}
+	if !slices.Equal(e.Tags, template.Tags) {
+		s.Tags = e.Tags
+	}
+
+	for k, v := range e.Fields {
+		if tv, ok := template.Fields[k]; ok && reflect.DeepEqual(tv, v) {
+			continue
+		}
+		if s.Fields == nil {
+			s.Fields = make(map[string]models.Value)
+		}
+		s.Fields[k] = v
+	}
+
+	for _, cwc := range e.GetComponentsWithChildren() {
+		children := cwc.GetChildren()
+		cs := &ChildrenState{
+			Revealed: children.GetRevealed(),
+			Entities: []*EntityState{},
+		}
+		for _, child := range children.GetChildren() {
+			if c.skip != nil && c.skip(child) {
+				continue
+			}
+			cs.Entities = append(cs.Entities, c.capture(child))
+		}
+
+		if s.Children == nil {
+			s.Children = make(map[string]*ChildrenState)
+		}
+		s.Children[cwc.(entities.Component).Id().String()] = cs
+	}
+
+	return s
+}
+
+// Restore rebuilds an entity from its saved state by copying its template,
+// applying the overrides, and replacing the template's children with the
+// saved ones. Restored entities keep their saved instance IDs. Children whose
+// templates no longer exist are dropped.
+func Restore(s *EntityState, templates map[string]*entities.Entity, parent entities.ComponentWithChildren) (*entities.Entity, error) {
+	r := &restorer{templates: templates}
+	return r.restore(s, parent)
+}
+
+type restorer struct {
+	templates map[string]*entities.Entity
+	visit     func(e *entities.Entity) // called on every restored entity
+}
+
+func (r *restorer) restore(s *EntityState, parent entities.ComponentWithChildren) (*entities.Entity, error) {
+	template, ok := r.templates[s.TemplateID]
+	if !ok {
+		return nil, fmt.Errorf("restore: template '%s' does not exist", s.TemplateID)
+	}
+
+	e := template.Copy(parent)
+	if s.ID != "" {
+		e.ID = s.ID
+	}
+
+	if s.Name != "" {
+		e.Name = s.Name
+	}
+	if s.Description != "" {
+		e.Description = s.Description
+	}
+	if s.Aliases != nil {
+		e.Aliases = s.Aliases
+	}
+	if s.Tags != nil {
+		e.Tags = s.Tags
+	}
+	for k, v := range s.Fields {
+		e.Fields[k] = v
+	}
+
+	for _, cwc := range e.GetComponentsWithChildren() {
+		children := cwc.GetChildren()
+		saved, ok := s.Children[cwc.(entities.Component).Id().String()]
+		if !ok {
+			// saved before the template had this component, keep its defaults
+			continue
+		}
+
+		for _, child := range children.GetChildren() {
+			cwc.RemoveChild(child)
+		}
+		children.SetRevealed(saved.Revealed)
+
+		for _, cs := range saved.Entities {
+			child, err := r.restore(cs, cwc)
+			if err != nil {
+				fmt.Printf("restore '%s': dropping child: %v\n", s.TemplateID, err)
+				continue
+			}
+			if err := cwc.AddChild(child); err != nil {
+				return nil, fmt.Errorf("restore '%s': %w", s.TemplateID, err)
+			}
+		}
+	}
+
+	if r.visit != nil {
+		r.visit(e)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,91 @@
+package persist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureRestore(t *testing.T) {
+	t.Parallel()
+
+	newTemplate := func(id string, fields map[string]models.Value) *entities.Entity {
+		e := entities.NewEntity(id, id+" desc", []string{id}, nil, fields, nil)
+		e.TemplateID = id
+		return e
+	}
+
+	egg := newTemplate("egg", map[string]models.Value{"angry": models.VBool(false)})
+	box := newTemplate("box", map[string]models.Value{})
+	box.Add(components.NewContainer())
+	nickel := newTemplate("nickel", map[string]models.Value{})
+
+	hero := newTemplate("hero", map[string]models.Value{"hp": models.VInt(10)})
+	inventory := components.NewInventory()
+	hero.Add(inventory)
+	require.NoError(t, inventory.AddChild(egg.Copy(inventory)))
+
+	templates := map[string]*entities.Entity{
+		"egg":    egg,
+		"box":    box,
+		"nickel": nickel,
+		"hero":   hero,
+	}
+
+	// the instance has drifted from its template: the egg was swapped for a
+	// box holding an angry egg and a nickel, and the hero was renamed
+	instance := hero.Copy(nil)
+	instance.Name = "Bob"
+	instInv, ok := entities.GetComponent[*components.Inventory](instance)
+	require.True(t, ok)
+	for _, child := range instInv.GetChildren().GetChildren() {
+		instInv.RemoveChild(child)
+	}
+	boxInst := box.Copy(instInv)
+	require.NoError(t, instInv.AddChild(boxInst))
+	container, ok := entities.GetComponent[*components.Container](boxInst)
+	require.True(t, ok)
+	angryEgg := egg.Copy(container)
+	angryEgg.Fields["angry"] = models.VBool(true)
+	require.NoError(t, container.AddChild(angryEgg))
+	require.NoError(t, container.AddChild(nickel.Copy(container)))
+
+	state := Capture(instance, templates)
+	require.Equal(t, "hero", state.TemplateID)
+	require.Equal(t, "Bob", state.Name)
+	require.Empty(t, state.Fields, "unchanged fields should not be saved")
+
+	// survive a trip through JSON like the file store does
+	data, err := json.Marshal(state)
+	require.NoError(t, err)
+	var decoded EntityState
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	restored, err := Restore(&decoded, templates, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Bob", restored.Name)
+	require.Equal(t, models.VInt(10), restored.Fields["hp"])
+
+	restoredInv, ok := entities.GetComponent[*components.Inventory](restored)
+	require.True(t, ok)
+	items := restoredInv.GetChildren().GetChildren()
+	require.Len(t, items, 1)
+	require.Equal(t, "box", items[0].TemplateID)
+
+	restoredBox, ok := entities.GetComponent[*components.Container](items[0])
+	require.True(t, ok)
+	contents := map[string]*entities.Entity{}
+	for _, c := range restoredBox.GetChildren().GetChildren() {
+		contents[c.TemplateID] = c
+	}
+	require.Len(t, contents, 2)
+	require.Equal(t, models.VBool(true), contents["egg"].Fields["angry"])
+	require.Contains(t, contents, "nickel")
+
+	_, err = Restore(&EntityState{TemplateID: "missing"}, templates, nil)
+	require.Error(t, err)
+}
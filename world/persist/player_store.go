@@ -0,0 +1,85 @@
+package persist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/mud/utils"
+)
+
+var ErrNotFound = errors.New("no saved state")
+
+// PlayerState is everything needed to put a player back where they left off.
+type PlayerState struct {
+	Name    string       `json:"name"`
+	RoomID  string       `json:"roomId"`
+	Entity  *EntityState `json:"entity"`
+	SavedAt time.Time    `json:"savedAt"`
+}
+
+// PlayerStore persists player state by name. Names are matched
+// case-insensitively; LoadPlayer returns ErrNotFound for unknown names.
+type PlayerStore interface {
+	LoadPlayer(name string) (*PlayerState, error)
+	SavePlayer(s *PlayerState) error
+}
+
+// FileStore keeps each player's state as a JSON file in a directory.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+var _ PlayerStore = &FileStore{}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create player directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (f *FileStore) path(name string) string {
+	return filepath.Join(f.dir, strings.ToLower(name)+".json")
+}
+
+func (f *FileStore) LoadPlayer(name string) (*PlayerState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read player '%s': %w", name, err)
+	}
+
+	var s PlayerState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decode player '%s': %w", name, err)
+	}
+	return &s, nil
+}
+
+func (f *FileStore) SavePlayer(s *PlayerState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode player '%s': %w", s.Name, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := utils.WriteFileAtomic(f.path(s.Name), data); err != nil {
+		return fmt.Errorf("save player '%s': %w", s.Name, err)
+	}
+	return nil
+}
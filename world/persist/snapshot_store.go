@@ -0,0 +1,134 @@
+package persist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"example.com/mud/utils"
+)
+
+const snapshotTimeFormat = "20060102-150405.000"
+
+// SnapshotStore keeps world snapshots as timestamped JSON files in a
+// directory, dropping the oldest once there are more than keep of them.
+type SnapshotStore struct {
+	dir  string
+	keep int
+	mu   sync.Mutex
+}
+
+func NewSnapshotStore(dir string, keep int) (*SnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create snapshot directory: %w", err)
+	}
+	return &SnapshotStore{dir: dir, keep: keep}, nil
+}
+
+// Save writes the snapshot and returns the name it can be loaded by.
+func (ss *SnapshotStore) Save(s *WorldSnapshot) (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	name := s.SavedAt.UTC().Format(snapshotTimeFormat)
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if err := utils.WriteFileAtomic(filepath.Join(ss.dir, name+".json"), data); err != nil {
+		return "", fmt.Errorf("save snapshot: %w", err)
+	}
+
+	if err := ss.prune(); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// Load reads the snapshot with the given name, as returned by Save or List.
+func (ss *SnapshotStore) Load(name string) (*WorldSnapshot, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, ErrNotFound
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(ss.dir, name+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot '%s': %w", name, err)
+	}
+
+	var s WorldSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decode snapshot '%s': %w", name, err)
+	}
+	return &s, nil
+}
+
+// Latest returns the name of the newest snapshot, or ErrNotFound if there are
+// none.
+func (ss *SnapshotStore) Latest() (string, error) {
+	names, err := ss.List()
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", ErrNotFound
+	}
+	return names[len(names)-1], nil
+}
+
+// List returns the names of the saved snapshots, oldest first.
+func (ss *SnapshotStore) List() ([]string, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.list()
+}
+
+func (ss *SnapshotStore) list() ([]string, error) {
+	dirEntries, err := os.ReadDir(ss.dir)
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots: %w", err)
+	}
+
+	var names []string
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(de.Name(), ".json"))
+	}
+	// the time format sorts chronologically
+	sort.Strings(names)
+	return names, nil
+}
+
+func (ss *SnapshotStore) prune() error {
+	if ss.keep <= 0 {
+		return nil
+	}
+
+	names, err := ss.list()
+	if err != nil {
+		return err
+	}
+	for len(names) > ss.keep {
+		if err := os.Remove(filepath.Join(ss.dir, names[0]+".json")); err != nil {
+			return fmt.Errorf("prune snapshots: %w", err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
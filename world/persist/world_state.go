@@ -0,0 +1,112 @@
+package persist
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"example.com/mud/world/entities"
+)
+
+// WorldSnapshot is the whole world at one moment: every entity outside of a
+// player, and the scheduled jobs that were waiting to run.
+type WorldSnapshot struct {
+	SavedAt time.Time               `json:"savedAt"`
+	Roots   map[string]*EntityState `json:"roots"` // entity map ID -> top-level entity
+	Jobs    []*JobState             `json:"jobs"`
+}
+
+// JobState is a pending scheduler job, saved as the key of the action that
+// scheduled it and the event it will run against.
+type JobState struct {
+	Key     string      `json:"key"`
+	NextRun time.Time   `json:"nextRun"`
+	Event   *EventState `json:"event"`
+}
+
+// EventState holds the instance IDs of the event's entities. Entities that
+// no longer exist when the job is resumed are left out of the event.
+type EventState struct {
+	Type         string `json:"type"`
+	Message      string `json:"message,omitempty"`
+	RoomID       string `json:"roomId,omitempty"`
+	SourceID     string `json:"sourceId,omitempty"`
+	TargetID     string `json:"targetId,omitempty"`
+	InstrumentID string `json:"instrumentId,omitempty"`
+}
+
+// CaptureWorld records every top-level entity in the entity map in full,
+// skipping anything skip reports true for (players are saved on their own).
+func CaptureWorld(entityMap map[string]*entities.Entity, skip func(e *entities.Entity) bool) *WorldSnapshot {
+	c := &capturer{skip: skip}
+
+	snapshot := &WorldSnapshot{
+		SavedAt: time.Now(),
+		Roots:   make(map[string]*EntityState),
+	}
+	for _, id := range sortedIDs(entityMap) {
+		e := entityMap[id]
+		if e.Parent != nil || (skip != nil && skip(e)) {
+			continue
+		}
+		snapshot.Roots[id] = c.capture(e)
+	}
+
+	return snapshot
+}
+
+// RestoreWorld rebuilds an entity map from a snapshot, using the current
+// entity map as templates. Entries missing from the snapshot are kept as they
+// are. Entries that aren't top-level (an item that lives in a room) are
+// pointed at the restored entity with the same instance ID, or else the first
+// one built from the same template.
+func RestoreWorld(s *WorldSnapshot, templates map[string]*entities.Entity) (map[string]*entities.Entity, error) {
+	byID := make(map[string]*entities.Entity)
+	byTemplate := make(map[string]*entities.Entity)
+	r := &restorer{
+		templates: templates,
+		visit: func(e *entities.Entity) {
+			byID[e.ID] = e
+			if _, ok := byTemplate[e.TemplateID]; !ok {
+				byTemplate[e.TemplateID] = e
+			}
+		},
+	}
+
+	restored := make(map[string]*entities.Entity, len(templates))
+	for _, id := range sortedIDs(s.Roots) {
+		e, err := r.restore(s.Roots[id], nil)
+		if err != nil {
+			return nil, fmt.Errorf("restore world: '%s': %w", id, err)
+		}
+		restored[id] = e
+	}
+
+	for id, e := range templates {
+		if _, ok := restored[id]; ok {
+			continue
+		}
+		if e.Parent != nil {
+			if moved, ok := byID[e.ID]; ok {
+				restored[id] = moved
+				continue
+			}
+			if moved, ok := byTemplate[e.TemplateID]; ok {
+				restored[id] = moved
+				continue
+			}
+		}
+		restored[id] = e
+	}
+
+	return restored, nil
+}
+
+func sortedIDs[V any](m map[string]V) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,70 @@
+package persist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureRestoreWorld(t *testing.T) {
+	t.Parallel()
+
+	newEntity := func(id string) *entities.Entity {
+		e := entities.NewEntity(id, id+" desc", []string{id}, nil, map[string]models.Value{}, nil)
+		e.TemplateID = id
+		return e
+	}
+
+	hall := newEntity("hall")
+	hallRoom := components.NewRoom()
+	hall.Add(hallRoom)
+	cellar := newEntity("cellar")
+	cellarRoom := components.NewRoom()
+	cellar.Add(cellarRoom)
+
+	// like a plugin world, the sword in the map is the one in the hall
+	sword := newEntity("sword")
+	require.NoError(t, hallRoom.AddChild(sword))
+	hero := newEntity("hero")
+	require.NoError(t, cellarRoom.AddChild(hero))
+
+	entityMap := map[string]*entities.Entity{
+		"hall":   hall,
+		"cellar": cellar,
+		"sword":  sword,
+	}
+
+	// the sword was carried down to the cellar and dulled on the way
+	hallRoom.RemoveChild(sword)
+	require.NoError(t, cellarRoom.AddChild(sword))
+	sword.Fields["sharp"] = models.VBool(false)
+
+	snapshot := CaptureWorld(entityMap, func(e *entities.Entity) bool { return e == hero })
+	require.Len(t, snapshot.Roots, 2, "only top-level entities are roots")
+
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	var decoded WorldSnapshot
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	restored, err := RestoreWorld(&decoded, entityMap)
+	require.NoError(t, err)
+	require.Len(t, restored, 3)
+
+	restoredHall, ok := entities.GetComponent[*components.Room](restored["hall"])
+	require.True(t, ok)
+	require.Empty(t, restoredHall.GetChildren().GetChildren())
+
+	restoredCellar, ok := entities.GetComponent[*components.Room](restored["cellar"])
+	require.True(t, ok)
+	children := restoredCellar.GetChildren().GetChildren()
+	require.Len(t, children, 1, "the hero is not part of the world snapshot")
+	require.Same(t, children[0], restored["sword"], "map entries follow their entity")
+	require.Equal(t, sword.ID, children[0].ID, "instance IDs survive a restore")
+	require.Equal(t, models.VBool(false), restored["sword"].Fields["sharp"])
+	require.NotSame(t, sword, restored["sword"])
+}
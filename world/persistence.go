@@ -0,0 +1,97 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/persist"
+	"example.com/mud/world/player"
+	"example.com/mud/world/scheduler"
+)
+
+// SetPlayerStore enables saving players when they leave and restoring them
+// when they come back.
+func (w *World) SetPlayerStore(store persist.PlayerStore) {
+	w.playerStore = store
+}
+
+// StartAutosave saves every online player on the given interval, so a crash
+// loses at most one interval of progress.
+func (w *World) StartAutosave(interval time.Duration) {
+	var schedule func(next time.Time)
+
+	schedule = func(next time.Time) {
+		w.Scheduler.Add(&scheduler.Job{
+			NextRun: next,
+			RunFunc: func() {
+				w.SavePlayers()
+				schedule(next.Add(interval))
+			},
+		})
+	}
+
+	schedule(time.Now().Add(interval))
+}
+
+// SavePlayers saves every online player.
+func (w *World) SavePlayers() {
+	for _, p := range w.OnlinePlayers() {
+		if err := w.savePlayer(p); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+func (w *World) savePlayer(p *player.Player) error {
+	if w.playerStore == nil {
+		return nil
+	}
+
+	state := &persist.PlayerState{
+		Name:    p.Name,
+		RoomID:  p.CurrentRoom().TemplateID,
+		Entity:  persist.Capture(p.Entity, w.EntitiesById()),
+		SavedAt: time.Now(),
+	}
+	if err := w.playerStore.SavePlayer(state); err != nil {
+		return fmt.Errorf("save player '%s': %w", p.Name, err)
+	}
+	return nil
+}
+
+// restorePlayer puts a newly created player back in the state they were saved
+// in, if any. A player whose room no longer exists stays where they are.
+func (w *World) restorePlayer(p *player.Player) error {
+	if w.playerStore == nil {
+		return nil
+	}
+
+	state, err := w.playerStore.LoadPlayer(p.Name)
+	if errors.Is(err, persist.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("restore player '%s': %w", p.Name, err)
+	}
+
+	entityMap := w.EntitiesById()
+	restored, err := persist.Restore(state.Entity, entityMap, nil)
+	if err != nil {
+		return fmt.Errorf("restore player '%s': %w", p.Name, err)
+	}
+	// the account name is authoritative, whatever was saved
+	restored.Name = p.Entity.Name
+	restored.Aliases = p.Entity.Aliases
+	p.Entity = restored
+
+	if room, ok := entityMap[state.RoomID]; ok {
+		if _, isRoom := entities.GetComponent[*components.Room](room); isRoom {
+			p.SetCurrentRoom(room)
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package player
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"strings"
@@ -19,15 +21,21 @@ import (
 var safeNameRegex = regexp.MustCompile(`[^a-zA-Z]+`)
 
 type Player struct {
-	Name        string
-	Entity      *entities.Entity
-	CurrentRoom *entities.Entity
-	Pending     *entities.PendingAction
+	Name    string
+	Entity  *entities.Entity
+	Pending *entities.PendingAction
 
 	mu            sync.Mutex
+	currentRoom   *entities.Entity
 	nextActionAt  time.Time
 	trackingAlias string
 	world         World
+
+	joinedAt   time.Time
+	connection string          // protocol, "" while link-dead
+	ctx        context.Context // the connection's, nil while never connected
+	lastActive time.Time
+	role       models.Role
 }
 
 type World interface {
@@ -39,6 +47,7 @@ type World interface {
 	PublishTo(room *entities.Entity, recipient *entities.Entity, text string)
 
 	GetScheduler() *scheduler.Scheduler
+	entities.Registry
 }
 
 func NewPlayer(name string, world World, currentRoom *entities.Entity) (*Player, error) {
@@ -52,11 +61,14 @@ func NewPlayer(name string, world World, currentRoom *entities.Entity) (*Player,
 	playerEntity.Description = fmt.Sprintf("%s the brave hero is here.", name)
 	playerEntity.Aliases = []string{strings.ToLower(name)}
 
+	now := time.Now()
 	return &Player{
 		Name:        name,
 		Entity:      playerEntity,
-		CurrentRoom: currentRoom,
+		currentRoom: currentRoom,
 		world:       world,
+		joinedAt:    now,
+		lastActive:  now,
 	}, nil
 }
 
@@ -100,8 +112,40 @@ func (p *Player) StartCooldown(d time.Duration) {
 	p.mu.Unlock()
 }
 
+// CurrentRoom returns the room the player is in.
+func (p *Player) CurrentRoom() *entities.Entity {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.currentRoom
+}
+
+// SetCurrentRoom records that the player is now in room. It only changes
+// where the player is looked for; the world moves their entity.
+func (p *Player) SetCurrentRoom(room *entities.Entity) {
+	p.mu.Lock()
+	p.currentRoom = room
+	p.mu.Unlock()
+}
+
+// Role returns what the player's account is allowed to do.
+func (p *Player) Role() models.Role {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.role == "" {
+		return models.RolePlayer
+	}
+	return p.role
+}
+
+func (p *Player) SetRole(role models.Role) {
+	p.mu.Lock()
+	p.role = role
+	p.mu.Unlock()
+}
+
 func (p *Player) GetRoomDescription() (response.RoomDescription, error) {
-	room, err := entities.RequireComponent[*components.Room](p.CurrentRoom)
+	current := p.CurrentRoom()
+	room, err := entities.RequireComponent[*components.Room](current)
 	if err != nil {
 		return response.RoomDescription{}, err
 	}
@@ -122,16 +166,19 @@ func (p *Player) GetRoomDescription() (response.RoomDescription, error) {
 	})
 
 	// Collect exit directions
-	exits := make([]string, 0, len(room.Exits))
-	for dir := range room.Exits {
+	exitIDs := room.Exits()
+	exits := make([]string, 0, len(exitIDs))
+	for dir := range exitIDs {
 		exits = append(exits, dir)
 	}
 	slices.Sort(exits)
 
 	return response.RoomDescription{
-		Name:        p.CurrentRoom.Name,
-		Description: strings.TrimSpace(p.CurrentRoom.Description),
+		ID:          current.TemplateID,
+		Name:        current.Name,
+		Description: strings.TrimSpace(current.Description),
 		Exits:       exits,
+		ExitIDs:     maps.Clone(exitIDs),
 		Children:    children,
 	}, nil
 }
@@ -220,8 +267,9 @@ func (p *Player) ActMessage(action, message, noMatchMessage string) (response.Te
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		Registry:     p.world,
 		EntitiesById: p.world.EntitiesById(),
-		Room:         p.CurrentRoom,
+		Room:         p.CurrentRoom(),
 		Source:       p.Entity,
 		Message:      message,
 	}, noMatchMessage)
@@ -263,8 +311,9 @@ func (p *Player) actUponEntity(action string, target *entities.Entity, noMatchMe
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		Registry:     p.world,
 		EntitiesById: p.world.EntitiesById(),
-		Room:         p.CurrentRoom,
+		Room:         p.CurrentRoom(),
 		Source:       p.Entity,
 		Target:       target,
 	}, noMatchMessage)
@@ -305,8 +354,9 @@ func (p *Player) actUponMessageEntity(action string, target *entities.Entity, me
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		Registry:     p.world,
 		EntitiesById: p.world.EntitiesById(),
-		Room:         p.CurrentRoom,
+		Room:         p.CurrentRoom(),
 		Source:       p.Entity,
 		Target:       target,
 		Message:      message,
@@ -378,8 +428,9 @@ func (p *Player) actUponWithEntities(action string, target, instrument *entities
 		Type:         action,
 		Publisher:    p.world,
 		Scheduler:    p.world.GetScheduler(),
+		Registry:     p.world,
 		EntitiesById: p.world.EntitiesById(),
-		Room:         p.CurrentRoom,
+		Room:         p.CurrentRoom(),
 		Source:       p.Entity,
 		Instrument:   instrument,
 		Target:       target,
@@ -391,6 +442,7 @@ func (p *Player) sendEventToEntity(entity *entities.Entity, event *entities.Even
 		return "", fmt.Errorf("player '%s' send event nil entity", p.Name)
 	}
 
+	event.Ctx = p.Context()
 	if reactor, ok := entity.GetReactor(); ok {
 		match, err := reactor.OnEvent(event)
 		if err != nil {
@@ -419,15 +471,16 @@ func (p *Player) getEntitiesByAlias(alias string) ([]entities.AmbiguityOption, e
 	eMatches := make([]entities.AmbiguityOption, 0, 10)
 
 	// check if the room itself has a matching alias
-	if slices.Contains(p.CurrentRoom.Aliases, alias) {
+	current := p.CurrentRoom()
+	if slices.Contains(current.Aliases, alias) {
 		eMatches = append(eMatches, entities.AmbiguityOption{
-			Text:   fmt.Sprintf("The room: %s", p.CurrentRoom.Name),
-			Entity: p.CurrentRoom,
+			Text:   fmt.Sprintf("The room: %s", current.Name),
+			Entity: current,
 		})
 	}
 
 	// look for matches in the room
-	room, err := entities.RequireComponent[*components.Room](p.CurrentRoom)
+	room, err := entities.RequireComponent[*components.Room](current)
 	if err != nil {
 		return nil, fmt.Errorf("getEntityByAlias for player '%s': %w", p.Name, err)
 	} else {
@@ -446,7 +499,8 @@ func (p *Player) getEntitiesByAlias(alias string) ([]entities.AmbiguityOption, e
 	return eMatches, nil
 }
 
-// renderForTelnet converts a Response to a plain-text string for the telnet client.
+// RenderForTelnet converts a Response to a plain-text string for telnet and
+// other terminal clients, such as SSH.
 // ANSI formatting is applied here so the wire format stays clean.
 func RenderForTelnet(r response.Response) (string, error) {
 	switch v := r.(type) {
@@ -509,6 +563,13 @@ func RenderForTelnet(r response.Response) (string, error) {
 	case response.Text:
 		return v.Value, nil
 
+	case response.Prompt:
+		return v.Value, nil
+
+	case response.Resume:
+		// telnet players resume by logging in again
+		return "", nil
+
 	default:
 		return fmt.Sprintf("%+v", v), nil
 	}
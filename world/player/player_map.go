@@ -27,12 +27,12 @@ func (p *Player) MapCommand() (response.Response, error) {
 }
 
 func (p *Player) Map() (response.MapView, error) {
-	coordByRoom, err := assignCoordinates(p.CurrentRoom, p.world, 8)
+	coordByRoom, err := assignCoordinates(p.CurrentRoom(), p.world, 8)
 	if err != nil {
 		return response.MapView{}, fmt.Errorf("map: assign coordinates: %w", err)
 	}
 
-	currentRoom, err := entities.RequireComponent[*components.Room](p.CurrentRoom)
+	currentRoom, err := entities.RequireComponent[*components.Room](p.CurrentRoom())
 	if err != nil {
 		return response.MapView{}, fmt.Errorf("cannot map non-room area: %w", err)
 	}
@@ -98,7 +98,7 @@ func assignCoordinates(start *entities.Entity, world World, maxDepth int) (map[*
 		roomAtCoord[c] = r
 
 		// Expand neighbors in deterministic NESW order
-		exits := r.Exits
+		exits := r.Exits()
 		for _, dir := range dirOrder {
 			roomID, ok := exits[dir]
 			if !ok {
@@ -183,7 +183,7 @@ func (p *Player) renderMap(coordByRoom map[*components.Room]coord, currentRoom *
 			grid[gy][gx].Icon = "tracked-space"
 		}
 
-		for _, roomId := range r.Exits {
+		for _, roomId := range r.Exits() {
 			roomEntity, ok := world.GetEntityById(roomId)
 			if !ok {
 				return nil, 0, 0, fmt.Errorf("entity with id '%s' does not exist", roomId)
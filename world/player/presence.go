@@ -0,0 +1,63 @@
+package player
+
+import (
+	"context"
+	"time"
+)
+
+// Connect records that the player is playing over a connection of the given
+// protocol, one of the config.Protocol values, whose context is cancelled
+// when it drops. Connecting counts as activity.
+func (p *Player) Connect(ctx context.Context, protocol string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.connection = protocol
+	p.ctx = ctx
+	p.lastActive = time.Now()
+}
+
+// Context returns the context of the connection the player is playing over,
+// for anything slow done on their behalf to give up on when it drops.
+func (p *Player) Context() context.Context {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+// LinkDead records that the player's connection dropped while they stayed in
+// the world.
+func (p *Player) LinkDead() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.connection = ""
+}
+
+// Touch records that the player just sent something.
+func (p *Player) Touch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastActive = time.Now()
+}
+
+// Connection returns the protocol the player is connected with, or "" while
+// they're link-dead.
+func (p *Player) Connection() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.connection
+}
+
+// Idle returns how long it's been since the player last sent anything.
+func (p *Player) Idle() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return time.Since(p.lastActive)
+}
+
+// Online returns how long the player has been in the world.
+func (p *Player) Online() time.Duration {
+	return time.Since(p.joinedAt)
+}
@@ -0,0 +1,21 @@
+package world
+
+import "example.com/mud/world/response"
+
+// PluginReporter describes how the game plugin the world comes from is
+// doing.
+type PluginReporter interface {
+	Report() string
+}
+
+// SetPluginReporter gives admins a report on the game plugin.
+func (w *World) SetPluginReporter(r PluginReporter) {
+	w.plugin = r
+}
+
+func (w *World) pluginStatsCommand() response.Response {
+	if w.plugin == nil {
+		return response.Text{Value: "The world doesn't come from a game plugin."}
+	}
+	return response.Text{Value: w.plugin.Report()}
+}
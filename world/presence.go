@@ -0,0 +1,95 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// Presence is a snapshot of one online player, for who lists.
+type Presence struct {
+	Name       string
+	Room       *entities.Entity
+	Connection string // protocol, "" while link-dead
+	Idle       time.Duration
+	Online     time.Duration
+}
+
+func presenceOf(p *player.Player) Presence {
+	return Presence{
+		Name:       p.Name,
+		Room:       p.CurrentRoom(),
+		Connection: p.Connection(),
+		Idle:       p.Idle(),
+		Online:     p.Online(),
+	}
+}
+
+// Players returns the presence of every online player, by name.
+func (w *World) Players() []Presence {
+	players := w.OnlinePlayers()
+	list := make([]Presence, 0, len(players))
+	for _, p := range players {
+		list = append(list, presenceOf(p))
+	}
+	slices.SortFunc(list, func(a, b Presence) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list
+}
+
+// PlayerPresence returns the presence of the online player with the given
+// name, in any case.
+func (w *World) PlayerPresence(name string) (Presence, bool) {
+	p, ok := w.FindPlayer(name)
+	if !ok {
+		return Presence{}, false
+	}
+	return presenceOf(p), true
+}
+
+// whoCommand lists who's online. Admins also see where everyone is.
+func (w *World) whoCommand(p *player.Player) response.Response {
+	players := w.Players()
+	admin := w.RoleOf(p).Allows(models.RoleAdmin)
+
+	var b strings.Builder
+	if len(players) == 1 {
+		b.WriteString("1 player is online:\n")
+	} else {
+		fmt.Fprintf(&b, "%d players are online:\n", len(players))
+	}
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, pr := range players {
+		connection := pr.Connection
+		if connection == "" {
+			connection = "link-dead"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\tidle %s", pr.Name, connection, formatIdle(pr.Idle))
+		if admin {
+			fmt.Fprintf(tw, "\t%s (%s)", pr.Room.Name, pr.Room.TemplateID)
+		}
+		fmt.Fprintln(tw)
+	}
+	_ = tw.Flush()
+	return response.Text{Value: strings.TrimRight(b.String(), "\n")}
+}
+
+// formatIdle rounds d down to its largest unit, as in "12s", "5m" or "2h".
+func formatIdle(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
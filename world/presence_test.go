@@ -0,0 +1,74 @@
+package world
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/world/response"
+	"example.com/mud/world/worldtest"
+)
+
+func TestWorld_Players(t *testing.T) {
+	t.Parallel()
+
+	hall := worldtest.Room("Hall", nil)
+	hall.Name = "Great Hall"
+	w := NewWorld(worldtest.Entities(hall), "Hall")
+	w.SetAdmins([]string{"Alice"})
+
+	for _, name := range []string{"Bob", "Alice"} {
+		_, err := w.AddPlayer(name, NewOutbox(OutboxOptions{}, nil))
+		require.NoError(t, err)
+	}
+	alice, _ := w.FindPlayer("alice")
+	bob, _ := w.FindPlayer("BOB")
+	alice.Connect(context.Background(), "telnet")
+	bob.Connect(context.Background(), "wss")
+	bob.LinkDead()
+
+	players := w.Players()
+	require.Len(t, players, 2)
+	require.Equal(t, "Alice", players[0].Name)
+	require.Equal(t, "telnet", players[0].Connection)
+	require.Equal(t, hall, players[0].Room)
+	require.Equal(t, "Bob", players[1].Name)
+	require.Empty(t, players[1].Connection)
+
+	time.Sleep(10 * time.Millisecond)
+	alice.Touch()
+	presence, ok := w.PlayerPresence("ALICE")
+	require.True(t, ok)
+	require.Less(t, presence.Idle, 10*time.Millisecond)
+	require.GreaterOrEqual(t, presence.Online, 10*time.Millisecond)
+	_, ok = w.PlayerPresence("Carol")
+	require.False(t, ok)
+
+	tests := []struct {
+		name   string
+		viewer string
+		want   []string
+	}{
+		{
+			name:   "player",
+			viewer: "Bob",
+			want:   []string{"2 players are online:", "  Alice  telnet     idle 0s", "  Bob    link-dead  idle 0s"},
+		},
+		{
+			name:   "admin sees rooms",
+			viewer: "Alice",
+			want:   []string{"2 players are online:", "  Alice  telnet     idle 0s  Great Hall (Hall)", "  Bob    link-dead  idle 0s  Great Hall (Hall)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viewer, _ := w.FindPlayer(tt.viewer)
+			resp := w.whoCommand(viewer)
+			require.Equal(t, tt.want, strings.Split(resp.(response.Text).Value, "\n"))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package world
+
+import (
+	"example.com/mud/world/entities"
+)
+
+var _ entities.Registry = &World{}
+
+// Register records e and everything it holds under their instance IDs, and
+// listens for changes to their children.
+func (w *World) Register(e *entities.Entity) {
+	w.registryMu.Lock()
+	defer w.registryMu.Unlock()
+	e.Walk(func(e *entities.Entity) {
+		w.registry[e.ID] = e
+		e.ListenForChildren(w.childrenChanged)
+	})
+}
+
+// Unregister forgets e and everything it holds.
+func (w *World) Unregister(e *entities.Entity) {
+	w.registryMu.Lock()
+	defer w.registryMu.Unlock()
+	e.Walk(func(e *entities.Entity) {
+		if w.registry[e.ID] == e {
+			delete(w.registry, e.ID)
+		}
+	})
+}
+
+// Moved tells observers that e has moved, naming the rooms it moved between.
+// Moves that don't start and end in a room, like an item taken out of the
+// world's templates, go unreported.
+func (w *World) Moved(e, from, to *entities.Entity) {
+	fromRoom, ok := roomOf(from)
+	if !ok {
+		return
+	}
+	toRoom, ok := roomOf(to)
+	if !ok {
+		return
+	}
+	w.notify(func(o Observer) { o.EntityMoved(e, fromRoom, toRoom) })
+}
+
+// GetEntityByInstanceID finds a live entity by its instance ID.
+func (w *World) GetEntityByInstanceID(id string) (*entities.Entity, bool) {
+	w.registryMu.RLock()
+	defer w.registryMu.RUnlock()
+	e, ok := w.registry[id]
+	return e, ok
+}
+
+// ResolveEntity finds an entity by instance ID, then by template ID, then by
+// the name of an online player.
+func (w *World) ResolveEntity(id string) (*entities.Entity, bool) {
+	if e, ok := w.GetEntityByInstanceID(id); ok {
+		return e, true
+	}
+	if e, ok := w.GetEntityById(id); ok {
+		return e, true
+	}
+	return w.GetPlayerEntity(id)
+}
+
+// rebuildRegistry registers everything reachable from the entity map and the
+// online players, dropping whatever was registered before.
+func (w *World) rebuildRegistry() {
+	registry := make(map[string]*entities.Entity)
+	add := func(e *entities.Entity) {
+		registry[e.ID] = e
+		e.ListenForChildren(w.childrenChanged)
+	}
+	for _, e := range w.EntitiesById() {
+		e.Walk(add)
+	}
+	for _, p := range w.OnlinePlayers() {
+		p.Entity.Walk(add)
+	}
+
+	w.registryMu.Lock()
+	w.registry = registry
+	w.registryMu.Unlock()
+}
@@ -0,0 +1,201 @@
+package world
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/player"
+	"example.com/mud/world/response"
+)
+
+// Loader compiles the world's definitions again, for a reload.
+type Loader func() (map[string]*entities.Entity, []*models.CommandDefinition, error)
+
+// ReloadSummary says what a reload changed.
+type ReloadSummary struct {
+	Changed  int // templates whose entities were brought up to date
+	Added    int // templates new to the world
+	Missing  int // templates no longer declared, left as they are until a restart
+	Commands int // commands the world declares
+}
+
+func (s ReloadSummary) String() string {
+	return fmt.Sprintf("%d changed, %d added, %d no longer declared, %d world commands", s.Changed, s.Added, s.Missing, s.Commands)
+}
+
+// SetLoader makes the world reloadable, from wherever load compiles it from.
+func (w *World) SetLoader(load Loader) {
+	w.loader = load
+}
+
+// Reload compiles the world's definitions again and brings the running world
+// up to date with them, in place. Every entity keeps where it is, what it
+// holds and its fields' values, but takes its template's name, description,
+// aliases, tags, reactions and exits, and any fields or components it was
+// missing. Players are left alone. If the definitions don't compile nothing
+// changes. Players' commands wait while the entities are brought up to date.
+func (w *World) Reload() (ReloadSummary, error) {
+	if w.loader == nil {
+		return ReloadSummary{}, fmt.Errorf("reload: the world isn't loaded from anything that can be reloaded")
+	}
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	templates, cmds, err := w.loader()
+	if err != nil {
+		return ReloadSummary{}, fmt.Errorf("reload: %w", err)
+	}
+	if _, ok := templates[w.startingRoom]; !ok {
+		return ReloadSummary{}, fmt.Errorf("reload: room '%s' does not exist in world", w.startingRoom)
+	}
+	if err := commands.Validate(cmds); err != nil {
+		return ReloadSummary{}, fmt.Errorf("reload: %w", err)
+	}
+	summary := ReloadSummary{Commands: len(cmds)}
+
+	// no one acts while their world changes under them
+	w.actMu.Lock()
+	defer w.actMu.Unlock()
+
+	players := make(map[*entities.Entity]struct{})
+	for _, p := range w.OnlinePlayers() {
+		players[p.Entity] = struct{}{}
+	}
+	byTemplate := make(map[string][]*entities.Entity)
+	w.registryMu.RLock()
+	for _, e := range w.registry {
+		if _, ok := players[e]; !ok {
+			byTemplate[e.TemplateID] = append(byTemplate[e.TemplateID], e)
+		}
+	}
+	w.registryMu.RUnlock()
+
+	w.entityMu.Lock()
+	entityMap := maps.Clone(w.entityMap)
+	var added []*entities.Entity
+	for id, template := range templates {
+		if _, ok := entityMap[id]; !ok {
+			entityMap[id] = template
+			added = append(added, template)
+			continue
+		}
+
+		changed := false
+		for _, e := range byTemplate[id] {
+			if refresh(e, template) {
+				changed = true
+			}
+		}
+		if changed {
+			summary.Changed++
+		}
+	}
+	for id := range entityMap {
+		if _, ok := templates[id]; !ok {
+			summary.Missing++
+		}
+	}
+	w.entityMap = entityMap
+	w.entityMu.Unlock()
+
+	for _, e := range added {
+		w.Register(e)
+	}
+	summary.Added = len(added)
+
+	// the commands go last, so none is ever there before what it acts on
+	if err := commands.ReplaceWorldCommands(cmds); err != nil {
+		return ReloadSummary{}, fmt.Errorf("reload: %w", err)
+	}
+	return summary, nil
+}
+
+// refresh brings e up to date with its template, and reports whether
+// anything changed.
+func refresh(e, template *entities.Entity) bool {
+	changed := false
+	set := func(field string, from, to models.Value) {
+		if !reflect.DeepEqual(from, to) {
+			if err := e.SetField(field, to); err != nil {
+				fmt.Printf("reload '%s': %v\n", e.TemplateID, err)
+				return
+			}
+			changed = true
+		}
+	}
+	set("name", models.VStr(e.Name), models.VStr(template.Name))
+	set("description", models.VStr(e.Description), models.VStr(template.Description))
+	set("aliases", models.Value{K: models.KindStringList, SL: e.Aliases}, models.Value{K: models.KindStringList, SL: slices.Clone(template.Aliases)})
+	set("tags", models.Value{K: models.KindStringList, SL: e.Tags}, models.Value{K: models.KindStringList, SL: slices.Clone(template.Tags)})
+
+	for k, v := range template.Fields {
+		if _, ok := e.Fields[k]; !ok {
+			e.Fields[k] = v
+			changed = true
+		}
+	}
+
+	rules := map[string][]*entities.Rule{}
+	if eventful, ok := entities.GetComponent[*components.Eventful](template); ok {
+		rules = maps.Clone(eventful.Rules)
+	}
+	if eventful, ok := entities.GetComponent[*components.Eventful](e); ok {
+		if !reflect.DeepEqual(eventful.Rules, rules) {
+			eventful.Rules = rules
+			changed = true
+		}
+	} else if len(rules) > 0 {
+		e.Add(&components.Eventful{Rules: rules})
+		changed = true
+	}
+
+	// reactors the world doesn't compile itself, like a game plugin's, are
+	// replaced whole
+	if reactor, ok := template.GetReactor(); ok {
+		if _, compiled := reactor.(*components.Eventful); !compiled {
+			if current, _ := e.GetReactor(); !reflect.DeepEqual(current, reactor) {
+				e.Add(reactor.Copy())
+				changed = true
+			}
+		}
+	}
+
+	if room, ok := entities.GetComponent[*components.Room](template); ok {
+		if live, ok := entities.GetComponent[*components.Room](e); ok {
+			if !maps.Equal(live.Exits(), room.Exits()) || live.MapIcon != room.MapIcon || live.MapColor != room.MapColor {
+				live.SetExits(maps.Clone(room.Exits()))
+				live.MapIcon = room.MapIcon
+				live.MapColor = room.MapColor
+				changed = true
+			}
+		}
+	}
+
+	// components the template has gained, which start out empty
+	for _, c := range template.Components() {
+		if !hasComponent(e, c.Id()) {
+			e.Add(c.Copy())
+			changed = true
+		}
+	}
+	return changed
+}
+
+func hasComponent(e *entities.Entity, id entities.ComponentType) bool {
+	return slices.ContainsFunc(e.Components(), func(c entities.Component) bool { return c.Id() == id })
+}
+
+func (w *World) reloadCommand(p *player.Player) response.Response {
+	summary, err := w.Reload()
+	if err != nil {
+		return response.Text{Value: fmt.Sprintf("The world is unchanged: %v", err)}
+	}
+	fmt.Printf("%s reloaded the world: %s\n", p.Name, summary)
+	return response.Text{Value: fmt.Sprintf("Reloaded the world: %s.", summary)}
+}
@@ -0,0 +1,180 @@
+package world
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"example.com/mud/dsl"
+	"example.com/mud/models"
+	"example.com/mud/parser/commands"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/response"
+	"example.com/mud/world/worldtest"
+)
+
+const reloadBefore = `entity Hall {
+    name is "Hall"
+    description is "A long hall."
+    aliases is ["hall"]
+    component Room {
+        exits is { "north": "Tower" }
+        children is ["Lamp"]
+    }
+}
+
+entity Tower {
+    name is "Tower"
+    description is "A tall tower."
+    aliases is ["tower"]
+    component Room {
+        exits is { "south": "Hall" }
+    }
+}
+
+entity Lamp {
+    name is "Lamp"
+    description is "A lamp."
+    aliases is ["lamp"]
+    lit is true
+    react rub {
+        then {
+            print source "Nothing happens."
+        }
+    }
+}
+`
+
+const reloadAfter = `entity Hall {
+    name is "Hall"
+    description is "A long hall, freshly swept."
+    aliases is ["hall"]
+    component Room {
+        exits is { "north": "Tower", "east": "Cellar" }
+        children is ["Lamp"]
+    }
+}
+
+entity Cellar {
+    name is "Cellar"
+    description is "A damp cellar."
+    aliases is ["cellar"]
+    component Room {
+        exits is { "west": "Hall" }
+    }
+}
+
+entity Lamp {
+    name is "Brass Lamp"
+    description is "A lamp."
+    aliases is ["lamp"]
+    lit is true
+    fuel is 3
+    react rub {
+        then {
+            print source "A genie appears."
+        }
+    }
+}
+
+command Rub {
+    aliases is ["rub"]
+    pattern {
+        syntax is "rub {target}"
+    }
+}
+`
+
+func TestWorld_Reload(t *testing.T) {
+	t.Parallel()
+	registerOnce.Do(func() { require.NoError(t, commands.RegisterBuiltInCommands()) })
+
+	dir := t.TempDir()
+	write := func(world string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "world.mud"), []byte(world), 0o644))
+	}
+	load := func() (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+		entityMap, commands, err := dsl.LoadEntitiesFromDirectory(dir)
+		if err == nil {
+			entityMap["Player"] = worldtest.Player()
+		}
+		return entityMap, commands, err
+	}
+
+	write(reloadBefore)
+	entityMap, _, err := load()
+	require.NoError(t, err)
+	w := NewWorld(entityMap, "Hall")
+	w.SetLoader(load)
+
+	outbox := NewOutbox(OutboxOptions{}, nil)
+	p, err := w.AddPlayer("Alice", outbox)
+	require.NoError(t, err)
+	hall := entityMap["Hall"]
+	room, _ := entities.GetComponent[*components.Room](hall)
+	lamp := room.GetChildren().GetChildrenByAlias("lamp")[0].Entity
+	require.NoError(t, lamp.SetField("lit", models.VBool(false)))
+
+	w.SetAdmins([]string{"Alice"})
+	resp, err := w.Parse(p, "reload")
+	require.NoError(t, err)
+	require.Equal(t, response.Text{Value: "Reloaded the world: 0 changed, 0 added, 0 no longer declared, 0 world commands."}, resp, "nothing changed yet")
+
+	// the reload waits for anyone in the middle of acting on the world
+	write(reloadAfter)
+	acting, release := make(chan struct{}), make(chan struct{})
+	go w.Act(func() {
+		close(acting)
+		<-release
+	})
+	<-acting
+	var summary ReloadSummary
+	reloaded := make(chan struct{})
+	go func() {
+		defer close(reloaded)
+		summary, err = w.Reload()
+	}()
+	select {
+	case <-reloaded:
+		t.Fatal("the reload didn't wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.Equal(t, "Lamp", lamp.Name)
+	close(release)
+	<-reloaded
+	require.NoError(t, err)
+	require.Equal(t, ReloadSummary{Changed: 2, Added: 1, Missing: 1, Commands: 1}, summary)
+
+	hallNow, _ := w.GetEntityById("Hall")
+	require.Same(t, hall, hallNow, "rooms are changed in place")
+	require.Equal(t, "A long hall, freshly swept.", hall.Description)
+	require.Equal(t, map[string]string{"north": "Tower", "east": "Cellar"}, room.Exits())
+	require.Same(t, hall, p.CurrentRoom())
+	_, ok := w.GetEntityById("Cellar")
+	require.True(t, ok)
+	_, ok = w.GetEntityById("Tower")
+	require.True(t, ok, "rooms no longer declared stay until a restart")
+
+	require.Equal(t, "Brass Lamp", lamp.Name)
+	require.Equal(t, models.VBool(false), lamp.Fields["lit"], "fields keep their values")
+	require.Equal(t, models.VInt(3), lamp.Fields["fuel"])
+	outbox.Take()
+	_, err = p.ActUponAlias("rub", "lamp", "")
+	require.NoError(t, err)
+	require.Contains(t, outbox.Take(), response.Narrative("A genie appears."), "reactions are replaced")
+	_, ok = commands.CanonicalVerb("rub")
+	require.True(t, ok)
+	require.Equal(t, "Alice", p.Name)
+
+	w.SetLoader(func() (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+		return nil, nil, errors.New("syntax error")
+	})
+	_, err = w.Reload()
+	require.ErrorContains(t, err, "syntax error")
+	require.Equal(t, "Brass Lamp", lamp.Name, "a failed reload changes nothing")
+}
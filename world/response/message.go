@@ -0,0 +1,62 @@
+package response
+
+// Message kinds. Messages are what the world sends a player unprompted,
+// through its bus, as opposed to the Response to their own command.
+const (
+	MessageNarrative        = "narrative"         // something happening around the player
+	MessageRoomChanged      = "room-changed"      // what's in or out of the player's room changed
+	MessageInventoryChanged = "inventory-changed" // what the player carries changed
+	MessageChannel          = "channel"           // a line on a chat channel, named by Channel
+	MessageSystem           = "system"            // from the server rather than the world
+)
+
+// Message is the envelope carried by the world's bus. Change messages have no
+// text; each client looks up the player's own view of what changed. Data
+// holds anything structured the sender wants to pass along.
+type Message struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (m Message) Panel() string {
+	switch m.Kind {
+	case MessageRoomChanged:
+		return PanelRoom
+	case MessageInventoryChanged:
+		return PanelInventory
+	}
+	return PanelMain
+}
+
+// HasText reports whether the message is something to show the player, rather
+// than a change to look up.
+func (m Message) HasText() bool {
+	return m.Kind != MessageRoomChanged && m.Kind != MessageInventoryChanged
+}
+
+// Narrative is a message describing something happening in the world.
+func Narrative(text string) Message {
+	return Message{Kind: MessageNarrative, Text: text}
+}
+
+// System is a message from the server itself.
+func System(text string) Message {
+	return Message{Kind: MessageSystem, Text: text}
+}
+
+// ChannelLine is a line said on a chat channel.
+func ChannelLine(channel, text string) Message {
+	return Message{Kind: MessageChannel, Channel: channel, Text: text}
+}
+
+// RoomChanged tells the players in a room to look at it again.
+func RoomChanged() Message {
+	return Message{Kind: MessageRoomChanged}
+}
+
+// InventoryChanged tells a player to look at their inventory again.
+func InventoryChanged() Message {
+	return Message{Kind: MessageInventoryChanged}
+}
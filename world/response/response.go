@@ -10,6 +10,7 @@ const (
 	PanelRoom      = "room"
 	PanelMap       = "map"
 	PanelInventory = "inventory"
+	PanelSession   = "session"
 )
 
 // Response is the result type of world.Parse.
@@ -31,12 +32,15 @@ type ChildSummary struct {
 }
 
 // RoomDescription is returned by look (no target), move, and the opening message.
+// ID and the values of ExitIDs are room template IDs, for clients that map.
 type RoomDescription struct {
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Exits       []string       `json:"exits"`
-	Children    []ChildSummary `json:"children"`
-	ToPanel     string         `json:"panel"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Exits       []string          `json:"exits"`
+	ExitIDs     map[string]string `json:"exitIds"`
+	Children    []ChildSummary    `json:"children"`
+	ToPanel     string            `json:"panel"`
 }
 
 func (RoomDescription) Panel() string { return PanelRoom }
@@ -84,3 +88,20 @@ type Text struct {
 }
 
 func (Text) Panel() string { return PanelMain }
+
+// Prompt asks the player for a line of input, such as a name or password.
+// Secret input should not be shown as it's typed.
+type Prompt struct {
+	Value  string `json:"text"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+func (Prompt) Panel() string { return PanelMain }
+
+// Resume carries a token the client can log in with to pick the same player
+// back up, if its connection drops.
+type Resume struct {
+	Token string `json:"token"`
+}
+
+func (Resume) Panel() string { return PanelSession }
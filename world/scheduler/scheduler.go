@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"container/heap"
+	"sort"
 	"sync"
 	"time"
 )
@@ -9,6 +10,10 @@ import (
 type Job struct {
 	NextRun time.Time
 	RunFunc func()
+
+	// Meta describes the job to whoever saves the scheduler's state. The
+	// scheduler itself never looks at it.
+	Meta any
 }
 
 type Scheduler struct {
@@ -40,6 +45,41 @@ func (s *Scheduler) Add(job *Job) {
 	} // wake the loop upon jobs updating
 }
 
+// Pending returns the jobs that haven't run yet, earliest first.
+func (s *Scheduler) Pending() []*Job {
+	s.mu.Lock()
+	jobs := append([]*Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].NextRun.Before(jobs[j].NextRun) })
+	return jobs
+}
+
+// Remove drops every pending job that match reports true for.
+func (s *Scheduler) Remove(match func(j *Job) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.jobs[:0]
+	removed := 0
+	for _, j := range s.jobs {
+		if match(j) {
+			removed++
+			continue
+		}
+		kept = append(kept, j)
+	}
+	clear(s.jobs[len(kept):])
+	s.jobs = kept
+	heap.Init(&s.jobs)
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return removed
+}
+
 func (s *Scheduler) run() {
 	for {
 		s.mu.Lock()
@@ -0,0 +1,226 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/actions"
+	"example.com/mud/world/entities/components"
+	"example.com/mud/world/persist"
+	"example.com/mud/world/response"
+	"example.com/mud/world/scheduler"
+)
+
+// SetSnapshotStore enables saving and restoring snapshots of the whole world.
+func (w *World) SetSnapshotStore(store *persist.SnapshotStore) {
+	w.snapshotStore = store
+}
+
+// StartSnapshots snapshots the world on the given interval, so a crash loses
+// at most one interval of changes.
+func (w *World) StartSnapshots(interval time.Duration) {
+	var schedule func(next time.Time)
+
+	schedule = func(next time.Time) {
+		w.Scheduler.Add(&scheduler.Job{
+			NextRun: next,
+			RunFunc: func() {
+				if _, err := w.TakeSnapshot(); err != nil {
+					fmt.Println(err)
+				}
+				schedule(next.Add(interval))
+			},
+		})
+	}
+
+	schedule(time.Now().Add(interval))
+}
+
+// TakeSnapshot saves every entity outside of a player, along with the pending
+// jobs of keyed scheduled actions, and returns the snapshot's name. Players are
+// saved by the player store instead.
+func (w *World) TakeSnapshot() (string, error) {
+	if w.snapshotStore == nil {
+		return "", fmt.Errorf("take snapshot: snapshots are not enabled")
+	}
+
+	players := make(map[*entities.Entity]struct{})
+	for _, p := range w.OnlinePlayers() {
+		players[p.Entity] = struct{}{}
+	}
+
+	snapshot := persist.CaptureWorld(w.EntitiesById(), func(e *entities.Entity) bool {
+		_, ok := players[e]
+		return ok
+	})
+
+	idOf := func(e *entities.Entity) string {
+		if e == nil {
+			return ""
+		}
+		return e.ID
+	}
+
+	for _, job := range w.Scheduler.Pending() {
+		pending, ok := job.Meta.(*actions.PendingJob)
+		if !ok {
+			continue
+		}
+		ev := pending.Event
+		snapshot.Jobs = append(snapshot.Jobs, &persist.JobState{
+			Key:     pending.Key,
+			NextRun: job.NextRun,
+			Event: &persist.EventState{
+				Type:         ev.Type,
+				Message:      ev.Message,
+				RoomID:       idOf(ev.Room),
+				SourceID:     idOf(ev.Source),
+				TargetID:     idOf(ev.Target),
+				InstrumentID: idOf(ev.Instrument),
+			},
+		})
+	}
+
+	name, err := w.snapshotStore.Save(snapshot)
+	if err != nil {
+		return "", fmt.Errorf("take snapshot: %w", err)
+	}
+	return name, nil
+}
+
+// RestoreLatestSnapshot puts the world back in the state of the newest
+// snapshot. It is a no-op if there are none.
+func (w *World) RestoreLatestSnapshot() (string, error) {
+	if w.snapshotStore == nil {
+		return "", nil
+	}
+
+	name, err := w.snapshotStore.Latest()
+	if errors.Is(err, persist.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("restore latest snapshot: %w", err)
+	}
+	return name, w.RestoreSnapshot(name)
+}
+
+// RestoreSnapshot replaces the world's entities with the ones in the named
+// snapshot and reschedules its pending jobs. Online players stay online and
+// are moved to the restored copy of the room they were in.
+func (w *World) RestoreSnapshot(name string) error {
+	if w.snapshotStore == nil {
+		return fmt.Errorf("restore snapshot: snapshots are not enabled")
+	}
+
+	snapshot, err := w.snapshotStore.Load(name)
+	if err != nil {
+		return fmt.Errorf("restore snapshot '%s': %w", name, err)
+	}
+
+	restored, err := persist.RestoreWorld(snapshot, w.EntitiesById())
+	if err != nil {
+		return fmt.Errorf("restore snapshot '%s': %w", name, err)
+	}
+
+	w.entityMu.Lock()
+	w.entityMap = restored
+	w.entityMu.Unlock()
+
+	// jobs from the replaced world would act on entities that no longer exist
+	w.Scheduler.Remove(func(j *scheduler.Job) bool {
+		_, ok := j.Meta.(*actions.PendingJob)
+		return ok
+	})
+
+	w.relocatePlayers()
+	w.rebuildRegistry()
+
+	resolve := func(id string) *entities.Entity {
+		e, _ := w.GetEntityByInstanceID(id)
+		return e
+	}
+
+	for _, js := range snapshot.Jobs {
+		a, ok := actions.LookupResumable(js.Key)
+		if !ok {
+			fmt.Printf("restore snapshot '%s': dropping job for unknown action '%s'\n", name, js.Key)
+			continue
+		}
+
+		ev := w.NewEvent(js.Event.Type, resolve(js.Event.RoomID), resolve(js.Event.SourceID), resolve(js.Event.TargetID))
+		ev.Instrument = resolve(js.Event.InstrumentID)
+		ev.Message = js.Event.Message
+		a.Resume(ev, js.NextRun)
+	}
+
+	return nil
+}
+
+// relocatePlayers moves online players into the current entity map's copy of
+// their room, or the starting room if it's gone.
+func (w *World) relocatePlayers() {
+	w.movesMu.Lock()
+	defer w.movesMu.Unlock()
+	entityMap := w.EntitiesById()
+
+	for _, p := range w.OnlinePlayers() {
+		if room, ok := entities.GetComponent[*components.Room](p.CurrentRoom()); ok {
+			room.RemoveChild(p.Entity)
+		}
+
+		newRoom, ok := entityMap[p.CurrentRoom().TemplateID]
+		if !ok {
+			newRoom = entityMap[w.startingRoom]
+		}
+		p.SetCurrentRoom(newRoom)
+
+		if room, ok := entities.GetComponent[*components.Room](newRoom); ok {
+			room.AddChild(p.Entity)
+		}
+		w.bus.Move(newRoom, p.Entity)
+	}
+}
+
+func (w *World) snapshotCommand() (response.Response, error) {
+	name, err := w.TakeSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return response.Text{Value: fmt.Sprintf("Saved snapshot %s.", name)}, nil
+}
+
+// rollbackCommand restores the named snapshot, or the newest one if no name is
+// given.
+func (w *World) rollbackCommand(name string) (response.Response, error) {
+	if w.snapshotStore == nil {
+		return response.Text{Value: "Snapshots are not enabled."}, nil
+	}
+
+	names, err := w.snapshotStore.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return response.Text{Value: "There are no snapshots to roll back to."}, nil
+	}
+	if name == "" {
+		name = names[len(names)-1]
+	}
+
+	if err := w.RestoreSnapshot(name); errors.Is(err, persist.ErrNotFound) {
+		return response.Text{Value: fmt.Sprintf("No snapshot called %s. Snapshots: %s", name, strings.Join(names, ", "))}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, p := range w.OnlinePlayers() {
+		w.PublishMessageTo(p.CurrentRoom(), p.Entity, response.System("The world shimmers and settles into an earlier shape."))
+		w.PublishMessageTo(p.CurrentRoom(), p.Entity, response.RoomChanged())
+		w.PublishMessageTo(p.CurrentRoom(), p.Entity, response.InventoryChanged())
+	}
+	return response.Text{Value: fmt.Sprintf("Rolled back to snapshot %s.", name)}, nil
+}
@@ -3,12 +3,16 @@ package world
 import (
 	"fmt"
 	"log"
+	"slices"
 	"strings"
+	"sync"
 
+	"example.com/mud/models"
 	"example.com/mud/parser"
 	"example.com/mud/parser/commands"
 	"example.com/mud/world/entities"
 	"example.com/mud/world/entities/components"
+	"example.com/mud/world/persist"
 	"example.com/mud/world/player"
 	"example.com/mud/world/response"
 	"example.com/mud/world/scheduler"
@@ -17,24 +21,106 @@ import (
 type World struct {
 	Scheduler *scheduler.Scheduler
 
+	entityMu     sync.RWMutex // guards swapping entityMap on rollback
 	entityMap    map[string]*entities.Entity
 	startingRoom string
 	bus          *Bus
+
+	registryMu sync.RWMutex
+	registry   map[string]*entities.Entity // instance ID -> live entity
+
+	observersMu sync.RWMutex
+	observers   []Observer
+
+	playersMu sync.RWMutex
+	players   map[string]*player.Player // lower-case name -> online player
+
+	// movesMu serializes moving players between rooms, since a player can be
+	// moved by an admin while moving themselves
+	movesMu sync.Mutex
+
+	// actMu is held for reading while a player acts on the world, and for
+	// writing while a reload changes its entities in place
+	actMu sync.RWMutex
+
+	playerStore   persist.PlayerStore    // nil disables saving players
+	snapshotStore *persist.SnapshotStore // nil disables world snapshots
+	admins        map[string]struct{}    // lower-case account names
+	operator      Operator               // nil leaves out the admin commands that need it
+	source        SourceWriter           // nil keeps builders' changes only until the server stops
+	loader        Loader                 // nil if the world can't be reloaded
+	reloadMu      sync.Mutex
+	plugin        PluginReporter // nil unless the world comes from a game plugin
+	chat          *chat
+}
+
+// Observer is notified when players come and go or entities change rooms.
+// Implementations must not block, since they are called from player goroutines.
+type Observer interface {
+	PlayerJoined(player, room *entities.Entity)
+	PlayerLeft(player, room *entities.Entity)
+	EntityMoved(entity, from, to *entities.Entity)
 }
 
 func NewWorld(entityMap map[string]*entities.Entity, startingRoom string) *World {
-	return &World{
+	w := &World{
 		entityMap:    entityMap,
 		startingRoom: startingRoom,
 		Scheduler:    scheduler.NewScheduler(),
 		bus:          NewBus(),
+		players:      make(map[string]*player.Player),
+		chat:         newChat(ChatOptions{}),
 	}
+	w.rebuildRegistry()
+	return w
 }
 
-func (w *World) EntitiesById() map[string]*entities.Entity { return w.entityMap }
+func (w *World) EntitiesById() map[string]*entities.Entity {
+	w.entityMu.RLock()
+	defer w.entityMu.RUnlock()
+	return w.entityMap
+}
+
+func (w *World) AddObserver(o Observer) {
+	w.observersMu.Lock()
+	defer w.observersMu.Unlock()
+	w.observers = append(w.observers, o)
+}
 
-func (w *World) AddPlayer(name string, inbox chan string) (*player.Player, error) {
-	startingRoom, ok := w.entityMap[w.startingRoom]
+// RemoveObserver stops notifying o.
+func (w *World) RemoveObserver(o Observer) {
+	w.observersMu.Lock()
+	defer w.observersMu.Unlock()
+	w.observers = slices.DeleteFunc(w.observers, func(other Observer) bool { return other == o })
+}
+
+func (w *World) notify(fn func(o Observer)) {
+	w.observersMu.RLock()
+	defer w.observersMu.RUnlock()
+	for _, o := range w.observers {
+		fn(o)
+	}
+}
+
+// NewEvent builds an event of the given type bound to this world, for actions
+// that run outside of a player command.
+func (w *World) NewEvent(eventType string, room, source, target *entities.Entity) *entities.Event {
+	return &entities.Event{
+		Type:         eventType,
+		Publisher:    w,
+		Scheduler:    w.Scheduler,
+		Registry:     w,
+		EntitiesById: w.EntitiesById(),
+		Room:         room,
+		Source:       source,
+		Target:       target,
+	}
+}
+
+// AddPlayer puts a new player in the starting room, or wherever they were
+// saved. Messages they hear are pushed to outbox.
+func (w *World) AddPlayer(name string, outbox *Outbox) (*player.Player, error) {
+	startingRoom, ok := w.EntitiesById()[w.startingRoom]
 	if !ok {
 		log.Fatalf("add player: room '%s' does not exist in world.", w.startingRoom)
 	}
@@ -44,36 +130,107 @@ func (w *World) AddPlayer(name string, inbox chan string) (*player.Player, error
 		return nil, fmt.Errorf("could not create player '%s': %w", name, err)
 	}
 
-	if room, ok := entities.GetComponent[*components.Room](newPlayer.CurrentRoom); ok {
-		room.AddChild(newPlayer.Entity)
+	if err := w.restorePlayer(newPlayer); err != nil {
+		// better a fresh character than no character
+		fmt.Println(err)
 	}
 
-	w.bus.Subscribe(newPlayer.CurrentRoom, newPlayer.Entity, inbox)
-	w.Publish(newPlayer.CurrentRoom, fmt.Sprintf("%s enters the room.", newPlayer.Name), []*entities.Entity{newPlayer.Entity})
+	room := newPlayer.CurrentRoom()
+	if r, ok := entities.GetComponent[*components.Room](room); ok {
+		r.AddChild(newPlayer.Entity)
+	}
+
+	w.playersMu.Lock()
+	w.players[strings.ToLower(newPlayer.Name)] = newPlayer
+	w.playersMu.Unlock()
+	w.Register(newPlayer.Entity)
+	w.chat.arrive(newPlayer.Name)
+
+	w.bus.Subscribe(room, newPlayer.Entity, outbox)
+	w.Publish(room, fmt.Sprintf("%s enters the room.", newPlayer.Name), []*entities.Entity{newPlayer.Entity})
+	w.notify(func(o Observer) { o.PlayerJoined(newPlayer.Entity, room) })
 
 	return newPlayer, nil
 }
 
 func (w *World) DisconnectPlayer(p *player.Player) {
-	if room, ok := entities.GetComponent[*components.Room](p.CurrentRoom); ok {
-		room.RemoveChild(p.Entity)
+	if err := w.savePlayer(p); err != nil {
+		fmt.Println(err)
 	}
 
-	w.bus.Unsubscribe(p.CurrentRoom, p.Entity)
-	w.Publish(p.CurrentRoom, fmt.Sprintf("%s leaves the room.", p.Name), []*entities.Entity{p.Entity})
+	room := p.CurrentRoom()
+	if r, ok := entities.GetComponent[*components.Room](room); ok {
+		r.RemoveChild(p.Entity)
+	}
+
+	w.playersMu.Lock()
+	delete(w.players, strings.ToLower(p.Name))
+	w.playersMu.Unlock()
+	w.Unregister(p.Entity)
+
+	if outbox, ok := w.bus.Outbox(p.Entity); ok {
+		if stats := outbox.Stats(); stats.Dropped > 0 || stats.SlowDisconnects > 0 {
+			fmt.Printf("outbox for %s: %s\n", p.Name, stats)
+		}
+	}
+	w.bus.Unsubscribe(room, p.Entity)
+	w.Publish(room, fmt.Sprintf("%s leaves the room.", p.Name), []*entities.Entity{p.Entity})
+	w.notify(func(o Observer) { o.PlayerLeft(p.Entity, room) })
 }
 
 func (w *World) GetEntityById(id string) (*entities.Entity, bool) {
-	entity, ok := w.entityMap[id]
+	entity, ok := w.EntitiesById()[id]
 	return entity, ok
 }
 
+// GetPlayerEntity returns the entity of the online player with the given name.
+func (w *World) GetPlayerEntity(name string) (*entities.Entity, bool) {
+	w.playersMu.RLock()
+	defer w.playersMu.RUnlock()
+	p, ok := w.players[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return p.Entity, true
+}
+
+// FindPlayer returns the online player with the given name, in any case.
+func (w *World) FindPlayer(name string) (*player.Player, bool) {
+	w.playersMu.RLock()
+	defer w.playersMu.RUnlock()
+	p, ok := w.players[strings.ToLower(name)]
+	return p, ok
+}
+
+// OnlinePlayers returns the players currently in the world.
+func (w *World) OnlinePlayers() []*player.Player {
+	w.playersMu.RLock()
+	defer w.playersMu.RUnlock()
+	players := make([]*player.Player, 0, len(w.players))
+	for _, p := range w.players {
+		players = append(players, p)
+	}
+	return players
+}
+
+// Publish narrates text to everyone in room but the excluded.
 func (w *World) Publish(room *entities.Entity, text string, exclude []*entities.Entity) {
-	w.bus.Publish(room, text, exclude)
+	w.bus.Publish(room, response.Narrative(text), exclude)
 }
 
+// PublishTo narrates text to recipient, if they're in room.
 func (w *World) PublishTo(room *entities.Entity, recipient *entities.Entity, text string) {
-	w.bus.PublishTo(room, recipient, text)
+	w.bus.PublishTo(room, recipient, response.Narrative(text))
+}
+
+// PublishMessage sends msg to everyone in room but the excluded.
+func (w *World) PublishMessage(room *entities.Entity, msg response.Message, exclude []*entities.Entity) {
+	w.bus.Publish(room, msg, exclude)
+}
+
+// PublishMessageTo sends msg to recipient, if they're in room.
+func (w *World) PublishMessageTo(room *entities.Entity, recipient *entities.Entity, msg response.Message) {
+	w.bus.PublishTo(room, recipient, msg)
 }
 
 func (w *World) GetScheduler() *scheduler.Scheduler {
@@ -86,9 +243,22 @@ func (w *World) Parse(p *player.Player, line string) (response.Response, error)
 		return response.Text{Value: "What in the nine hells?"}, nil
 	}
 
+	role := w.RoleOf(p)
+	if !role.Allows(commands.RequiredRole(cmd.Kind)) {
+		return response.Text{Value: "What in the nine hells?"}, nil
+	}
+
+	if cmd.Kind == "reload" {
+		// a reload waits for everyone's commands to finish, so it can't run
+		// as one
+		return w.reloadCommand(p), nil
+	}
+	w.actMu.RLock()
+	defer w.actMu.RUnlock()
+
 	switch cmd.Kind {
 	case "help":
-		return w.HelpMessage(cmd.Params["command"]), nil
+		return w.HelpMessage(cmd.Params["command"], role), nil
 	case "move":
 		return p.Move(cmd.Params["direction"])
 	case "look":
@@ -99,6 +269,24 @@ func (w *World) Parse(p *player.Player, line string) (response.Response, error)
 		return p.MapCommand()
 	case "track":
 		return p.Track(cmd.Params["target"])
+	case "who":
+		return w.whoCommand(p), nil
+	case "sshkey":
+		return w.sshKeyCommand(p, cmd, line), nil
+	case "say", "tell", "shout", "chat", "channels", "join", "leave", "history", "ignore", "unignore":
+		return w.chatCommand(p, cmd, line), nil
+	case "goto", "transfer", "kick", "ban", "unban", "role", "broadcast", "shutdown", "force", "stat", "purge":
+		return w.adminCommand(p, cmd, line)
+	case "dig", "redit", "oset", "ocreate", "tag", "untag", "alias", "unalias":
+		return w.buildCommand(p, cmd, line), nil
+	case "snapshot":
+		return w.snapshotCommand()
+	case "rollback":
+		return w.rollbackCommand(cmd.Params["snapshot"])
+	case "outboxes":
+		return w.outboxesCommand(), nil
+	case "pluginstats":
+		return w.pluginStatsCommand(), nil
 	}
 
 	// see if it has target
@@ -120,18 +308,20 @@ func (w *World) Parse(p *player.Player, line string) (response.Response, error)
 	return response.Text{Value: "What the hell are you talking about?"}, nil
 }
 
-func (w *World) HelpMessage(command string) response.Text {
+// HelpMessage describes a command, or with no command, every command someone
+// with the given role can use.
+func (w *World) HelpMessage(command string, role models.Role) response.Text {
 	if command == "" {
-		return w.HelpGeneral()
+		return w.HelpGeneral(role)
 	}
 
-	canonical, ok := commands.VerbAliases[command]
-	if !ok {
+	canonical, ok := commands.CanonicalVerb(command)
+	if !ok || !role.Allows(commands.RequiredRole(canonical)) {
 		return response.Text{Value: fmt.Sprintf("Unrecognized command: %s", command)}
 	}
 
 	var b strings.Builder
-	for _, p := range commands.Patterns {
+	for _, p := range commands.AllPatterns() {
 		if strings.ToLower(p.Kind) == canonical {
 			b.WriteString("- ")
 			b.WriteString(p.String())
@@ -148,9 +338,12 @@ func (w *World) HelpMessage(command string) response.Text {
 	return response.Text{Value: b.String()}
 }
 
-func (w *World) HelpGeneral() response.Text {
+func (w *World) HelpGeneral(role models.Role) response.Text {
 	var b strings.Builder
-	for _, p := range commands.Patterns {
+	for _, p := range commands.AllPatterns() {
+		if !role.Allows(commands.RequiredRole(p.Kind)) {
+			continue
+		}
 		b.WriteString("- ")
 		b.WriteString(p.String())
 
@@ -164,35 +357,56 @@ func (w *World) HelpGeneral() response.Text {
 	return response.Text{Value: b.String()}
 }
 
+// Act runs act, which acts on the world for a player outside of a command,
+// such as finishing one that was ambiguous, so a reload can't change the
+// world halfway through it.
+func (w *World) Act(act func()) {
+	w.actMu.RLock()
+	defer w.actMu.RUnlock()
+	act()
+}
+
 func (w *World) MovePlayer(p *player.Player, direction string) (response.Response, error) {
-	playerRoom, err := entities.RequireComponent[*components.Room](p.CurrentRoom)
+	playerRoom, err := entities.RequireComponent[*components.Room](p.CurrentRoom())
 	if err != nil {
 		return nil, fmt.Errorf("move for player '%s': %w", p.Name, err)
 	}
 
 	newRoom := w.getNeighboringRoom(playerRoom, direction)
 	if newRoom != nil {
-		w.Publish(p.CurrentRoom, fmt.Sprintf("%s leaves the room.", p.Name), []*entities.Entity{p.Entity})
+		w.relocate(p, newRoom, "%s leaves the room.", "%s enters the room.")
+		return p.GetRoomDescription()
+	}
 
-		playerRoom.RemoveChild(p.Entity)
-		p.CurrentRoom = newRoom
+	return response.Text{Value: "You can't go there."}, nil
+}
 
-		if room, ok := entities.GetComponent[*components.Room](p.CurrentRoom); ok {
-			room.AddChild(p.Entity)
-		}
+// relocate moves p to room, telling those they leave and join with the given
+// formats, which take the player's name.
+func (w *World) relocate(p *player.Player, room *entities.Entity, leaving, arriving string) {
+	w.movesMu.Lock()
+	defer w.movesMu.Unlock()
 
-		w.bus.Move(p.CurrentRoom, p.Entity)
-		w.Publish(p.CurrentRoom, fmt.Sprintf("%s enters the room.", p.Name), []*entities.Entity{p.Entity})
+	oldRoom := p.CurrentRoom()
+	w.Publish(oldRoom, fmt.Sprintf(leaving, p.Name), []*entities.Entity{p.Entity})
 
-		return p.GetRoomDescription()
+	if from, ok := entities.GetComponent[*components.Room](oldRoom); ok {
+		from.RemoveChild(p.Entity)
 	}
+	p.SetCurrentRoom(room)
 
-	return response.Text{Value: "You can't go there."}, nil
+	if to, ok := entities.GetComponent[*components.Room](room); ok {
+		to.AddChild(p.Entity)
+	}
+
+	w.bus.Move(room, p.Entity)
+	w.Publish(room, fmt.Sprintf(arriving, p.Name), []*entities.Entity{p.Entity})
+	w.Moved(p.Entity, oldRoom, room)
 }
 
 func (w *World) getNeighboringRoom(r *components.Room, direction string) *entities.Entity {
 	if roomId, ok := r.GetNeighboringRoomId(direction); ok {
-		room := w.entityMap[roomId]
+		room := w.EntitiesById()[roomId]
 		return room
 	}
 	return nil
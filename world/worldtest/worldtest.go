@@ -0,0 +1,50 @@
+// Package worldtest builds the small worlds that tests of the world and the
+// packages around it run against.
+package worldtest
+
+import (
+	"strings"
+
+	"example.com/mud/models"
+	"example.com/mud/world/entities"
+	"example.com/mud/world/entities/components"
+)
+
+// Room returns a room with the given exits, aliased by its lower-case ID.
+func Room(id string, exits map[string]string) *entities.Entity {
+	e := entities.NewEntity(id, "A room.", []string{strings.ToLower(id)}, []string{"room"}, map[string]models.Value{}, nil)
+	e.TemplateID = id
+	room := components.NewRoom()
+	room.SetExits(exits)
+	e.Add(room)
+	return e
+}
+
+// Thing returns something with no components, aliased by its lower-case ID.
+func Thing(id string) *entities.Entity {
+	e := entities.NewEntity(id, "A "+strings.ToLower(id)+".", []string{strings.ToLower(id)}, nil, map[string]models.Value{}, nil)
+	e.TemplateID = id
+	return e
+}
+
+// Player returns the template players are copied from.
+func Player() *entities.Entity {
+	e := entities.NewEntity("Player", "A player.", []string{"player"}, []string{"player"}, map[string]models.Value{}, nil)
+	e.TemplateID = "Player"
+	e.Add(components.NewInventory())
+	return e
+}
+
+// Entities returns a world's templates, keyed by template ID: a Hall for
+// players to start in, the Player template, and extra, which replace any of
+// those with the same ID.
+func Entities(extra ...*entities.Entity) map[string]*entities.Entity {
+	entityMap := map[string]*entities.Entity{
+		"Hall":   Room("Hall", nil),
+		"Player": Player(),
+	}
+	for _, e := range extra {
+		entityMap[e.TemplateID] = e
+	}
+	return entityMap
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example.com/mud/config"
+	"example.com/mud/dsl"
+	"example.com/mud/models"
+	orbisplugin "example.com/mud/plugin"
+	"example.com/mud/world"
+	"example.com/mud/world/entities"
+)
+
+// worldDefinition is everything the engine needs to start a world, regardless
+// of whether it came from a game plugin or from DSL files.
+type worldDefinition struct {
+	entityMap    map[string]*entities.Entity
+	startingRoom string
+	commands     []*models.CommandDefinition
+
+	// source is where builders' changes are written back to, if anywhere
+	source world.SourceWriter
+	// reload compiles the world again, if it can be
+	reload world.Loader
+
+	// gameClient and guard are set when the world comes from a plugin;
+	// gameClient is the guard, which reports on how the game is doing
+	gameClient orbisplugin.GameClient
+	guard      *orbisplugin.Guard
+	// supervisor is set when the plugin was launched by the engine
+	supervisor *orbisplugin.Supervisor
+}
+
+// loadWorldDefinition builds the world from the configured source. The returned
+// cleanup func must be called on shutdown.
+func loadWorldDefinition(src config.WorldSource, debug bool) (*worldDefinition, func(), error) {
+	var def *worldDefinition
+	cleanup := func() {}
+	var err error
+
+	switch src.Type {
+	case config.WorldSourceDSL:
+		def, err = loadDSLWorld(src)
+	case config.WorldSourcePlugin:
+		def, cleanup, err = loadPluginWorld(src, debug)
+	default:
+		err = fmt.Errorf("unknown world source '%s'", src.Type)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// validate starting room exists in entity map
+	if _, ok := def.entityMap[def.startingRoom]; !ok {
+		cleanup()
+		return nil, nil, fmt.Errorf("room '%s' does not exist in world", def.startingRoom)
+	}
+
+	return def, cleanup, nil
+}
+
+func loadDSLWorld(src config.WorldSource) (*worldDefinition, error) {
+	entityMap, cmds, err := dsl.LoadEntitiesFromDirectory(src.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("load DSL from '%s': %w", src.DataDir, err)
+	}
+
+	writer, err := dsl.NewWriter(src.DataDir, src.BuildFile)
+	if err != nil {
+		return nil, fmt.Errorf("load DSL from '%s': %w", src.DataDir, err)
+	}
+
+	return &worldDefinition{
+		entityMap:    entityMap,
+		startingRoom: src.StartingRoom,
+		commands:     cmds,
+		source:       writer,
+		reload: func() (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+			return dsl.LoadEntitiesFromDirectory(src.DataDir)
+		},
+	}, nil
+}
+
+func loadPluginWorld(src config.WorldSource, debug bool) (*worldDefinition, func(), error) {
+	var gameClient orbisplugin.GameClient
+	var supervisor *orbisplugin.Supervisor
+	var cleanup func()
+	var err error
+	if debug {
+		gameClient, cleanup, err = orbisplugin.ConnectDebug(5 * time.Second)
+	} else {
+		supervisor, err = orbisplugin.Supervise(src.GameBinary)
+		if supervisor != nil {
+			gameClient, cleanup = supervisor, supervisor.Stop
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect to game: %w", err)
+	}
+	guard := orbisplugin.NewGuard(gameClient, orbisplugin.GuardOptions{
+		Timeout:            time.Duration(src.Events.Timeout) * time.Millisecond,
+		BreakerFailures:    src.Events.BreakerFailures,
+		BreakerCooldown:    time.Duration(src.Events.BreakerCooldown) * time.Second,
+		UnavailableMessage: src.Events.UnavailableMessage,
+	})
+
+	manifest, err := gameClient.GetManifest(context.Background())
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("get game manifest: %w", err)
+	}
+
+	entityMap, cmds, err := orbisplugin.ManifestToWorld(manifest, guard)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("build world from manifest: %w", err)
+	}
+
+	def := &worldDefinition{
+		entityMap:    entityMap,
+		startingRoom: manifest.GetStartingRoom(),
+		commands:     cmds,
+		gameClient:   guard,
+		guard:        guard,
+		supervisor:   supervisor,
+	}
+	if supervisor != nil {
+		// reloading swaps in the game binary as it's been rebuilt
+		def.reload = func() (map[string]*entities.Entity, []*models.CommandDefinition, error) {
+			return supervisor.Reload(guard)
+		}
+	}
+	return def, cleanup, nil
+}